@@ -0,0 +1,22 @@
+package gkit
+
+// BlendMode is how what is drawn is combined with what is under it. The
+// source is weighed by its alpha and coverage in every mode.
+type BlendMode uint8
+
+const (
+	// BlendNormal draws the source over the destination.
+	BlendNormal BlendMode = iota
+	// BlendMultiply multiplies the colors, which darkens the destination.
+	BlendMultiply
+	// BlendScreen multiplies the inverted colors, which lightens the
+	// destination.
+	BlendScreen
+	// BlendAdditive adds the colors.
+	BlendAdditive
+	// BlendCopy replaces the destination with the source where it isn't
+	// transparent.
+	BlendCopy
+	// BlendClear erases the destination, as much as the source is opaque.
+	BlendClear
+)
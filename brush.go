@@ -0,0 +1,152 @@
+package gkit
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+type brushKind uint8
+
+const (
+	brushSolid brushKind = iota
+	brushLinear
+	brushRadial
+)
+
+// GradientStop is the color of a gradient at Offset, from 0 at its start
+// to 1 at its end.
+type GradientStop struct {
+	Offset float32
+	Color  Color
+}
+
+// Brush is what Painter fills rects, paths, text and rounded rects with: a
+// solid color or a gradient. Gradients are given in the coordinates
+// things are drawn in and pad with the colors of their ends. The zero
+// Brush is transparent.
+type Brush struct {
+	kind  brushKind
+	color Color
+	stops []GradientStop
+	// toGradient maps the drawing coordinates into the gradient's, where
+	// the offset is x for linear gradients and the distance from the
+	// origin for radial ones.
+	toGradient Transform
+}
+
+func SolidBrush(c Color) Brush {
+	return Brush{color: c}
+}
+
+// LinearGradient changes color along the line from start to end.
+func LinearGradient(start, end PointF, stops ...GradientStop) Brush {
+	d := end.Sub(start)
+	length := d.X*d.X + d.Y*d.Y
+	if length == 0 || len(stops) < 2 {
+		return solidGradient(stops)
+	}
+	return Brush{
+		kind:  brushLinear,
+		stops: sortStops(stops),
+		toGradient: Transform{
+			A: d.X / length,
+			B: -d.Y / length,
+			C: d.Y / length,
+			D: d.X / length,
+			E: -(start.X*d.X + start.Y*d.Y) / length,
+			F: (start.X*d.Y - start.Y*d.X) / length,
+		},
+	}
+}
+
+// RadialGradient changes color along every radius of the circle.
+func RadialGradient(center PointF, radius float32, stops ...GradientStop) Brush {
+	if radius <= 0 || len(stops) < 2 {
+		return solidGradient(stops)
+	}
+	return Brush{
+		kind:       brushRadial,
+		stops:      sortStops(stops),
+		toGradient: Scaling(1/radius, 1/radius).Mul(Translation(-center.X, -center.Y)),
+	}
+}
+
+// solidGradient is what a gradient without length or with less than two
+// stops draws: the color of its last stop.
+func solidGradient(stops []GradientStop) Brush {
+	if len(stops) == 0 {
+		return Brush{}
+	}
+	return SolidBrush(sortStops(stops)[len(stops)-1].Color)
+}
+
+// sortStops returns a sorted copy of stops with their offsets clamped to
+// [0, 1].
+func sortStops(stops []GradientStop) []GradientStop {
+	sorted := make([]GradientStop, len(stops))
+	copy(sorted, stops)
+	for i := range sorted {
+		sorted[i].Offset = min32(max32(sorted[i].Offset, 0), 1)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	return sorted
+}
+
+func (b Brush) IsSolid() bool {
+	return b.kind == brushSolid
+}
+
+func (b Brush) IsRadial() bool {
+	return b.kind == brushRadial
+}
+
+// Color returns the color of a solid brush.
+func (b Brush) Color() Color {
+	return b.color
+}
+
+func (b Brush) Stops() []GradientStop {
+	return b.stops
+}
+
+// GradientTransform maps the drawing coordinates into the coordinates of
+// the gradient. The offset along a linear gradient is x there, the offset
+// along a radial gradient is the distance from the origin.
+func (b Brush) GradientTransform() Transform {
+	return b.toGradient
+}
+
+// Offset returns the offset along the gradient of p, in the drawing
+// coordinates.
+func (b Brush) Offset(p PointF) float32 {
+	g := b.toGradient.Apply(p)
+	if b.kind == brushRadial {
+		return float32(math.Hypot(float64(g.X), float64(g.Y)))
+	}
+	return g.X
+}
+
+// ColorAt returns the color of the gradient at offset, interpolated
+// between the stops around it. Colors are interpolated premultiplied, so
+// that fading into transparency doesn't tint them.
+func (b Brush) ColorAt(offset float32) Color {
+	if b.kind == brushSolid {
+		return b.color
+	}
+	i := sort.Search(len(b.stops), func(i int) bool { return b.stops[i].Offset > offset })
+	if i == 0 {
+		return b.stops[0].Color
+	}
+	if i == len(b.stops) {
+		return b.stops[i-1].Color
+	}
+	from, to := b.stops[i-1], b.stops[i]
+	f := (offset - from.Offset) / (to.Offset - from.Offset)
+	mix := func(a, b uint32) uint16 {
+		return uint16(float32(a) + (float32(b)-float32(a))*f + 0.5)
+	}
+	fr, fg, fb, fa := from.Color.RGBA()
+	tr, tg, tb, ta := to.Color.RGBA()
+	return ColorOf(color.RGBA64{mix(fr, tr), mix(fg, tg), mix(fb, tb), mix(fa, ta)})
+}
@@ -0,0 +1,150 @@
+package gkit
+
+// Clip is the area painters limit drawing to, in the coordinates of their
+// root: the intersection of the rects passed to Painter.PushClip and of
+// the layers drawn, each mapped by the transform it was made under. Rects
+// mapped by transforms that rotate or skew make it a convex polygon, the
+// others keep it a rect.
+type Clip struct {
+	// Bounds is the bounding box of the clip.
+	Bounds RectF
+	// Polygon is the convex outline of the clip, nil if it is Bounds. Its
+	// vertices go clockwise on the screen, so the inside is on the right
+	// of every edge as EdgeDistance measures it.
+	Polygon []PointF
+}
+
+// ClipRect returns the clip of r.
+func ClipRect(r RectF) Clip {
+	return Clip{Bounds: r}
+}
+
+// Empty reports whether the clip has no area.
+func (c Clip) Empty() bool {
+	return c.Bounds.Empty()
+}
+
+// ClipTo returns the part of c inside r mapped by t.
+func (c Clip) ClipTo(r RectF, t Transform) Clip {
+	if t.IsAxisAligned() {
+		return c.Intersect(ClipRect(t.BoundingBox(r)))
+	}
+	rb := r.RightBottom()
+	return c.Intersect(Clip{
+		Bounds: t.BoundingBox(r),
+		Polygon: clockwise([]PointF{
+			t.Apply(r.PointF),
+			t.Apply(PointF{rb.X, r.Y}),
+			t.Apply(rb),
+			t.Apply(PointF{r.X, rb.Y}),
+		}),
+	})
+}
+
+// Intersect returns the part of c inside c2.
+func (c Clip) Intersect(c2 Clip) Clip {
+	bounds := c.Bounds.Intersect(c2.Bounds)
+	if bounds.Empty() {
+		return Clip{}
+	}
+	if c.Polygon == nil && c2.Polygon == nil {
+		return Clip{Bounds: bounds}
+	}
+	polygon := c.Polygon
+	if polygon == nil {
+		polygon = corners(bounds)
+	}
+	for _, other := range [2][]PointF{c2.Polygon, corners(bounds)} {
+		for i, a := range other {
+			b := other[(i+1)%len(other)]
+			polygon = clipPolygon(polygon, func(p PointF) float32 { return EdgeDistance(a, b, p) })
+		}
+	}
+	polygon = withoutDuplicates(polygon)
+	if len(polygon) < 3 {
+		return Clip{}
+	}
+	leftTop, rightBottom := polygon[0], polygon[0]
+	for _, p := range polygon[1:] {
+		leftTop = PointF{min32(leftTop.X, p.X), min32(leftTop.Y, p.Y)}
+		rightBottom = PointF{max32(rightBottom.X, p.X), max32(rightBottom.Y, p.Y)}
+	}
+	return Clip{Bounds: RectFromCorners(leftTop, rightBottom).Intersect(bounds), Polygon: polygon}
+}
+
+// Contains reports whether p is inside the clip.
+func (c Clip) Contains(p PointF) bool {
+	if !c.Bounds.Contains(p) {
+		return false
+	}
+	for i, a := range c.Polygon {
+		if EdgeDistance(a, c.Polygon[(i+1)%len(c.Polygon)], p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EdgeDistance returns how far p is on the right of the edge from a to b
+// on the screen, scaled by the length of the edge. It is negative on the
+// left.
+func EdgeDistance(a, b, p PointF) float32 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+func corners(r RectF) []PointF {
+	rb := r.RightBottom()
+	return []PointF{r.PointF, {rb.X, r.Y}, rb, {r.X, rb.Y}}
+}
+
+// clockwise returns the convex polygon with its vertices going clockwise
+// on the screen, reversing it if a transform has mirrored it.
+func clockwise(polygon []PointF) []PointF {
+	var area float32
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	if area < 0 {
+		for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+			polygon[i], polygon[j] = polygon[j], polygon[i]
+		}
+	}
+	return polygon
+}
+
+// withoutDuplicates removes the vertices of polygon that are within a
+// fraction of a pixel of the next ones, as cutting along edges the
+// polygon touches leaves.
+func withoutDuplicates(polygon []PointF) []PointF {
+	const epsilon = 1.0 / 1024
+	result := polygon[:0]
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if len(result) > 0 && i == len(polygon)-1 {
+			b = result[0]
+		}
+		if d := a.Sub(b); d.X*d.X+d.Y*d.Y > epsilon*epsilon {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// clipPolygon cuts the convex polygon to the part where distance is not
+// negative.
+func clipPolygon(polygon []PointF, distance func(PointF) float32) []PointF {
+	result := make([]PointF, 0, len(polygon)+1)
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		da, db := distance(a), distance(b)
+		if da >= 0 {
+			result = append(result, a)
+		}
+		if (da < 0) != (db < 0) {
+			f := da / (da - db)
+			result = append(result, PointF{a.X + (b.X-a.X)*f, a.Y + (b.Y-a.Y)*f})
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package gkit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func rectF(x, y, w, h float32) gkit.RectF {
+	return gkit.RectF{PointF: gkit.PointF{X: x, Y: y}, SizeF: gkit.SizeF{Width: w, Height: h}}
+}
+
+func TestClipToAxisAligned(t *testing.T) {
+	clip := gkit.ClipRect(rectF(0, 0, 100, 100)).
+		ClipTo(rectF(10, 10, 50, 50), gkit.Translation(20, 0).Mul(gkit.Scaling(-1, 2)))
+	if clip.Polygon != nil {
+		t.Errorf("Polygon = %v, want nil", clip.Polygon)
+	}
+	if want := rectF(0, 20, 10, 80); clip.Bounds != want {
+		t.Errorf("Bounds = %v, want %v", clip.Bounds, want)
+	}
+}
+
+func TestClipToRotated(t *testing.T) {
+	// The square of 20x20 centered at (20, 20) rotated by 45° is a
+	// diamond with corners 10√2 away from the center.
+	d := float32(10 * math.Sqrt2)
+	transform := gkit.Translation(20, 20).Mul(gkit.Rotation(math.Pi / 4)).Mul(gkit.Translation(-10, -10))
+	for _, test := range []struct {
+		name      string
+		transform gkit.Transform
+	}{
+		{"rotated", transform},
+		// Mirroring reverses the corners.
+		{"mirrored", transform.Mul(gkit.Translation(20, 0)).Mul(gkit.Scaling(-1, 1))},
+	} {
+		clip := gkit.ClipRect(rectF(0, 0, 100, 100)).ClipTo(rectF(0, 0, 20, 20), test.transform)
+		if len(clip.Polygon) != 4 {
+			t.Fatalf("%s: Polygon = %v, want 4 corners", test.name, clip.Polygon)
+		}
+		bounds := rectF(20-d, 20-d, 2*d, 2*d)
+		if !closeRects(clip.Bounds, bounds) {
+			t.Errorf("%s: Bounds = %v, want %v", test.name, clip.Bounds, bounds)
+		}
+		for _, p := range []struct {
+			point gkit.PointF
+			want  bool
+		}{
+			{gkit.PointF{X: 20, Y: 20}, true},
+			{gkit.PointF{X: 20, Y: 20 - d + 0.1}, true},
+			{gkit.PointF{X: 20 + d/2 - 0.1, Y: 20 + d/2 - 0.1}, true},
+			{gkit.PointF{X: 20 + d/2 + 0.1, Y: 20 + d/2 + 0.1}, false},
+			// Corners of the bounds.
+			{gkit.PointF{X: 20 - d + 1, Y: 20 - d + 1}, false},
+			{gkit.PointF{X: 20 + d - 1, Y: 20 + d - 1}, false},
+		} {
+			if got := clip.Contains(p.point); got != p.want {
+				t.Errorf("%s: Contains(%v) = %v, want %v", test.name, p.point, got, p.want)
+			}
+		}
+	}
+}
+
+func TestClipIntersect(t *testing.T) {
+	diamond := gkit.ClipRect(rectF(0, 0, 100, 100)).ClipTo(rectF(-10, -10, 20, 20),
+		gkit.Translation(20, 20).Mul(gkit.Rotation(math.Pi/4)))
+	d := float32(10 * math.Sqrt2)
+
+	// The right half of the diamond is a triangle.
+	half := diamond.Intersect(gkit.ClipRect(rectF(20, 0, 100, 100)))
+	if len(half.Polygon) != 3 {
+		t.Errorf("right half Polygon = %v, want 3 corners", half.Polygon)
+	}
+	if want := rectF(20, 20-d, d, 2*d); !closeRects(half.Bounds, want) {
+		t.Errorf("right half Bounds = %v, want %v", half.Bounds, want)
+	}
+	if half.Contains(gkit.PointF{X: 19, Y: 20}) || !half.Contains(gkit.PointF{X: 21, Y: 20}) {
+		t.Errorf("right half contains the wrong side")
+	}
+
+	// Rects in the corners of the bounding box miss the diamond.
+	if got := diamond.Intersect(gkit.ClipRect(rectF(0, 0, 9, 9))); !got.Empty() {
+		t.Errorf("corner Intersect = %v, want empty", got)
+	}
+	if got := gkit.ClipRect(rectF(0, 0, 9, 9)).Intersect(diamond); !got.Empty() {
+		t.Errorf("corner Intersect = %v, want empty", got)
+	}
+
+	// Rects stay rects.
+	got := gkit.ClipRect(rectF(0, 0, 10, 10)).Intersect(gkit.ClipRect(rectF(5, 5, 10, 10)))
+	if want := gkit.ClipRect(rectF(5, 5, 5, 5)); got.Bounds != want.Bounds || got.Polygon != nil {
+		t.Errorf("rect Intersect = %v, want %v", got, want)
+	}
+}
+
+func closeRects(a, b gkit.RectF) bool {
+	close := func(x, y float32) bool {
+		return math.Abs(float64(x-y)) < 1e-3
+	}
+	return close(a.X, b.X) && close(a.Y, b.Y) && close(a.Width, b.Width) && close(a.Height, b.Height)
+}
@@ -0,0 +1,8 @@
+package gkit
+
+// Clipboard gives text controls access to the system clipboard. Windows of
+// both backends implement it.
+type Clipboard interface {
+	ClipboardText() string
+	SetClipboardText(text string)
+}
@@ -1,11 +1,23 @@
 package gkit
 
+import (
+	"image/color"
+)
+
+// Color is an sRGB color with straight, not premultiplied, alpha. It
+// implements color.Color.
 type Color uint32
 
 func RGBA(r, g, b, a uint8) Color {
 	return Color(uint32(r)<<24 | uint32(g)<<16 | uint32(b)<<8 | uint32(a))
 }
 
+// ColorOf converts c, like the colors of images, into a Color.
+func ColorOf(c color.Color) Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return RGBA(n.R, n.G, n.B, n.A)
+}
+
 func (c Color) R() uint8 {
 	return uint8(c >> 24)
 }
@@ -22,11 +34,7 @@ func (c Color) A() uint8 {
 	return uint8(c)
 }
 
-func (c Color) vec4() [4]float32 {
-	return [4]float32{
-		float32(c.R()) / 256.0,
-		float32(c.G()) / 256.0,
-		float32(c.B()) / 256.0,
-		float32(c.A()) / 256.0,
-	}
+// RGBA returns the premultiplied components of c, like color.Color does.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA{c.R(), c.G(), c.B(), c.A()}.RGBA()
 }
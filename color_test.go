@@ -0,0 +1,61 @@
+package gkit_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func TestColorOf(t *testing.T) {
+	for _, test := range []struct {
+		in   color.Color
+		want gkit.Color
+	}{
+		{color.NRGBA{R: 255, G: 128, B: 0, A: 255}, gkit.RGBA(255, 128, 0, 255)},
+		{color.NRGBA{R: 255, G: 128, B: 0, A: 128}, gkit.RGBA(255, 128, 0, 128)},
+		// Premultiplied colors are divided by their alpha.
+		{color.RGBA{R: 128, G: 64, B: 0, A: 128}, gkit.RGBA(255, 127, 0, 128)},
+		{color.RGBA{R: 10, G: 20, B: 30, A: 255}, gkit.RGBA(10, 20, 30, 255)},
+		{color.RGBA64{R: 0x8000, G: 0, B: 0x8000, A: 0x8000}, gkit.RGBA(255, 0, 255, 128)},
+		{color.Gray{Y: 100}, gkit.RGBA(100, 100, 100, 255)},
+		{color.Alpha{A: 64}, gkit.RGBA(255, 255, 255, 64)},
+		{color.Transparent, gkit.RGBA(0, 0, 0, 0)},
+		{gkit.RGBA(10, 20, 30, 200), gkit.RGBA(10, 20, 30, 200)},
+	} {
+		if got := gkit.ColorOf(test.in); got != test.want {
+			t.Errorf("ColorOf(%#v) = %08x, want %08x", test.in, uint32(got), uint32(test.want))
+		}
+	}
+}
+
+func TestColorRGBA(t *testing.T) {
+	for _, c := range []gkit.Color{
+		gkit.RGBA(0, 0, 0, 0),
+		gkit.RGBA(0, 0, 0, 255),
+		gkit.RGBA(255, 255, 255, 255),
+		gkit.RGBA(255, 128, 0, 128),
+		gkit.RGBA(12, 34, 56, 78),
+		gkit.RGBA(255, 255, 255, 1),
+	} {
+		// RGBA returns premultiplied components, like color.NRGBA does.
+		r, g, b, a := c.RGBA()
+		wr, wg, wb, wa := color.NRGBA{c.R(), c.G(), c.B(), c.A()}.RGBA()
+		if r != wr || g != wg || b != wb || a != wa {
+			t.Errorf("%08x.RGBA() = %d, %d, %d, %d, want %d, %d, %d, %d",
+				uint32(c), r, g, b, a, wr, wg, wb, wa)
+		}
+		if r > a || g > a || b > a {
+			t.Errorf("%08x.RGBA() = %d, %d, %d, %d isn't premultiplied", uint32(c), r, g, b, a)
+		}
+		// Straight colors survive the round trip through premultiplied
+		// ones, except for the color of transparent ones.
+		want := c
+		if c.A() == 0 {
+			want = 0
+		}
+		if got := gkit.ColorOf(c); got != want {
+			t.Errorf("ColorOf(%08x) = %08x", uint32(c), uint32(got))
+		}
+	}
+}
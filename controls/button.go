@@ -0,0 +1,265 @@
+package controls
+
+import (
+	"image"
+
+	"github.com/alex-ac/gkit"
+)
+
+const buttonContentSpacing = 4
+
+type ButtonState uint8
+
+const (
+	ButtonNormal ButtonState = iota
+	ButtonHover
+	ButtonPressed
+	ButtonFocused
+	ButtonDisabled
+
+	buttonStateCount
+)
+
+type ButtonStyle struct {
+	Color           gkit.Color
+	BackgroundColor gkit.Color
+}
+
+// Button shows a text and/or an image and calls OnClick when it is released
+// with the mouse inside it, or when Enter or Space is pressed while it has
+// focus.
+type Button struct {
+	gkit.ViewBase
+
+	text     string
+	font     *gkit.Font
+	fontSize uint32
+	image    image.Image
+
+	styles [buttonStateCount]ButtonStyle
+
+	OnClick func()
+
+	hovered  bool
+	pressed  bool
+	disabled bool
+}
+
+var _ gkit.View = &Button{}
+var _ gkit.MouseHandler = &Button{}
+var _ gkit.HoverHandler = &Button{}
+var _ gkit.KeyHandler = &Button{}
+
+func NewButton() *Button {
+	button := &Button{}
+	button.ViewBase.View = button
+	button.SetFocusable(true)
+	button.styles = [buttonStateCount]ButtonStyle{
+		ButtonNormal:   {gkit.RGBA(0, 0, 0, 255), gkit.RGBA(224, 224, 224, 255)},
+		ButtonHover:    {gkit.RGBA(0, 0, 0, 255), gkit.RGBA(236, 236, 236, 255)},
+		ButtonPressed:  {gkit.RGBA(0, 0, 0, 255), gkit.RGBA(192, 192, 192, 255)},
+		ButtonFocused:  {gkit.RGBA(0, 0, 0, 255), gkit.RGBA(208, 224, 248, 255)},
+		ButtonDisabled: {gkit.RGBA(128, 128, 128, 255), gkit.RGBA(240, 240, 240, 255)},
+	}
+	return button
+}
+
+func (b *Button) SetText(text string) {
+	if b.text != text {
+		b.text = text
+		b.SetPrefSizeChanged()
+		b.SetNeedsRedraw()
+	}
+}
+
+func (b *Button) Text() string {
+	return b.text
+}
+
+func (b *Button) SetFont(font *gkit.Font) {
+	if b.font != font {
+		b.font = font
+		b.SetPrefSizeChanged()
+	}
+}
+
+func (b *Button) SetFontSize(size uint32) {
+	if b.fontSize != size {
+		b.fontSize = size
+		b.SetPrefSizeChanged()
+	}
+}
+
+func (b *Button) SetImage(img image.Image) {
+	b.image = img
+	b.SetPrefSizeChanged()
+	b.SetNeedsRedraw()
+}
+
+func (b *Button) Image() image.Image {
+	return b.image
+}
+
+func (b *Button) SetStyle(state ButtonState, style ButtonStyle) {
+	if b.styles[state] != style {
+		b.styles[state] = style
+		b.SetNeedsRedraw()
+	}
+}
+
+func (b *Button) Style(state ButtonState) ButtonStyle {
+	return b.styles[state]
+}
+
+// SetDisabled makes the button ignore input. A disabled button gives up
+// focus, the window moves it away on the next key event.
+func (b *Button) SetDisabled(disabled bool) {
+	if b.disabled != disabled {
+		b.disabled = disabled
+		b.pressed = false
+		b.SetFocusable(!disabled)
+		if disabled {
+			b.SetFocused(false)
+		}
+		b.SetNeedsRedraw()
+	}
+}
+
+func (b *Button) Disabled() bool {
+	return b.disabled
+}
+
+func (b *Button) State() ButtonState {
+	switch {
+	case b.disabled:
+		return ButtonDisabled
+	case b.pressed && b.hovered:
+		return ButtonPressed
+	case b.hovered:
+		return ButtonHover
+	case b.Focused():
+		return ButtonFocused
+	}
+	return ButtonNormal
+}
+
+func (b *Button) Click() {
+	if !b.disabled && b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+func (b *Button) hasText() bool {
+	return b.font != nil && b.fontSize != 0 && b.text != ""
+}
+
+func (b *Button) imageSize() gkit.Size {
+	if b.image == nil {
+		return gkit.Size{}
+	}
+	bounds := b.image.Bounds()
+	return gkit.Size{Width: uint32(bounds.Dx()), Height: uint32(bounds.Dy())}
+}
+
+func (b *Button) contentSize() gkit.Size {
+	size := b.imageSize()
+	if b.hasText() {
+		textSize := b.font.StringSize(b.fontSize, b.text)
+		if b.image != nil {
+			size.Width += buttonContentSpacing
+		}
+		size.Width += textSize.Width
+		if textSize.Height > size.Height {
+			size.Height = textSize.Height
+		}
+	}
+	return size
+}
+
+func (b *Button) Layout() {}
+func (b *Button) Update() {}
+
+func (b *Button) UpdateSizes() {
+	b.SetPrefSize(b.contentSize())
+}
+
+func (b *Button) Draw(p gkit.Painter) {
+	style := b.styles[b.State()]
+	p.SetColor(style.BackgroundColor)
+	p.DrawRect(gkit.Rect{Size: b.Size()})
+
+	bounds := b.Bounds()
+	content := b.contentSize()
+	origin := bounds.Point
+	if bounds.Width > content.Width {
+		origin.X += (bounds.Width - content.Width) / 2
+	}
+	if bounds.Height > content.Height {
+		origin.Y += (bounds.Height - content.Height) / 2
+	}
+	if b.image != nil {
+		imageSize := b.imageSize()
+		imageOrigin := origin
+		imageOrigin.Y += (content.Height - imageSize.Height) / 2
+		p.DrawImage(gkit.Rect{Point: imageOrigin, Size: imageSize}, b.image)
+		origin.X += imageSize.Width + buttonContentSpacing
+	}
+	if b.hasText() {
+		textSize := b.font.StringSize(b.fontSize, b.text)
+		origin.Y += (content.Height - textSize.Height) / 2
+		p.SetFont(b.font)
+		p.SetFontSize(b.fontSize)
+		p.SetColor(style.Color)
+		p.DrawText(origin, b.text)
+	}
+}
+
+func (b *Button) MouseEnter(e *gkit.MouseEvent) {
+	b.hovered = true
+	b.SetNeedsRedraw()
+}
+
+func (b *Button) MouseLeave(e *gkit.MouseEvent) {
+	b.hovered = false
+	b.SetNeedsRedraw()
+}
+
+func (b *Button) MouseDown(e *gkit.MouseEvent) bool {
+	if b.disabled || e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	b.pressed = true
+	b.SetNeedsRedraw()
+	return true
+}
+
+func (b *Button) MouseMove(e *gkit.MouseEvent) bool {
+	return b.pressed
+}
+
+func (b *Button) MouseUp(e *gkit.MouseEvent) bool {
+	if !b.pressed || e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	b.pressed = false
+	b.SetNeedsRedraw()
+	if e.Inside && b.Bounds().Contains(e.Local) {
+		b.Click()
+	}
+	return true
+}
+
+func (b *Button) KeyDown(e *gkit.KeyEvent) bool {
+	if b.disabled || e.Repeat {
+		return false
+	}
+	if e.Key == gkit.KeyEnter || e.Key == gkit.KeySpace {
+		b.Click()
+		return true
+	}
+	return false
+}
+
+func (b *Button) KeyUp(e *gkit.KeyEvent) bool {
+	return false
+}
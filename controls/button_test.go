@@ -0,0 +1,203 @@
+package controls
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/headless"
+)
+
+// testRoot is a plain view to place controls in at fixed frames. It fills
+// itself with color.
+type testRoot struct {
+	gkit.ViewBase
+
+	color gkit.Color
+}
+
+func newTestRoot(children ...gkit.View) *testRoot {
+	root := &testRoot{}
+	root.ViewBase.View = root
+	for _, child := range children {
+		root.AddChild(child)
+	}
+	return root
+}
+
+func (r *testRoot) Layout()      {}
+func (r *testRoot) Update()      {}
+func (r *testRoot) UpdateSizes() {}
+func (r *testRoot) Draw(p gkit.Painter) {
+	p.SetColor(r.color)
+	p.DrawRect(gkit.Rect{Size: r.Size()})
+}
+
+func newTestWindow(t *testing.T, root gkit.View) *headless.Window {
+	t.Helper()
+	window, err := headless.NewWindowSystem().Create(200, 200, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := window.(*headless.Window)
+	w.SetRoot(root)
+	return w
+}
+
+func mouse(t gkit.EventType, x, y uint32) *gkit.MouseEvent {
+	return &gkit.MouseEvent{Type: t, Position: gkit.Point{X: x, Y: y}}
+}
+
+func key(k gkit.Key) *gkit.KeyEvent {
+	return &gkit.KeyEvent{Type: gkit.KeyDown, Key: k}
+}
+
+// newTestButton returns a button at (10, 10) of 80x30 counting its clicks.
+func newTestButton(clicks *int) *Button {
+	button := NewButton()
+	button.SetFrame(gkit.Rect{Point: gkit.Point{X: 10, Y: 10}, Size: gkit.Size{Width: 80, Height: 30}})
+	button.OnClick = func() { *clicks++ }
+	return button
+}
+
+func TestButtonStates(t *testing.T) {
+	var clicks int
+	button := newTestButton(&clicks)
+	w := newTestWindow(t, newTestRoot(button))
+
+	for _, step := range []struct {
+		event gkit.Event
+		want  ButtonState
+	}{
+		{mouse(gkit.MouseMove, 150, 150), ButtonNormal},
+		{mouse(gkit.MouseMove, 20, 20), ButtonHover},
+		{mouse(gkit.MouseDown, 20, 20), ButtonPressed},
+		// Leaving the button while pressed shows that releasing won't
+		// click. Mouse down has focused it.
+		{mouse(gkit.MouseMove, 150, 150), ButtonFocused},
+		{mouse(gkit.MouseMove, 30, 20), ButtonPressed},
+		{mouse(gkit.MouseUp, 30, 20), ButtonHover},
+		{mouse(gkit.MouseMove, 150, 150), ButtonFocused},
+	} {
+		w.DispatchEvent(step.event)
+		if got := button.State(); got != step.want {
+			t.Errorf("State() after %+v = %d, want %d", step.event, got, step.want)
+		}
+	}
+	if clicks != 1 {
+		t.Errorf("clicks = %d, want 1", clicks)
+	}
+
+	button.SetDisabled(true)
+	if got := button.State(); got != ButtonDisabled {
+		t.Errorf("State() when disabled = %d, want %d", got, ButtonDisabled)
+	}
+	w.DispatchEvent(mouse(gkit.MouseMove, 20, 20))
+	w.DispatchEvent(mouse(gkit.MouseDown, 20, 20))
+	if got := button.State(); got != ButtonDisabled {
+		t.Errorf("State() after mouse down when disabled = %d, want %d", got, ButtonDisabled)
+	}
+	w.DispatchEvent(mouse(gkit.MouseUp, 20, 20))
+	if clicks != 1 {
+		t.Errorf("disabled button clicked")
+	}
+
+	button.SetDisabled(false)
+	if got := button.State(); got != ButtonHover {
+		t.Errorf("State() when enabled again = %d, want %d", got, ButtonHover)
+	}
+}
+
+func TestButtonClickOnRelease(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		up     gkit.Point
+		clicks int
+	}{
+		{"inside", gkit.Point{X: 80, Y: 35}, 1},
+		{"right", gkit.Point{X: 95, Y: 20}, 0},
+		{"below", gkit.Point{X: 20, Y: 45}, 0},
+		// Local coordinates are clamped to 0 there.
+		{"left", gkit.Point{X: 5, Y: 20}, 0},
+		{"above", gkit.Point{X: 20, Y: 5}, 0},
+	} {
+		var clicks int
+		button := newTestButton(&clicks)
+		w := newTestWindow(t, newTestRoot(button))
+		w.DispatchEvent(mouse(gkit.MouseDown, 20, 20))
+		w.DispatchEvent(mouse(gkit.MouseMove, test.up.X, test.up.Y))
+		w.DispatchEvent(mouse(gkit.MouseUp, test.up.X, test.up.Y))
+		if clicks != test.clicks {
+			t.Errorf("%s: clicks = %d, want %d", test.name, clicks, test.clicks)
+		}
+	}
+}
+
+func TestButtonClickUnderSibling(t *testing.T) {
+	var clicks int
+	button := newTestButton(&clicks)
+	// cover is on top of the right half of the button.
+	cover := newTestRoot()
+	cover.SetFrame(gkit.Rect{Point: gkit.Point{X: 50, Y: 0}, Size: gkit.Size{Width: 100, Height: 100}})
+	w := newTestWindow(t, newTestRoot(button, cover))
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 20, 20))
+	w.DispatchEvent(mouse(gkit.MouseMove, 60, 20))
+	w.DispatchEvent(mouse(gkit.MouseUp, 60, 20))
+	if clicks != 1 {
+		t.Errorf("clicks = %d after a release inside the bounds, want 1", clicks)
+	}
+}
+
+func TestButtonClickIgnoresBorders(t *testing.T) {
+	var clicks int
+	button := newTestButton(&clicks)
+	button.SetBorders(gkit.SideValues{Left: 4, Right: 4, Top: 4, Bottom: 4})
+	w := newTestWindow(t, newTestRoot(button))
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 20, 20))
+	w.DispatchEvent(mouse(gkit.MouseUp, 12, 20))
+	if clicks != 0 {
+		t.Errorf("release on the border clicked")
+	}
+}
+
+func TestButtonKeys(t *testing.T) {
+	var clicks int
+	button := newTestButton(&clicks)
+	w := newTestWindow(t, newTestRoot(button))
+
+	// Keys go to the root while the button isn't focused.
+	w.DispatchEvent(key(gkit.KeyEnter))
+	if clicks != 0 {
+		t.Fatalf("unfocused button clicked")
+	}
+
+	w.SetFocus(button)
+	if got := button.State(); got != ButtonFocused {
+		t.Errorf("State() when focused = %d, want %d", got, ButtonFocused)
+	}
+	w.DispatchEvent(key(gkit.KeyEnter))
+	w.DispatchEvent(key(gkit.KeySpace))
+	repeat := key(gkit.KeySpace)
+	repeat.Repeat = true
+	w.DispatchEvent(repeat)
+	w.DispatchEvent(key(gkit.KeyA))
+	if clicks != 2 {
+		t.Errorf("clicks = %d, want 2", clicks)
+	}
+
+	// Disabling the button takes focus away from it.
+	button.SetDisabled(true)
+	if button.Focused() {
+		t.Errorf("disabled button is focused")
+	}
+	w.DispatchEvent(key(gkit.KeyEnter))
+	if w.Focus() != nil {
+		t.Errorf("Focus() = %v after disabling, want nil", w.Focus())
+	}
+	button.SetDisabled(false)
+	w.DispatchEvent(key(gkit.KeyEnter))
+	if clicks != 2 {
+		t.Errorf("clicks = %d, want 2", clicks)
+	}
+}
@@ -1,9 +1,31 @@
 package controls
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/alex-ac/gkit"
 )
 
+const ellipsis = "…"
+
+type TextAlign uint8
+
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+	AlignJustify
+)
+
+type VerticalAlign uint8
+
+const (
+	AlignTop VerticalAlign = iota
+	AlignMiddle
+	AlignBottom
+)
+
 type Label struct {
 	gkit.ViewBase
 
@@ -12,6 +34,15 @@ type Label struct {
 	fontSize        uint32
 	Color           gkit.Color
 	BackgroundColor gkit.Color
+
+	wrap     TextWrap
+	maxLines int
+	ellipsis bool
+	align    TextAlign
+	vAlign   VerticalAlign
+
+	// layoutWidth is the width the preferred height was computed for.
+	layoutWidth uint32
 }
 
 var _ gkit.View = &Label{}
@@ -22,18 +53,121 @@ func NewLabel() *Label {
 	return &label
 }
 
-func (l *Label) Layout() {}
+// Layout updates the preferred height of a wrapped label for its new width
+// right away, so that its parent lays it out again in the same pass.
+func (l *Label) Layout() {
+	if l.wrap != WrapNone && l.Bounds().Width != l.layoutWidth {
+		l.UpdateSizes()
+	}
+}
+
 func (l *Label) Update() {}
 
+// lines lays the text out for width and returns the lines to show. If
+// maxLines or maxHeight cut the text, the last line is marked truncated.
+func (l *Label) lines(width, maxHeight uint32) ([]textLine, bool) {
+	runes := []rune(l.text)
+	lines := wrapLines(l.font, l.fontSize, runes, width, l.wrap)
+	limit := len(lines)
+	if l.maxLines > 0 && limit > l.maxLines {
+		limit = l.maxLines
+	}
+	if lineHeight := l.font.LineHeight(l.fontSize); maxHeight > 0 && lineHeight > 0 {
+		if fit := int(maxHeight / lineHeight); fit < limit {
+			limit = fit
+		}
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	return lines[:limit], limit < len(lines)
+}
+
+// truncate shortens text so that it fits width with an ellipsis appended.
+func (l *Label) truncate(text string, width uint32) string {
+	runes := []rune(strings.TrimRightFunc(text, unicode.IsSpace))
+	ellipsisWidth := l.font.StringSize(l.fontSize, ellipsis).Width
+	offsets := l.font.Offsets(l.fontSize, string(runes))
+	end := len(runes)
+	for end > 0 && offsets[end]+ellipsisWidth > width {
+		end--
+	}
+	return strings.TrimRightFunc(string(runes[:end]), unicode.IsSpace) + ellipsis
+}
+
 type subLayer struct {
 	*Label
 }
 
 func (s subLayer) Draw(p gkit.Painter) {
+	bounds := s.Bounds()
+	runes := []rune(s.text)
+	lines, truncated := s.lines(bounds.Width, bounds.Height)
+	lineHeight := s.font.LineHeight(s.fontSize)
+
 	p.SetFont(s.font)
 	p.SetFontSize(s.fontSize)
 	p.SetColor(s.Color)
-	p.DrawText(gkit.Point{}, s.text)
+
+	var y uint32
+	if height := uint32(len(lines)) * lineHeight; height < bounds.Height {
+		switch s.vAlign {
+		case AlignMiddle:
+			y = (bounds.Height - height) / 2
+		case AlignBottom:
+			y = bounds.Height - height
+		}
+	}
+	for i, line := range lines {
+		last := i == len(lines)-1
+		text := strings.TrimRightFunc(string(runes[line.start:line.end]), unicode.IsSpace)
+		width := s.font.StringSize(s.fontSize, text).Width
+		if s.ellipsis && last && (truncated || width > bounds.Width) {
+			text = s.truncate(text, bounds.Width)
+			width = s.font.StringSize(s.fontSize, text).Width
+		}
+		// The last line of a paragraph isn't justified.
+		endsParagraph := line.end == len(runes) || runes[line.end] == '\n'
+		if s.align == AlignJustify && !endsParagraph && !(last && truncated && s.ellipsis) {
+			s.drawJustified(p, text, y, bounds.Width)
+		} else {
+			var x uint32
+			if width < bounds.Width {
+				switch s.align {
+				case AlignCenter:
+					x = (bounds.Width - width) / 2
+				case AlignRight:
+					x = bounds.Width - width
+				}
+			}
+			p.DrawText(gkit.Point{X: x, Y: y}, text)
+		}
+		y += lineHeight
+	}
+}
+
+// drawJustified draws the words of text spread over width.
+func (s subLayer) drawJustified(p gkit.Painter, text string, y, width uint32) {
+	words := strings.Fields(text)
+	if len(words) < 2 {
+		p.DrawText(gkit.Point{Y: y}, text)
+		return
+	}
+	var wordsWidth uint32
+	widths := make([]uint32, len(words))
+	for i, word := range words {
+		widths[i] = s.font.StringSize(s.fontSize, word).Width
+		wordsWidth += widths[i]
+	}
+	var space float32
+	if width > wordsWidth {
+		space = float32(width-wordsWidth) / float32(len(words)-1)
+	}
+	x := float32(0)
+	for i, word := range words {
+		p.DrawText(gkit.Point{X: uint32(x + 0.5), Y: y}, word)
+		x += float32(widths[i]) + space
+	}
 }
 
 func (l *Label) Draw(p gkit.Painter) {
@@ -81,8 +215,84 @@ func (l *Label) UpdateSizes() {
 		l.SetMaxSize(gkit.Size{})
 		return
 	}
-	size := l.font.StringSize(l.fontSize, l.text)
-	l.SetPrefSize(size)
+	// The preferred width is the width of the text without wrapping, the
+	// preferred height is the height at the current width.
+	var width uint32
+	runes := []rune(l.text)
+	for _, line := range wrapLines(l.font, l.fontSize, runes, 0, WrapNone) {
+		lineWidth := l.font.StringSize(l.fontSize, string(runes[line.start:line.end])).Width
+		if lineWidth > width {
+			width = lineWidth
+		}
+	}
+	l.layoutWidth = l.Bounds().Width
+	height := l.HeightForWidth(l.layoutWidth)
+	if l.ellipsis || l.wrap != WrapNone {
+		l.SetMinSize(gkit.Size{Height: l.font.LineHeight(l.fontSize)}.Outset(l.Borders()))
+	} else {
+		l.SetMinSize(gkit.Size{})
+	}
+	l.SetPrefSize(gkit.Size{Width: width, Height: height})
+}
+
+// HeightForWidth returns the height the text needs when it is laid out in
+// the given content width. Zero width means no wrapping.
+func (l *Label) HeightForWidth(width uint32) uint32 {
+	if l.font == nil || l.fontSize == 0 || l.text == "" {
+		return 0
+	}
+	lines, _ := l.lines(width, 0)
+	return uint32(len(lines)) * l.font.LineHeight(l.fontSize)
+}
+
+func (l *Label) SetWrap(wrap TextWrap) {
+	if l.wrap != wrap {
+		l.wrap = wrap
+		l.SetPrefSizeChanged()
+		l.SetNeedsRedraw()
+	}
+}
+
+func (l *Label) Wrap() TextWrap {
+	return l.wrap
+}
+
+// SetMaxLines limits the number of lines shown. Zero means no limit.
+func (l *Label) SetMaxLines(lines int) {
+	if l.maxLines != lines {
+		l.maxLines = lines
+		l.SetPrefSizeChanged()
+		l.SetNeedsRedraw()
+	}
+}
+
+func (l *Label) MaxLines() int {
+	return l.maxLines
+}
+
+// SetEllipsis makes the label end the last visible line with an ellipsis
+// when the text doesn't fit.
+func (l *Label) SetEllipsis(ellipsis bool) {
+	if l.ellipsis != ellipsis {
+		l.ellipsis = ellipsis
+		l.SetPrefSizeChanged()
+		l.SetNeedsRedraw()
+	}
+}
+
+func (l *Label) Ellipsis() bool {
+	return l.ellipsis
+}
+
+func (l *Label) SetAlignment(align TextAlign, vAlign VerticalAlign) {
+	if l.align != align || l.vAlign != vAlign {
+		l.align, l.vAlign = align, vAlign
+		l.SetNeedsRedraw()
+	}
+}
+
+func (l *Label) Alignment() (TextAlign, VerticalAlign) {
+	return l.align, l.vAlign
 }
 
 func (l *Label) SetColor(color gkit.Color) {
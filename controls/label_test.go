@@ -0,0 +1,50 @@
+package controls
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/layout"
+)
+
+func testFont(t testing.TB) *gkit.Font {
+	t.Helper()
+	font, err := gkit.LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
+
+func TestLabelWrapsInOneLayoutPass(t *testing.T) {
+	label := NewLabel()
+	label.SetFont(testFont(t))
+	label.SetFontSize(16)
+	label.SetWrap(WrapWord)
+	label.SetText("The quick brown fox jumps over the lazy dog")
+	stack := layout.NewVStack()
+	stack.AddChild(label)
+	stack.SetSize(gkit.Size{Width: 100, Height: 500})
+
+	stack.PropagateUpdate()
+	stack.PropagateLayout()
+
+	lineHeight := label.font.LineHeight(16)
+	want := label.HeightForWidth(100)
+	if want <= lineHeight {
+		t.Fatalf("HeightForWidth(100) = %d, want more than one line of %d", want, lineHeight)
+	}
+	if got := label.Size(); got.Width != 100 || got.Height != want {
+		t.Errorf("Size() = %v after one pass, want {100 %d}", got, want)
+	}
+
+	// Widening the label shrinks it back in the same pass.
+	stack.SetSize(gkit.Size{Width: 1000, Height: 500})
+	stack.PropagateUpdate()
+	stack.PropagateLayout()
+	if got := label.Size(); got.Width != 1000 || got.Height != lineHeight {
+		t.Errorf("Size() = %v after widening, want {1000 %d}", got, lineHeight)
+	}
+}
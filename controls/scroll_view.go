@@ -0,0 +1,347 @@
+package controls
+
+import (
+	"github.com/alex-ac/gkit"
+)
+
+const (
+	defaultScrollbarWidth = 8
+	minThumbLength        = 16
+	scrollStep            = 20
+)
+
+// ScrollView shows a part of its content view, which can be larger than the
+// scroll view itself. The content is scrolled with the mouse wheel, by
+// dragging the scrollbar thumbs or by dragging the content.
+type ScrollView struct {
+	gkit.ViewBase
+
+	content gkit.View
+	offset  gkit.Point
+
+	horizontal bool
+	vertical   bool
+
+	ScrollbarWidth  uint32
+	ScrollbarColor  gkit.Color
+	ThumbColor      gkit.Color
+	BackgroundColor gkit.Color
+
+	dragging   bool
+	dragThumb  bool
+	dragAxis   int
+	dragStart  gkit.Point
+	dragOrigin gkit.Point
+}
+
+var _ gkit.View = &ScrollView{}
+var _ gkit.Scroller = &ScrollView{}
+
+func NewScrollView() *ScrollView {
+	scrollView := &ScrollView{
+		vertical:       true,
+		ScrollbarWidth: defaultScrollbarWidth,
+		ScrollbarColor: gkit.RGBA(0, 0, 0, 32),
+		ThumbColor:     gkit.RGBA(0, 0, 0, 128),
+	}
+	scrollView.ViewBase.View = scrollView
+	return scrollView
+}
+
+func (s *ScrollView) SetContent(content gkit.View) {
+	if s.content != nil {
+		s.DeleteChild(s.content)
+	}
+	s.content = content
+	s.offset = gkit.Point{}
+	if content != nil {
+		s.AddChild(content)
+	}
+	s.SetNeedsRedraw()
+}
+
+func (s *ScrollView) Content() gkit.View {
+	return s.content
+}
+
+// SetScrollable enables scrolling along each axis. Along an axis that
+// doesn't scroll the content is as large as the scroll view.
+func (s *ScrollView) SetScrollable(horizontal, vertical bool) {
+	if s.horizontal != horizontal || s.vertical != vertical {
+		s.horizontal, s.vertical = horizontal, vertical
+		s.SetNeedsLayout()
+	}
+}
+
+func (s *ScrollView) Scrollable() (bool, bool) {
+	return s.horizontal, s.vertical
+}
+
+func (s *ScrollView) ContentOffset() gkit.Point {
+	return s.offset
+}
+
+// SetContentOffset scrolls the content so that offset is at the top left
+// corner of the scroll view. The offset is limited to the content size.
+func (s *ScrollView) SetContentOffset(offset gkit.Point) {
+	maxOffset := s.maxOffset()
+	if offset.X > maxOffset.X {
+		offset.X = maxOffset.X
+	}
+	if offset.Y > maxOffset.Y {
+		offset.Y = maxOffset.Y
+	}
+	if s.offset != offset {
+		s.offset = offset
+		s.SetNeedsRedraw()
+	}
+}
+
+func (s *ScrollView) SetScrollbarColor(color gkit.Color) {
+	oldColor := s.ScrollbarColor
+	s.ScrollbarColor = color
+	if s.ScrollbarColor != oldColor {
+		s.SetNeedsRedraw()
+	}
+}
+
+func (s *ScrollView) SetThumbColor(color gkit.Color) {
+	oldColor := s.ThumbColor
+	s.ThumbColor = color
+	if s.ThumbColor != oldColor {
+		s.SetNeedsRedraw()
+	}
+}
+
+func (s *ScrollView) SetBackgroundColor(color gkit.Color) {
+	oldColor := s.BackgroundColor
+	s.BackgroundColor = color
+	if s.BackgroundColor != oldColor {
+		s.SetNeedsRedraw()
+	}
+}
+
+func (s *ScrollView) contentSize() gkit.Size {
+	if s.content == nil {
+		return gkit.Size{}
+	}
+	return s.content.Size()
+}
+
+func (s *ScrollView) maxOffset() gkit.Point {
+	content, viewport := s.contentSize(), s.Bounds().Size
+	var offset gkit.Point
+	if content.Width > viewport.Width {
+		offset.X = content.Width - viewport.Width
+	}
+	if content.Height > viewport.Height {
+		offset.Y = content.Height - viewport.Height
+	}
+	return offset
+}
+
+func (s *ScrollView) Update() {}
+
+func (s *ScrollView) UpdateSizes() {
+	if s.content == nil {
+		s.SetPrefSize(gkit.Size{})
+		return
+	}
+	s.SetPrefSize(s.content.PrefSize())
+	s.SetNeedsLayout()
+}
+
+func (s *ScrollView) Layout() {
+	if s.content == nil {
+		return
+	}
+	bounds := s.Bounds()
+	size := bounds.Size
+	pref := s.content.PrefSize()
+	if s.horizontal && pref.Width > size.Width {
+		size.Width = pref.Width
+	}
+	if s.vertical && pref.Height > size.Height {
+		size.Height = pref.Height
+	}
+	s.content.SetFrame(gkit.Rect{Point: bounds.Point, Size: size})
+	s.SetContentOffset(s.offset)
+}
+
+func (s *ScrollView) Draw(p gkit.Painter) {
+	p.SetColor(s.BackgroundColor)
+	p.DrawRect(gkit.Rect{Size: s.Size()})
+}
+
+// scrolledContent draws the children of a ScrollView inside the scrolled
+// layer.
+type scrolledContent struct {
+	*ScrollView
+}
+
+func (c scrolledContent) Draw(p gkit.Painter) {}
+
+func (c scrolledContent) PropagateDraw(p gkit.Painter) {
+	c.ViewBase.PropagateDraw(p)
+}
+
+func (s *ScrollView) PropagateDraw(p gkit.Painter) {
+	p.DrawScrolledLayer(s.Bounds(), s.offset, scrolledContent{s})
+
+	for axis := 0; axis < 2; axis++ {
+		track, thumb, ok := s.scrollbar(axis)
+		if !ok {
+			continue
+		}
+		p.SetColor(s.ScrollbarColor)
+		p.DrawRect(track)
+		p.SetColor(s.ThumbColor)
+		p.DrawRect(thumb)
+	}
+}
+
+// scrollbars reports whether the horizontal and the vertical scrollbar are
+// shown: the content can be scrolled along their axis and the scroll view
+// is wide enough for them.
+func (s *ScrollView) scrollbars() (bool, bool) {
+	bounds := s.Bounds()
+	content := s.contentSize()
+	width := s.ScrollbarWidth
+	return content.Width > bounds.Width && bounds.Height >= width,
+		content.Height > bounds.Height && bounds.Width >= width
+}
+
+// scrollbar returns the track and thumb rects of the horizontal (axis 0) or
+// vertical (axis 1) scrollbar, if it is shown. When both are, the tracks
+// leave out the corner between them.
+func (s *ScrollView) scrollbar(axis int) (gkit.Rect, gkit.Rect, bool) {
+	bounds := s.Bounds()
+	content := s.contentSize()
+	width := s.ScrollbarWidth
+	horizontal, vertical := s.scrollbars()
+	var track, thumb gkit.Rect
+	if axis == 0 {
+		if !horizontal {
+			return track, thumb, false
+		}
+		track = gkit.Rect{
+			Point: gkit.Point{X: bounds.X, Y: bounds.Y + bounds.Height - width},
+			Size:  gkit.Size{Width: bounds.Width, Height: width},
+		}
+		if vertical {
+			track.Width -= width
+		}
+		length, position := thumbGeometry(track.Width, bounds.Width, content.Width, s.offset.X)
+		thumb = gkit.Rect{
+			Point: gkit.Point{X: track.X + position, Y: track.Y},
+			Size:  gkit.Size{Width: length, Height: width},
+		}
+		return track, thumb, true
+	}
+	if !vertical {
+		return track, thumb, false
+	}
+	track = gkit.Rect{
+		Point: gkit.Point{X: bounds.X + bounds.Width - width, Y: bounds.Y},
+		Size:  gkit.Size{Width: width, Height: bounds.Height},
+	}
+	if horizontal {
+		track.Height -= width
+	}
+	length, position := thumbGeometry(track.Height, bounds.Height, content.Height, s.offset.Y)
+	thumb = gkit.Rect{
+		Point: gkit.Point{X: track.X, Y: track.Y + position},
+		Size:  gkit.Size{Width: width, Height: length},
+	}
+	return track, thumb, true
+}
+
+// thumbGeometry returns the length and the position of a thumb in a track
+// for content scrolled by offset in a viewport. Content that fits the
+// viewport gets a thumb filling the track.
+func thumbGeometry(track, viewport, content, offset uint32) (uint32, uint32) {
+	if content <= viewport {
+		return track, 0
+	}
+	length := uint32(uint64(track) * uint64(viewport) / uint64(content))
+	if length < minThumbLength {
+		length = minThumbLength
+	}
+	if length > track {
+		length = track
+	}
+	if offset > content-viewport {
+		offset = content - viewport
+	}
+	position := uint32(uint64(track-length) * uint64(offset) / uint64(content-viewport))
+	return length, position
+}
+
+func (s *ScrollView) Scroll(e *gkit.ScrollEvent) bool {
+	old := s.offset
+	s.SetContentOffset(gkit.Point{
+		X: scrollBy(s.offset.X, -e.DeltaX*scrollStep),
+		Y: scrollBy(s.offset.Y, -e.DeltaY*scrollStep),
+	})
+	return s.offset != old
+}
+
+func scrollBy(offset uint32, delta float32) uint32 {
+	result := float32(offset) + delta
+	if result < 0 {
+		return 0
+	}
+	return uint32(result)
+}
+
+func (s *ScrollView) MouseDown(e *gkit.MouseEvent) bool {
+	if e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	s.dragging = true
+	s.dragThumb = false
+	s.dragStart = e.Position
+	s.dragOrigin = s.offset
+	for axis := 0; axis < 2; axis++ {
+		if track, _, ok := s.scrollbar(axis); ok && track.Contains(e.Local) {
+			s.dragThumb = true
+			s.dragAxis = axis
+		}
+	}
+	return true
+}
+
+func (s *ScrollView) MouseMove(e *gkit.MouseEvent) bool {
+	if !s.dragging {
+		return false
+	}
+	dx := float32(e.Position.X) - float32(s.dragStart.X)
+	dy := float32(e.Position.Y) - float32(s.dragStart.Y)
+	if s.dragThumb {
+		// Moving the thumb by its free track length scrolls through the
+		// whole content.
+		track, thumb, _ := s.scrollbar(s.dragAxis)
+		maxOffset := s.maxOffset()
+		offset := s.dragOrigin
+		if s.dragAxis == 0 && track.Width > thumb.Width {
+			offset.X = scrollBy(offset.X, dx*float32(maxOffset.X)/float32(track.Width-thumb.Width))
+		} else if s.dragAxis == 1 && track.Height > thumb.Height {
+			offset.Y = scrollBy(offset.Y, dy*float32(maxOffset.Y)/float32(track.Height-thumb.Height))
+		}
+		s.SetContentOffset(offset)
+		return true
+	}
+	s.SetContentOffset(gkit.Point{
+		X: scrollBy(s.dragOrigin.X, -dx),
+		Y: scrollBy(s.dragOrigin.Y, -dy),
+	})
+	return true
+}
+
+func (s *ScrollView) MouseUp(e *gkit.MouseEvent) bool {
+	if !s.dragging || e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	s.dragging = false
+	return true
+}
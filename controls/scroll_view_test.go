@@ -0,0 +1,207 @@
+package controls
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/headless"
+)
+
+// newTestScrollView returns a scroll view of 200x200 with content of
+// 400x600 and the window showing it.
+func newTestScrollView(t *testing.T, horizontal bool) (*ScrollView, *testRoot, *headless.Window) {
+	t.Helper()
+	content := newTestRoot()
+	content.SetPrefSize(gkit.Size{Width: 400, Height: 600})
+	scrollView := NewScrollView()
+	scrollView.SetScrollable(horizontal, true)
+	scrollView.SetContent(content)
+	w := newTestWindow(t, scrollView)
+	scrollView.PropagateUpdate()
+	scrollView.PropagateLayout()
+	return scrollView, content, w
+}
+
+func TestScrollViewLayout(t *testing.T) {
+	scrollView, content, _ := newTestScrollView(t, false)
+	// The content is as wide as the scroll view along the axis that
+	// doesn't scroll.
+	if got, want := content.Size(), (gkit.Size{Width: 200, Height: 600}); got != want {
+		t.Errorf("content size = %v, want %v", got, want)
+	}
+	scrollView.SetContentOffset(gkit.Point{X: 1000, Y: 1000})
+	if got, want := scrollView.ContentOffset(), (gkit.Point{X: 0, Y: 400}); got != want {
+		t.Errorf("ContentOffset() = %v, want %v", got, want)
+	}
+
+	scrollView.SetScrollable(true, true)
+	scrollView.PropagateUpdate()
+	scrollView.PropagateLayout()
+	if got, want := content.Size(), (gkit.Size{Width: 400, Height: 600}); got != want {
+		t.Errorf("content size = %v, want %v", got, want)
+	}
+	scrollView.SetContentOffset(gkit.Point{X: 1000, Y: 1000})
+	if got, want := scrollView.ContentOffset(), (gkit.Point{X: 200, Y: 400}); got != want {
+		t.Errorf("ContentOffset() = %v, want %v", got, want)
+	}
+}
+
+func TestScrollViewWheel(t *testing.T) {
+	scrollView, _, window := newTestScrollView(t, false)
+
+	wheel := func(dy float32) *gkit.ScrollEvent {
+		return &gkit.ScrollEvent{Position: gkit.Point{X: 50, Y: 50}, DeltaY: dy}
+	}
+	// Scrolling past the start isn't consumed, so that an outer scroll
+	// view can take it.
+	if scrollView.Scroll(wheel(1)) {
+		t.Error("Scroll past the start was consumed")
+	}
+	window.DispatchEvent(wheel(-2))
+	if got := scrollView.ContentOffset(); got.Y != 2*scrollStep {
+		t.Errorf("ContentOffset() = %v, want Y %d", got, 2*scrollStep)
+	}
+	for i := 0; i < 100; i++ {
+		window.DispatchEvent(wheel(-1))
+	}
+	if got := scrollView.ContentOffset(); got.Y != 400 {
+		t.Errorf("ContentOffset() = %v after scrolling to the end, want Y 400", got)
+	}
+	window.DispatchEvent(wheel(1))
+	if got := scrollView.ContentOffset(); got.Y != 400-scrollStep {
+		t.Errorf("ContentOffset() = %v, want Y %d", got, 400-scrollStep)
+	}
+}
+
+func TestScrollViewScrollbars(t *testing.T) {
+	scrollView, _, _ := newTestScrollView(t, true)
+	width := scrollView.ScrollbarWidth
+	// The tracks leave out the corner between them.
+	for _, test := range []struct {
+		axis  int
+		track gkit.Rect
+		thumb gkit.Rect
+	}{
+		{0, rect(0, 200-width, 200-width, width), rect(0, 200-width, 96, width)},
+		{1, rect(200-width, 0, width, 200-width), rect(200-width, 0, width, 64)},
+	} {
+		track, thumb, ok := scrollView.scrollbar(test.axis)
+		if !ok || track != test.track || thumb != test.thumb {
+			t.Errorf("scrollbar(%d) = %v, %v, %v, want %v, %v", test.axis, track, thumb, ok, test.track, test.thumb)
+		}
+	}
+
+	scrollView.SetContentOffset(gkit.Point{X: 200, Y: 400})
+	if _, thumb, _ := scrollView.scrollbar(1); thumb.Y+thumb.Height != 200-width {
+		t.Errorf("vertical thumb at the end = %v, want it to end at %d", thumb, 200-width)
+	}
+}
+
+func TestThumbGeometry(t *testing.T) {
+	for _, test := range []struct {
+		track, viewport, content, offset uint32
+		length, position                 uint32
+	}{
+		{100, 100, 400, 0, 25, 0},
+		{100, 100, 400, 300, 25, 75},
+		{100, 100, 400, 150, 25, 37},
+		{100, 100, 10000, 0, minThumbLength, 0},
+		// Offsets past the end put the thumb at the end.
+		{100, 100, 400, 1000, 25, 75},
+		// Content that fits doesn't divide by zero.
+		{100, 100, 100, 0, 100, 0},
+		{100, 100, 50, 10, 100, 0},
+		{0, 100, 400, 0, 0, 0},
+	} {
+		length, position := thumbGeometry(test.track, test.viewport, test.content, test.offset)
+		if length != test.length || position != test.position {
+			t.Errorf("thumbGeometry(%d, %d, %d, %d) = %d, %d, want %d, %d",
+				test.track, test.viewport, test.content, test.offset, length, position, test.length, test.position)
+		}
+	}
+}
+
+func TestScrollViewDrag(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		from, to gkit.Point
+		want     gkit.Point
+	}{
+		// The content follows the pointer.
+		{"content", gkit.Point{X: 100, Y: 100}, gkit.Point{X: 80, Y: 70}, gkit.Point{X: 70, Y: 80}},
+		// The vertical thumb moves through its free track of 128 pixels
+		// for the 400 pixels of offset.
+		{"vertical thumb", gkit.Point{X: 196, Y: 20}, gkit.Point{X: 150, Y: 52}, gkit.Point{X: 50, Y: 150}},
+		{"horizontal thumb", gkit.Point{X: 20, Y: 196}, gkit.Point{X: 68, Y: 150}, gkit.Point{X: 150, Y: 50}},
+		// The corner belongs to neither scrollbar.
+		{"corner", gkit.Point{X: 196, Y: 196}, gkit.Point{X: 186, Y: 186}, gkit.Point{X: 60, Y: 60}},
+	} {
+		scrollView, _, w := newTestScrollView(t, true)
+		scrollView.SetContentOffset(gkit.Point{X: 50, Y: 50})
+		w.DispatchEvent(mouse(gkit.MouseDown, test.from.X, test.from.Y))
+		w.DispatchEvent(mouse(gkit.MouseMove, test.to.X, test.to.Y))
+		w.DispatchEvent(mouse(gkit.MouseUp, test.to.X, test.to.Y))
+		if got := scrollView.ContentOffset(); got != test.want {
+			t.Errorf("%s: ContentOffset() = %v, want %v", test.name, got, test.want)
+		}
+		// Moving after the release doesn't scroll.
+		w.DispatchEvent(mouse(gkit.MouseMove, 0, 0))
+		if got := scrollView.ContentOffset(); got != test.want {
+			t.Errorf("%s: ContentOffset() = %v after release, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestScrollViewClipsContent(t *testing.T) {
+	red := gkit.RGBA(255, 0, 0, 255)
+	blue := gkit.RGBA(0, 0, 255, 255)
+	white := gkit.RGBA(255, 255, 255, 255)
+	content := newTestRoot()
+	content.color = red
+	content.SetPrefSize(gkit.Size{Width: 300, Height: 300})
+	// child sticks out of the viewport at the bottom right.
+	child := newTestRoot()
+	child.color = blue
+	child.SetFrame(rect(60, 60, 100, 100))
+	content.AddChild(child)
+	scrollView := NewScrollView()
+	scrollView.SetScrollable(true, true)
+	scrollView.SetContent(content)
+	scrollView.ScrollbarWidth = 0
+	scrollView.SetFrame(rect(20, 20, 100, 100))
+	root := newTestRoot(scrollView)
+	root.color = white
+	w := newTestWindow(t, root)
+	root.PropagateUpdate()
+	root.PropagateLayout()
+	scrollView.SetContentOffset(gkit.Point{X: 50, Y: 50})
+
+	p := w.BeginPaint()
+	p.DrawLayer(gkit.Rect{Size: w.Size()}, root)
+	w.EndPaint(p)
+	img := w.Image()
+	for _, test := range []struct {
+		x, y int
+		want gkit.Color
+	}{
+		{10, 10, white},
+		{20, 20, red},
+		{29, 29, red},
+		// The child starts at 60 - 50 in the scroll view.
+		{30, 30, blue},
+		{119, 119, blue},
+		// The child and the content are clipped to the scroll view.
+		{120, 119, white},
+		{119, 120, white},
+		{150, 150, white},
+	} {
+		if got := img.At(test.x, test.y); color.NRGBAModel.Convert(got) != color.NRGBAModel.Convert(test.want) {
+			t.Errorf("pixel (%d, %d) = %v, want %v", test.x, test.y, got, color.NRGBAModel.Convert(test.want))
+		}
+	}
+}
+
+func rect(x, y, w, h uint32) gkit.Rect {
+	return gkit.Rect{Point: gkit.Point{X: x, Y: y}, Size: gkit.Size{Width: w, Height: h}}
+}
@@ -0,0 +1,516 @@
+package controls
+
+import (
+	"github.com/alex-ac/gkit"
+)
+
+// TextArea is a multi-line text editor. The text is wrapped to the width of
+// the area and scrolled vertically.
+type TextArea struct {
+	gkit.ViewBase
+
+	edit     textEdit
+	font     *gkit.Font
+	fontSize uint32
+
+	lines     []textLine
+	wrapWidth uint32
+	scroll    uint32
+	// goalX keeps the horizontal caret position while moving between lines.
+	goalX int
+
+	Color           gkit.Color
+	BackgroundColor gkit.Color
+	SelectionColor  gkit.Color
+
+	Clipboard gkit.Clipboard
+	OnChange  func(text string)
+
+	selecting bool
+}
+
+var _ gkit.View = &TextArea{}
+var _ gkit.MouseHandler = &TextArea{}
+var _ gkit.ScrollHandler = &TextArea{}
+var _ gkit.KeyHandler = &TextArea{}
+var _ gkit.TextInputHandler = &TextArea{}
+
+func NewTextArea() *TextArea {
+	area := &TextArea{
+		Color:           gkit.RGBA(0, 0, 0, 255),
+		BackgroundColor: gkit.RGBA(255, 255, 255, 255),
+		SelectionColor:  gkit.RGBA(160, 200, 255, 255),
+		// The empty text is one empty line until the first layout wraps
+		// it, so caret movement works before that.
+		lines: []textLine{{}},
+		goalX: -1,
+	}
+	area.ViewBase.View = area
+	area.SetFocusable(true)
+	return area
+}
+
+func (a *TextArea) SetText(text string) {
+	if a.edit.String() != text {
+		a.edit.setText(text)
+		a.edit.undoStack, a.edit.redoStack = nil, nil
+		a.changed(false)
+	}
+}
+
+func (a *TextArea) Text() string {
+	return a.edit.String()
+}
+
+func (a *TextArea) SetFont(font *gkit.Font) {
+	if a.font != font {
+		a.font = font
+		a.rewrap()
+	}
+}
+
+func (a *TextArea) SetFontSize(size uint32) {
+	if a.fontSize != size {
+		a.fontSize = size
+		a.rewrap()
+	}
+}
+
+func (a *TextArea) SetColor(color gkit.Color) {
+	oldColor := a.Color
+	a.Color = color
+	if a.Color != oldColor {
+		a.SetNeedsRedraw()
+	}
+}
+
+func (a *TextArea) SetBackgroundColor(color gkit.Color) {
+	oldColor := a.BackgroundColor
+	a.BackgroundColor = color
+	if a.BackgroundColor != oldColor {
+		a.SetNeedsRedraw()
+	}
+}
+
+func (a *TextArea) SetSelectionColor(color gkit.Color) {
+	oldColor := a.SelectionColor
+	a.SelectionColor = color
+	if a.SelectionColor != oldColor {
+		a.SetNeedsRedraw()
+	}
+}
+
+func (a *TextArea) Selection() (int, int) {
+	return a.edit.selection()
+}
+
+func (a *TextArea) SetSelection(start, end int) {
+	a.edit.moveTo(start, false)
+	a.edit.moveTo(end, true)
+	a.goalX = -1
+	a.scrollToCaret()
+	a.SetNeedsRedraw()
+}
+
+func (a *TextArea) CanUndo() bool {
+	return len(a.edit.undoStack) > 0
+}
+
+func (a *TextArea) CanRedo() bool {
+	return len(a.edit.redoStack) > 0
+}
+
+func (a *TextArea) Undo() {
+	if a.edit.undo() {
+		a.changed(true)
+	}
+}
+
+func (a *TextArea) Redo() {
+	if a.edit.redo() {
+		a.changed(true)
+	}
+}
+
+func (a *TextArea) hasFont() bool {
+	return a.font != nil && a.fontSize != 0
+}
+
+func (a *TextArea) lineHeight() uint32 {
+	if !a.hasFont() {
+		return 0
+	}
+	return a.font.LineHeight(a.fontSize)
+}
+
+func (a *TextArea) rewrap() {
+	if a.hasFont() {
+		a.lines = wrapLines(a.font, a.fontSize, a.edit.text, a.wrapWidth, WrapWord)
+	} else {
+		a.lines = []textLine{{0, len(a.edit.text)}}
+	}
+	a.SetPrefSizeChanged()
+	a.SetNeedsRedraw()
+}
+
+func (a *TextArea) changed(notify bool) {
+	a.rewrap()
+	a.goalX = -1
+	a.scrollToCaret()
+	if notify && a.OnChange != nil {
+		a.OnChange(a.edit.String())
+	}
+}
+
+func (a *TextArea) lineOffsets(line textLine) []uint32 {
+	if !a.hasFont() {
+		return make([]uint32, line.end-line.start+1)
+	}
+	return a.font.Offsets(a.fontSize, string(a.edit.text[line.start:line.end]))
+}
+
+func (a *TextArea) contentHeight() uint32 {
+	return uint32(len(a.lines)) * a.lineHeight()
+}
+
+func (a *TextArea) maxScroll() uint32 {
+	height, visible := a.contentHeight(), a.Bounds().Height
+	if height > visible {
+		return height - visible
+	}
+	return 0
+}
+
+func (a *TextArea) setScroll(scroll uint32) {
+	if max := a.maxScroll(); scroll > max {
+		scroll = max
+	}
+	if a.scroll != scroll {
+		a.scroll = scroll
+		a.SetNeedsRedraw()
+	}
+}
+
+// scrollToCaret scrolls vertically so that the caret line is visible.
+func (a *TextArea) scrollToCaret() {
+	lineHeight := a.lineHeight()
+	top := uint32(lineAt(a.lines, a.edit.caret)) * lineHeight
+	visible := a.Bounds().Height
+	scroll := a.scroll
+	if top < scroll {
+		scroll = top
+	} else if top+lineHeight > scroll+visible {
+		scroll = top + lineHeight - visible
+	}
+	a.setScroll(scroll)
+}
+
+func (a *TextArea) Layout() {
+	width := a.Bounds().Width
+	if width != a.wrapWidth {
+		a.wrapWidth = width
+		a.rewrap()
+	}
+	a.setScroll(a.scroll)
+}
+
+func (a *TextArea) Update() {}
+
+func (a *TextArea) UpdateSizes() {
+	lineHeight := a.lineHeight()
+	a.SetMinSize(gkit.Size{Height: lineHeight}.Outset(a.Borders()))
+	a.SetPrefSize(gkit.Size{Width: a.wrapWidth, Height: a.contentHeight()})
+}
+
+// textAreaLayer draws the scrolled lines of a TextArea.
+type textAreaLayer struct {
+	*TextArea
+}
+
+func (l textAreaLayer) Draw(p gkit.Painter) {
+	bounds := l.Bounds()
+	lineHeight := l.lineHeight()
+	first := int(l.scroll / lineHeight)
+	last := int((l.scroll + bounds.Height) / lineHeight)
+	start, end := l.edit.selection()
+	focused := l.Focused()
+
+	p.SetFont(l.font)
+	p.SetFontSize(l.fontSize)
+	for i := first; i <= last && i < len(l.lines); i++ {
+		line := l.lines[i]
+		origin := gkit.Point{X: bounds.X, Y: bounds.Y + uint32(i)*lineHeight}
+		offsets := l.lineOffsets(line)
+		if focused && start < end && start <= line.end && end >= line.start {
+			from, to := start-line.start, end-line.start
+			if from < 0 {
+				from = 0
+			}
+			if to > line.end-line.start {
+				to = line.end - line.start
+			}
+			width := offsets[to] - offsets[from]
+			if end > line.end {
+				// Show that the selection continues on the next line.
+				width += lineHeight / 4
+			}
+			p.SetColor(l.SelectionColor)
+			p.DrawRect(gkit.Rect{
+				Point: gkit.Point{X: origin.X + offsets[from], Y: origin.Y},
+				Size:  gkit.Size{Width: width, Height: lineHeight},
+			})
+		}
+		p.SetColor(l.Color)
+		p.DrawText(origin, string(l.edit.text[line.start:line.end]))
+		if focused && lineAt(l.lines, l.edit.caret) == i {
+			p.DrawRect(gkit.Rect{
+				Point: gkit.Point{X: origin.X + offsets[l.edit.caret-line.start], Y: origin.Y},
+				Size:  gkit.Size{Width: caretWidth, Height: lineHeight},
+			})
+		}
+	}
+}
+
+func (a *TextArea) Draw(p gkit.Painter) {
+	p.SetColor(a.BackgroundColor)
+	p.DrawRect(gkit.Rect{Size: a.Size()})
+	if !a.hasFont() {
+		return
+	}
+	p.DrawScrolledLayer(a.Bounds(), gkit.Point{Y: a.scroll}, textAreaLayer{a})
+}
+
+// caretX returns the horizontal position of the caret within its line.
+func (a *TextArea) caretX() int {
+	line := a.lines[lineAt(a.lines, a.edit.caret)]
+	return int(a.lineOffsets(line)[a.edit.caret-line.start])
+}
+
+// positionIn returns the position on a line closest to x.
+func (a *TextArea) positionIn(index int, x int) int {
+	line := a.lines[index]
+	if x < 0 {
+		return line.start
+	}
+	position := line.start + nearestOffset(a.lineOffsets(line), uint32(x))
+	if index+1 < len(a.lines) && position == line.end && line.end == a.lines[index+1].start && position > line.start {
+		// The end of a wrapped line is shown at the start of the next one.
+		position--
+	}
+	return position
+}
+
+// moveLines moves the caret by delta lines, keeping its horizontal
+// position.
+func (a *TextArea) moveLines(delta int, extend bool) {
+	if a.goalX < 0 {
+		a.goalX = a.caretX()
+	}
+	goalX := a.goalX
+	index := lineAt(a.lines, a.edit.caret) + delta
+	switch {
+	case index < 0:
+		a.edit.moveTo(0, extend)
+	case index >= len(a.lines):
+		a.edit.moveTo(len(a.edit.text), extend)
+	default:
+		a.edit.moveTo(a.positionIn(index, goalX), extend)
+	}
+	a.goalX = goalX
+}
+
+func (a *TextArea) indexAt(p gkit.Point) int {
+	bounds := a.Bounds()
+	y := p.Y + a.scroll
+	index := 0
+	if y > bounds.Y && a.lineHeight() > 0 {
+		index = int((y - bounds.Y) / a.lineHeight())
+	}
+	if index >= len(a.lines) {
+		return len(a.edit.text)
+	}
+	return a.positionIn(index, int(p.X)-int(bounds.X))
+}
+
+func (a *TextArea) MouseDown(e *gkit.MouseEvent) bool {
+	if e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	a.edit.moveTo(a.indexAt(e.Local), e.Modifiers&gkit.ModShift != 0)
+	a.edit.breakUndoGroup()
+	a.goalX = -1
+	a.selecting = true
+	a.scrollToCaret()
+	a.SetNeedsRedraw()
+	return true
+}
+
+func (a *TextArea) MouseMove(e *gkit.MouseEvent) bool {
+	if !a.selecting {
+		return false
+	}
+	a.edit.moveTo(a.indexAt(e.Local), true)
+	a.scrollToCaret()
+	a.SetNeedsRedraw()
+	return true
+}
+
+func (a *TextArea) MouseUp(e *gkit.MouseEvent) bool {
+	if !a.selecting || e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	a.selecting = false
+	return true
+}
+
+func (a *TextArea) Scroll(e *gkit.ScrollEvent) bool {
+	old := a.scroll
+	a.setScroll(scrollBy(a.scroll, -e.DeltaY*scrollStep))
+	return a.scroll != old
+}
+
+func (a *TextArea) Copy() {
+	if a.Clipboard != nil && a.edit.hasSelection() {
+		a.Clipboard.SetClipboardText(a.edit.selectedText())
+	}
+}
+
+func (a *TextArea) Cut() {
+	if a.Clipboard == nil || !a.edit.hasSelection() {
+		return
+	}
+	a.Clipboard.SetClipboardText(a.edit.selectedText())
+	a.edit.record(editOther)
+	a.edit.insert("")
+	a.changed(true)
+}
+
+func (a *TextArea) Paste() {
+	if a.Clipboard == nil {
+		return
+	}
+	a.insert(editOther, a.Clipboard.ClipboardText())
+}
+
+func (a *TextArea) insert(kind editKind, text string) {
+	runes := []rune(text)
+	filtered := runes[:0]
+	for _, r := range runes {
+		if r == '\n' || printable(string(r)) != "" {
+			filtered = append(filtered, r)
+		}
+	}
+	if len(filtered) == 0 && !a.edit.hasSelection() {
+		return
+	}
+	a.edit.record(kind)
+	if a.edit.insert(string(filtered)) {
+		a.changed(true)
+	}
+}
+
+func (a *TextArea) TextInput(e *gkit.TextInputEvent) bool {
+	a.insert(editTyping, e.Text)
+	return true
+}
+
+func (a *TextArea) visibleLines() int {
+	lineHeight := a.lineHeight()
+	if lineHeight == 0 {
+		return 1
+	}
+	lines := int(a.Bounds().Height / lineHeight)
+	if lines < 1 {
+		lines = 1
+	}
+	return lines
+}
+
+func (a *TextArea) KeyDown(e *gkit.KeyEvent) bool {
+	shift := e.Modifiers&gkit.ModShift != 0
+	word := e.Modifiers&(gkit.ModControl|gkit.ModAlt) != 0
+	shortcut := e.Modifiers&(gkit.ModControl|gkit.ModSuper) != 0
+	edit := &a.edit
+	line := a.lines[lineAt(a.lines, edit.caret)]
+	keepGoal := false
+	switch {
+	case e.Key == gkit.KeyArrowLeft:
+		position := edit.caret - 1
+		if word {
+			position = edit.wordStart(edit.caret)
+		} else if edit.hasSelection() && !shift {
+			position, _ = edit.selection()
+		}
+		edit.moveTo(position, shift)
+	case e.Key == gkit.KeyArrowRight:
+		position := edit.caret + 1
+		if word {
+			position = edit.wordEnd(edit.caret)
+		} else if edit.hasSelection() && !shift {
+			_, position = edit.selection()
+		}
+		edit.moveTo(position, shift)
+	case e.Key == gkit.KeyArrowUp:
+		a.moveLines(-1, shift)
+		keepGoal = true
+	case e.Key == gkit.KeyArrowDown:
+		a.moveLines(1, shift)
+		keepGoal = true
+	case e.Key == gkit.KeyPageUp:
+		a.moveLines(-a.visibleLines(), shift)
+		keepGoal = true
+	case e.Key == gkit.KeyPageDown:
+		a.moveLines(a.visibleLines(), shift)
+		keepGoal = true
+	case e.Key == gkit.KeyHome && shortcut:
+		edit.moveTo(0, shift)
+	case e.Key == gkit.KeyEnd && shortcut:
+		edit.moveTo(len(edit.text), shift)
+	case e.Key == gkit.KeyHome:
+		edit.moveTo(line.start, shift)
+	case e.Key == gkit.KeyEnd:
+		index := lineAt(a.lines, edit.caret)
+		edit.moveTo(a.positionIn(index, int(a.lineOffsets(line)[line.end-line.start])), shift)
+	case e.Key == gkit.KeyBackspace:
+		if edit.hasSelection() || edit.caret > 0 {
+			edit.record(editDeleting)
+			edit.deleteBackward(word)
+			a.changed(true)
+		}
+	case e.Key == gkit.KeyDelete:
+		if edit.hasSelection() || edit.caret < len(edit.text) {
+			edit.record(editDeleting)
+			edit.deleteForward(word)
+			a.changed(true)
+		}
+	case e.Key == gkit.KeyEnter:
+		a.insert(editOther, "\n")
+	case shortcut && e.Key == gkit.KeyZ && shift, shortcut && e.Key == gkit.KeyY:
+		a.Redo()
+	case shortcut && e.Key == gkit.KeyZ:
+		a.Undo()
+	case shortcut && e.Key == gkit.KeyA:
+		edit.selectAll()
+	case shortcut && e.Key == gkit.KeyC:
+		a.Copy()
+	case shortcut && e.Key == gkit.KeyX:
+		a.Cut()
+	case shortcut && e.Key == gkit.KeyV:
+		a.Paste()
+	default:
+		return false
+	}
+	if !keepGoal {
+		a.goalX = -1
+	}
+	if e.Key != gkit.KeyBackspace && e.Key != gkit.KeyDelete {
+		edit.breakUndoGroup()
+	}
+	a.scrollToCaret()
+	a.SetNeedsRedraw()
+	return true
+}
+
+func (a *TextArea) KeyUp(e *gkit.KeyEvent) bool {
+	return false
+}
@@ -0,0 +1,26 @@
+package controls
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func TestTextAreaKeysBeforeLayout(t *testing.T) {
+	area := NewTextArea()
+	for _, key := range []gkit.Key{
+		gkit.KeyArrowUp, gkit.KeyArrowDown, gkit.KeyHome, gkit.KeyEnd,
+		gkit.KeyPageDown, gkit.KeyBackspace, gkit.KeyDelete,
+	} {
+		area.KeyDown(&gkit.KeyEvent{Type: gkit.KeyDown, Key: key})
+	}
+	area.TextInput(&gkit.TextInputEvent{Text: "ab"})
+	area.KeyDown(&gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyEnter})
+	area.TextInput(&gkit.TextInputEvent{Text: "c"})
+	area.KeyDown(&gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyArrowUp})
+	area.KeyDown(&gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyHome})
+	area.TextInput(&gkit.TextInputEvent{Text: "d"})
+	if got, want := area.Text(), "dab\nc"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,244 @@
+package controls
+
+import (
+	"unicode"
+)
+
+// textEdit holds the text of an editable control with its caret and
+// selection. Positions are rune indices; the selection spans from anchor
+// to caret.
+type textEdit struct {
+	text      []rune
+	caret     int
+	anchor    int
+	maxLength int
+
+	undoStack []textSnapshot
+	redoStack []textSnapshot
+	lastEdit  editKind
+}
+
+const maxUndoLevels = 100
+
+type editKind uint8
+
+const (
+	editNone editKind = iota
+	editTyping
+	editDeleting
+	editOther
+)
+
+type textSnapshot struct {
+	text   []rune
+	caret  int
+	anchor int
+}
+
+func (t *textEdit) String() string {
+	return string(t.text)
+}
+
+func (t *textEdit) setText(text string) {
+	t.text = []rune(text)
+	if t.maxLength > 0 && len(t.text) > t.maxLength {
+		t.text = t.text[:t.maxLength]
+	}
+	t.caret = len(t.text)
+	t.anchor = t.caret
+}
+
+func (t *textEdit) selection() (int, int) {
+	if t.anchor < t.caret {
+		return t.anchor, t.caret
+	}
+	return t.caret, t.anchor
+}
+
+func (t *textEdit) hasSelection() bool {
+	return t.anchor != t.caret
+}
+
+func (t *textEdit) selectedText() string {
+	start, end := t.selection()
+	return string(t.text[start:end])
+}
+
+func (t *textEdit) selectAll() {
+	t.anchor = 0
+	t.caret = len(t.text)
+}
+
+// moveTo moves the caret, extending the selection if extend is set and
+// collapsing it otherwise.
+func (t *textEdit) moveTo(position int, extend bool) {
+	if position < 0 {
+		position = 0
+	}
+	if position > len(t.text) {
+		position = len(t.text)
+	}
+	t.caret = position
+	if !extend {
+		t.anchor = position
+	}
+}
+
+// insert replaces the selection with s and reports whether the text has
+// changed. Input beyond maxLength is dropped.
+func (t *textEdit) insert(s string) bool {
+	runes := []rune(s)
+	start, end := t.selection()
+	if t.maxLength > 0 {
+		room := t.maxLength - (len(t.text) - (end - start))
+		if room < 0 {
+			room = 0
+		}
+		if len(runes) > room {
+			runes = runes[:room]
+		}
+	}
+	if len(runes) == 0 && start == end {
+		return false
+	}
+	text := make([]rune, 0, len(t.text)-(end-start)+len(runes))
+	text = append(text, t.text[:start]...)
+	text = append(text, runes...)
+	text = append(text, t.text[end:]...)
+	t.text = text
+	t.caret = start + len(runes)
+	t.anchor = t.caret
+	return true
+}
+
+// deleteBackward removes the selection or the rune before the caret.
+func (t *textEdit) deleteBackward(word bool) bool {
+	if !t.hasSelection() {
+		if t.caret == 0 {
+			return false
+		}
+		if word {
+			t.anchor = t.wordStart(t.caret)
+		} else {
+			t.anchor = t.caret - 1
+		}
+	}
+	return t.insert("")
+}
+
+// deleteForward removes the selection or the rune after the caret.
+func (t *textEdit) deleteForward(word bool) bool {
+	if !t.hasSelection() {
+		if t.caret == len(t.text) {
+			return false
+		}
+		if word {
+			t.anchor = t.wordEnd(t.caret)
+		} else {
+			t.anchor = t.caret + 1
+		}
+	}
+	return t.insert("")
+}
+
+func (t *textEdit) snapshot() textSnapshot {
+	return textSnapshot{
+		text:   append([]rune(nil), t.text...),
+		caret:  t.caret,
+		anchor: t.anchor,
+	}
+}
+
+func (t *textEdit) restore(s textSnapshot) {
+	t.text = s.text
+	t.caret = s.caret
+	t.anchor = s.anchor
+}
+
+// record saves the current state for undo before an edit. Consecutive edits
+// of the same kind, like typing a word, are undone together.
+func (t *textEdit) record(kind editKind) {
+	if kind != editOther && kind == t.lastEdit {
+		return
+	}
+	t.lastEdit = kind
+	t.undoStack = append(t.undoStack, t.snapshot())
+	if len(t.undoStack) > maxUndoLevels {
+		t.undoStack = t.undoStack[1:]
+	}
+	t.redoStack = nil
+}
+
+// breakUndoGroup makes the next edit start a new undo step.
+func (t *textEdit) breakUndoGroup() {
+	t.lastEdit = editNone
+}
+
+func (t *textEdit) undo() bool {
+	if len(t.undoStack) == 0 {
+		return false
+	}
+	t.redoStack = append(t.redoStack, t.snapshot())
+	t.restore(t.undoStack[len(t.undoStack)-1])
+	t.undoStack = t.undoStack[:len(t.undoStack)-1]
+	t.lastEdit = editNone
+	return true
+}
+
+func (t *textEdit) redo() bool {
+	if len(t.redoStack) == 0 {
+		return false
+	}
+	t.undoStack = append(t.undoStack, t.snapshot())
+	t.restore(t.redoStack[len(t.redoStack)-1])
+	t.redoStack = t.redoStack[:len(t.redoStack)-1]
+	t.lastEdit = editNone
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// wordStart returns the start of the word before position.
+func (t *textEdit) wordStart(position int) int {
+	for position > 0 && !isWordRune(t.text[position-1]) {
+		position--
+	}
+	for position > 0 && isWordRune(t.text[position-1]) {
+		position--
+	}
+	return position
+}
+
+// wordEnd returns the end of the word after position.
+func (t *textEdit) wordEnd(position int) int {
+	for position < len(t.text) && !isWordRune(t.text[position]) {
+		position++
+	}
+	for position < len(t.text) && isWordRune(t.text[position]) {
+		position++
+	}
+	return position
+}
+
+// printable drops control characters from text input.
+func printable(s string) string {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		if unicode.IsPrint(r) {
+			runes = append(runes, r)
+		}
+	}
+	return string(runes)
+}
+
+// nearestOffset returns the index of the offset closest to x.
+func nearestOffset(offsets []uint32, x uint32) int {
+	for i := 1; i < len(offsets); i++ {
+		if x < (offsets[i-1]+offsets[i])/2 {
+			return i - 1
+		}
+	}
+	return len(offsets) - 1
+}
@@ -0,0 +1,241 @@
+package controls
+
+import (
+	"testing"
+)
+
+func newTextEdit(text string, anchor, caret int) *textEdit {
+	t := &textEdit{text: []rune(text)}
+	t.anchor, t.caret = anchor, caret
+	return t
+}
+
+func checkEdit(t *testing.T, name string, edit *textEdit, text string, anchor, caret int) {
+	t.Helper()
+	if edit.String() != text || edit.anchor != anchor || edit.caret != caret {
+		t.Errorf("%s: got %q with anchor %d, caret %d; want %q with anchor %d, caret %d",
+			name, edit.String(), edit.anchor, edit.caret, text, anchor, caret)
+	}
+}
+
+func TestTextEditInsert(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		text          string
+		anchor, caret int
+		maxLength     int
+		insert        string
+		changed       bool
+		want          string
+		wantCaret     int
+	}{
+		{"insert", "abc", 1, 1, 0, "X", true, "aXbc", 2},
+		{"replace selection", "hello world", 0, 5, 0, "bye", true, "bye world", 3},
+		{"replace backward selection", "hello world", 5, 0, 0, "bye", true, "bye world", 3},
+		{"runes", "héllo", 2, 2, 0, "ö", true, "héöllo", 3},
+		{"truncate at max length", "abc", 3, 3, 5, "defg", true, "abcde", 5},
+		{"full", "abcde", 2, 2, 5, "x", false, "abcde", 2},
+		{"selection makes room", "abcde", 1, 3, 5, "xyz", true, "axyde", 3},
+		{"empty", "abc", 1, 1, 0, "", false, "abc", 1},
+	} {
+		edit := newTextEdit(test.text, test.anchor, test.caret)
+		edit.maxLength = test.maxLength
+		if changed := edit.insert(test.insert); changed != test.changed {
+			t.Errorf("%s: insert() = %v, want %v", test.name, changed, test.changed)
+		}
+		checkEdit(t, test.name, edit, test.want, test.wantCaret, test.wantCaret)
+	}
+}
+
+func TestTextEditSetTextTruncates(t *testing.T) {
+	edit := &textEdit{maxLength: 3}
+	edit.setText("abcdef")
+	checkEdit(t, "setText", edit, "abc", 3, 3)
+}
+
+func TestTextEditDelete(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		text          string
+		anchor, caret int
+		forward, word bool
+		changed       bool
+		want          string
+		wantCaret     int
+	}{
+		{"backward", "abc", 2, 2, false, false, true, "ac", 1},
+		{"backward at start", "abc", 0, 0, false, false, false, "abc", 0},
+		{"backward word", "foo bar  ", 9, 9, false, true, true, "foo ", 4},
+		{"backward selection", "abcdef", 1, 4, false, false, true, "aef", 1},
+		{"backward word selection", "abc def", 5, 7, false, true, true, "abc d", 5},
+		{"forward", "abc", 1, 1, true, false, true, "ac", 1},
+		{"forward at end", "abc", 3, 3, true, false, false, "abc", 3},
+		{"forward word", "foo bar", 0, 0, true, true, true, " bar", 0},
+		{"forward selection", "abcdef", 4, 1, true, false, true, "aef", 1},
+	} {
+		edit := newTextEdit(test.text, test.anchor, test.caret)
+		var changed bool
+		if test.forward {
+			changed = edit.deleteForward(test.word)
+		} else {
+			changed = edit.deleteBackward(test.word)
+		}
+		if changed != test.changed {
+			t.Errorf("%s: changed = %v, want %v", test.name, changed, test.changed)
+		}
+		checkEdit(t, test.name, edit, test.want, test.wantCaret, test.wantCaret)
+	}
+}
+
+func TestTextEditWords(t *testing.T) {
+	edit := newTextEdit("one, two_2 three", 0, 0)
+	for _, test := range []struct{ position, start, end int }{
+		{0, 0, 3},
+		{2, 0, 3},
+		{3, 0, 10},
+		{5, 0, 10},
+		{11, 5, 16},
+		{16, 11, 16},
+	} {
+		if start := edit.wordStart(test.position); start != test.start {
+			t.Errorf("wordStart(%d) = %d, want %d", test.position, start, test.start)
+		}
+		if end := edit.wordEnd(test.position); end != test.end {
+			t.Errorf("wordEnd(%d) = %d, want %d", test.position, end, test.end)
+		}
+	}
+}
+
+func TestTextEditSelection(t *testing.T) {
+	edit := newTextEdit("abcdef", 0, 0)
+	edit.moveTo(4, false)
+	edit.moveTo(2, true)
+	if start, end := edit.selection(); start != 2 || end != 4 || edit.selectedText() != "cd" {
+		t.Errorf("selection() = %d, %d (%q), want 2, 4 (\"cd\")", start, end, edit.selectedText())
+	}
+	edit.moveTo(-3, false)
+	checkEdit(t, "moveTo(-3)", edit, "abcdef", 0, 0)
+	edit.moveTo(10, true)
+	checkEdit(t, "moveTo(10)", edit, "abcdef", 0, 6)
+	edit.moveTo(1, false)
+	if edit.hasSelection() {
+		t.Error("hasSelection() after collapsing moveTo")
+	}
+	edit.selectAll()
+	if edit.selectedText() != "abcdef" {
+		t.Errorf("selectedText() after selectAll = %q", edit.selectedText())
+	}
+}
+
+func TestPrintable(t *testing.T) {
+	if got := printable("a\tb\x00c\nd é"); got != "abcd é" {
+		t.Errorf("printable() = %q, want %q", got, "abcd é")
+	}
+}
+
+func TestNearestOffset(t *testing.T) {
+	offsets := []uint32{0, 10, 20, 30}
+	for _, test := range []struct {
+		x    uint32
+		want int
+	}{
+		{0, 0}, {4, 0}, {5, 1}, {14, 1}, {26, 3}, {100, 3},
+	} {
+		if got := nearestOffset(offsets, test.x); got != test.want {
+			t.Errorf("nearestOffset(%d) = %d, want %d", test.x, got, test.want)
+		}
+	}
+}
+
+// typeText records an undo step of kind and inserts s, like the controls
+// do.
+func typeText(edit *textEdit, kind editKind, s string) {
+	edit.record(kind)
+	edit.insert(s)
+}
+
+func TestTextEditUndoGroups(t *testing.T) {
+	edit := &textEdit{}
+	typeText(edit, editTyping, "a")
+	typeText(edit, editTyping, "b")
+	edit.record(editDeleting)
+	edit.deleteBackward(false)
+	edit.record(editDeleting)
+	edit.deleteBackward(false)
+	typeText(edit, editTyping, "c")
+	edit.breakUndoGroup()
+	typeText(edit, editTyping, "d")
+	checkEdit(t, "typed", edit, "cd", 2, 2)
+
+	for _, want := range []string{"c", "", "ab", ""} {
+		if !edit.undo() {
+			t.Fatalf("undo() = false, want %q", want)
+		}
+		if edit.String() != want {
+			t.Errorf("after undo: %q, want %q", edit.String(), want)
+		}
+	}
+	if edit.undo() {
+		t.Error("undo() = true with an empty undo stack")
+	}
+
+	for _, want := range []string{"ab", "", "c", "cd"} {
+		if !edit.redo() {
+			t.Fatalf("redo() = false, want %q", want)
+		}
+		if edit.String() != want {
+			t.Errorf("after redo: %q, want %q", edit.String(), want)
+		}
+	}
+	if edit.redo() {
+		t.Error("redo() = true with an empty redo stack")
+	}
+}
+
+func TestTextEditUndoOtherEditsSeparately(t *testing.T) {
+	edit := &textEdit{}
+	typeText(edit, editOther, "\n")
+	typeText(edit, editOther, "\n")
+	edit.undo()
+	checkEdit(t, "undo", edit, "\n", 1, 1)
+}
+
+func TestTextEditUndoRestoresSelection(t *testing.T) {
+	edit := newTextEdit("abcd", 1, 3)
+	typeText(edit, editOther, "X")
+	checkEdit(t, "replaced", edit, "aXd", 2, 2)
+	edit.undo()
+	checkEdit(t, "undo", edit, "abcd", 1, 3)
+	edit.redo()
+	checkEdit(t, "redo", edit, "aXd", 2, 2)
+}
+
+func TestTextEditEditClearsRedo(t *testing.T) {
+	edit := &textEdit{}
+	typeText(edit, editTyping, "a")
+	edit.undo()
+	typeText(edit, editTyping, "b")
+	if edit.redo() {
+		t.Errorf("redo() = true after a new edit, text %q", edit.String())
+	}
+	// Undo ends the typing group, so typing after it is a new step.
+	edit.undo()
+	checkEdit(t, "undo", edit, "", 0, 0)
+}
+
+func TestTextEditUndoLimit(t *testing.T) {
+	edit := &textEdit{}
+	for i := 0; i < maxUndoLevels+50; i++ {
+		typeText(edit, editOther, "x")
+	}
+	undone := 0
+	for edit.undo() {
+		undone++
+	}
+	if undone != maxUndoLevels {
+		t.Errorf("undid %d steps, want %d", undone, maxUndoLevels)
+	}
+	if got := len(edit.text); got != 50 {
+		t.Errorf("text length after undoing everything = %d, want 50", got)
+	}
+}
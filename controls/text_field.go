@@ -0,0 +1,414 @@
+package controls
+
+import (
+	"strings"
+
+	"github.com/alex-ac/gkit"
+)
+
+const (
+	caretWidth   = 1
+	passwordMask = "•"
+)
+
+// TextField is a single-line text input. Clipboard operations go through
+// Clipboard, usually the window the field is shown in.
+type TextField struct {
+	gkit.ViewBase
+
+	edit        textEdit
+	placeholder string
+	password    bool
+	font        *gkit.Font
+	fontSize    uint32
+	scroll      uint32
+
+	Color            gkit.Color
+	BackgroundColor  gkit.Color
+	PlaceholderColor gkit.Color
+	SelectionColor   gkit.Color
+
+	Clipboard gkit.Clipboard
+	OnChange  func(text string)
+	OnSubmit  func(text string)
+
+	selecting bool
+}
+
+var _ gkit.View = &TextField{}
+var _ gkit.MouseHandler = &TextField{}
+var _ gkit.KeyHandler = &TextField{}
+var _ gkit.TextInputHandler = &TextField{}
+
+func NewTextField() *TextField {
+	field := &TextField{
+		Color:            gkit.RGBA(0, 0, 0, 255),
+		BackgroundColor:  gkit.RGBA(255, 255, 255, 255),
+		PlaceholderColor: gkit.RGBA(128, 128, 128, 255),
+		SelectionColor:   gkit.RGBA(160, 200, 255, 255),
+	}
+	field.ViewBase.View = field
+	field.SetFocusable(true)
+	return field
+}
+
+func (f *TextField) SetText(text string) {
+	if f.edit.String() != text {
+		f.edit.setText(text)
+		f.changed(false)
+	}
+}
+
+func (f *TextField) Text() string {
+	return f.edit.String()
+}
+
+func (f *TextField) SetPlaceholder(placeholder string) {
+	if f.placeholder != placeholder {
+		f.placeholder = placeholder
+		f.SetPrefSizeChanged()
+		f.SetNeedsRedraw()
+	}
+}
+
+func (f *TextField) Placeholder() string {
+	return f.placeholder
+}
+
+// SetMaxLength limits the text to length runes. Zero means no limit.
+func (f *TextField) SetMaxLength(length int) {
+	f.edit.maxLength = length
+	if length > 0 && len(f.edit.text) > length {
+		f.edit.setText(f.edit.String())
+		f.changed(false)
+	}
+}
+
+func (f *TextField) MaxLength() int {
+	return f.edit.maxLength
+}
+
+// SetPassword masks the text and disables copying it.
+func (f *TextField) SetPassword(password bool) {
+	if f.password != password {
+		f.password = password
+		f.SetPrefSizeChanged()
+		f.SetNeedsRedraw()
+	}
+}
+
+func (f *TextField) Password() bool {
+	return f.password
+}
+
+func (f *TextField) SetFont(font *gkit.Font) {
+	if f.font != font {
+		f.font = font
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *TextField) SetFontSize(size uint32) {
+	if f.fontSize != size {
+		f.fontSize = size
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *TextField) SetColor(color gkit.Color) {
+	oldColor := f.Color
+	f.Color = color
+	if f.Color != oldColor {
+		f.SetNeedsRedraw()
+	}
+}
+
+func (f *TextField) SetBackgroundColor(color gkit.Color) {
+	oldColor := f.BackgroundColor
+	f.BackgroundColor = color
+	if f.BackgroundColor != oldColor {
+		f.SetNeedsRedraw()
+	}
+}
+
+func (f *TextField) SetPlaceholderColor(color gkit.Color) {
+	oldColor := f.PlaceholderColor
+	f.PlaceholderColor = color
+	if f.PlaceholderColor != oldColor {
+		f.SetNeedsRedraw()
+	}
+}
+
+func (f *TextField) SetSelectionColor(color gkit.Color) {
+	oldColor := f.SelectionColor
+	f.SelectionColor = color
+	if f.SelectionColor != oldColor {
+		f.SetNeedsRedraw()
+	}
+}
+
+// Selection returns the selected rune range.
+func (f *TextField) Selection() (int, int) {
+	return f.edit.selection()
+}
+
+func (f *TextField) SetSelection(start, end int) {
+	f.edit.moveTo(start, false)
+	f.edit.moveTo(end, true)
+	f.scrollToCaret()
+	f.SetNeedsRedraw()
+}
+
+// displayText is the text as it is drawn, masked in password mode.
+func (f *TextField) displayText() string {
+	if f.password {
+		return strings.Repeat(passwordMask, len(f.edit.text))
+	}
+	return f.edit.String()
+}
+
+func (f *TextField) hasFont() bool {
+	return f.font != nil && f.fontSize != 0
+}
+
+func (f *TextField) offsets() []uint32 {
+	if !f.hasFont() {
+		return make([]uint32, len(f.edit.text)+1)
+	}
+	return f.font.Offsets(f.fontSize, f.displayText())
+}
+
+func (f *TextField) changed(notify bool) {
+	f.scrollToCaret()
+	f.SetPrefSizeChanged()
+	f.SetNeedsRedraw()
+	if notify && f.OnChange != nil {
+		f.OnChange(f.edit.String())
+	}
+}
+
+// scrollToCaret scrolls the text horizontally so that the caret is visible.
+func (f *TextField) scrollToCaret() {
+	width := f.Bounds().Width
+	if width <= caretWidth {
+		f.scroll = 0
+		return
+	}
+	offsets := f.offsets()
+	caret := offsets[f.edit.caret]
+	if caret < f.scroll {
+		f.scroll = caret
+	} else if caret+caretWidth > f.scroll+width {
+		f.scroll = caret + caretWidth - width
+	}
+	textWidth := offsets[len(offsets)-1] + caretWidth
+	if textWidth < f.scroll+width {
+		f.scroll = 0
+		if textWidth > width {
+			f.scroll = textWidth - width
+		}
+	}
+}
+
+func (f *TextField) Layout() {
+	f.scrollToCaret()
+}
+
+func (f *TextField) Update() {}
+
+func (f *TextField) UpdateSizes() {
+	if !f.hasFont() {
+		f.SetPrefSize(gkit.Size{})
+		return
+	}
+	text := f.font.StringSize(f.fontSize, f.displayText())
+	placeholder := f.font.StringSize(f.fontSize, f.placeholder)
+	width := text.Width
+	if placeholder.Width > width {
+		width = placeholder.Width
+	}
+	f.SetPrefSize(gkit.Size{
+		Width:  width + caretWidth,
+		Height: f.font.LineHeight(f.fontSize),
+	})
+}
+
+// textFieldLayer draws the scrolled text of a TextField.
+type textFieldLayer struct {
+	*TextField
+}
+
+func (l textFieldLayer) Draw(p gkit.Painter) {
+	bounds := l.Bounds()
+	origin := bounds.Point
+	height := l.font.LineHeight(l.fontSize)
+	if l.edit.String() == "" {
+		if l.placeholder != "" && !l.Focused() {
+			p.SetFont(l.font)
+			p.SetFontSize(l.fontSize)
+			p.SetColor(l.PlaceholderColor)
+			p.DrawText(origin, l.placeholder)
+		}
+	} else {
+		offsets := l.offsets()
+		if l.edit.hasSelection() && l.Focused() {
+			start, end := l.edit.selection()
+			p.SetColor(l.SelectionColor)
+			p.DrawRect(gkit.Rect{
+				Point: gkit.Point{X: origin.X + offsets[start], Y: origin.Y},
+				Size:  gkit.Size{Width: offsets[end] - offsets[start], Height: height},
+			})
+		}
+		p.SetFont(l.font)
+		p.SetFontSize(l.fontSize)
+		p.SetColor(l.Color)
+		p.DrawText(origin, l.displayText())
+	}
+	if l.Focused() {
+		offsets := l.offsets()
+		p.SetColor(l.Color)
+		p.DrawRect(gkit.Rect{
+			Point: gkit.Point{X: origin.X + offsets[l.edit.caret], Y: origin.Y},
+			Size:  gkit.Size{Width: caretWidth, Height: height},
+		})
+	}
+}
+
+func (f *TextField) Draw(p gkit.Painter) {
+	p.SetColor(f.BackgroundColor)
+	p.DrawRect(gkit.Rect{Size: f.Size()})
+	if !f.hasFont() {
+		return
+	}
+	p.DrawScrolledLayer(f.Bounds(), gkit.Point{X: f.scroll}, textFieldLayer{f})
+}
+
+// indexAt returns the caret position closest to a point in the field's
+// coordinates.
+func (f *TextField) indexAt(p gkit.Point) int {
+	x := p.X + f.scroll
+	bounds := f.Bounds()
+	if x < bounds.X {
+		return 0
+	}
+	return nearestOffset(f.offsets(), x-bounds.X)
+}
+
+func (f *TextField) MouseDown(e *gkit.MouseEvent) bool {
+	if e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	f.edit.moveTo(f.indexAt(e.Local), e.Modifiers&gkit.ModShift != 0)
+	f.selecting = true
+	f.scrollToCaret()
+	f.SetNeedsRedraw()
+	return true
+}
+
+func (f *TextField) MouseMove(e *gkit.MouseEvent) bool {
+	if !f.selecting {
+		return false
+	}
+	f.edit.moveTo(f.indexAt(e.Local), true)
+	f.scrollToCaret()
+	f.SetNeedsRedraw()
+	return true
+}
+
+func (f *TextField) MouseUp(e *gkit.MouseEvent) bool {
+	if !f.selecting || e.Button != gkit.MouseButtonLeft {
+		return false
+	}
+	f.selecting = false
+	return true
+}
+
+func (f *TextField) Copy() {
+	if f.Clipboard != nil && f.edit.hasSelection() && !f.password {
+		f.Clipboard.SetClipboardText(f.edit.selectedText())
+	}
+}
+
+func (f *TextField) Cut() {
+	if f.Clipboard == nil || !f.edit.hasSelection() || f.password {
+		return
+	}
+	f.Clipboard.SetClipboardText(f.edit.selectedText())
+	if f.edit.insert("") {
+		f.changed(true)
+	}
+}
+
+func (f *TextField) Paste() {
+	if f.Clipboard == nil {
+		return
+	}
+	if f.edit.insert(printable(f.Clipboard.ClipboardText())) {
+		f.changed(true)
+	}
+}
+
+func (f *TextField) TextInput(e *gkit.TextInputEvent) bool {
+	if f.edit.insert(printable(e.Text)) {
+		f.changed(true)
+	}
+	return true
+}
+
+func (f *TextField) KeyDown(e *gkit.KeyEvent) bool {
+	shift := e.Modifiers&gkit.ModShift != 0
+	word := e.Modifiers&(gkit.ModControl|gkit.ModAlt) != 0
+	shortcut := e.Modifiers&(gkit.ModControl|gkit.ModSuper) != 0
+	edit := &f.edit
+	switch {
+	case e.Key == gkit.KeyArrowLeft:
+		position := edit.caret - 1
+		if word && !f.password {
+			position = edit.wordStart(edit.caret)
+		} else if edit.hasSelection() && !shift {
+			position, _ = edit.selection()
+		}
+		edit.moveTo(position, shift)
+	case e.Key == gkit.KeyArrowRight:
+		position := edit.caret + 1
+		if word && !f.password {
+			position = edit.wordEnd(edit.caret)
+		} else if edit.hasSelection() && !shift {
+			_, position = edit.selection()
+		}
+		edit.moveTo(position, shift)
+	case e.Key == gkit.KeyHome:
+		edit.moveTo(0, shift)
+	case e.Key == gkit.KeyEnd:
+		edit.moveTo(len(edit.text), shift)
+	case e.Key == gkit.KeyBackspace:
+		if edit.deleteBackward(word && !f.password) {
+			f.changed(true)
+		}
+	case e.Key == gkit.KeyDelete:
+		if edit.deleteForward(word && !f.password) {
+			f.changed(true)
+		}
+	case e.Key == gkit.KeyEnter:
+		if f.OnSubmit != nil {
+			f.OnSubmit(edit.String())
+		}
+	case shortcut && e.Key == gkit.KeyA:
+		edit.selectAll()
+	case shortcut && e.Key == gkit.KeyC:
+		f.Copy()
+	case shortcut && e.Key == gkit.KeyX:
+		f.Cut()
+	case shortcut && e.Key == gkit.KeyV:
+		f.Paste()
+	default:
+		return false
+	}
+	f.scrollToCaret()
+	f.SetNeedsRedraw()
+	return true
+}
+
+func (f *TextField) KeyUp(e *gkit.KeyEvent) bool {
+	return false
+}
@@ -0,0 +1,232 @@
+package controls
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/headless"
+)
+
+// newTestTextField returns a field at (10, 10) of 150x30 in a window that
+// is its clipboard, with OnChange recording the texts it reports.
+func newTestTextField(t *testing.T, changes *[]string) (*TextField, *headless.Window) {
+	t.Helper()
+	field := NewTextField()
+	field.SetFont(testFont(t))
+	field.SetFontSize(16)
+	field.SetFrame(rect(10, 10, 150, 30))
+	field.OnChange = func(text string) { *changes = append(*changes, text) }
+	root := newTestRoot(field)
+	window := newTestWindow(t, root)
+	field.Clipboard = window
+	root.PropagateUpdate()
+	root.PropagateLayout()
+	return field, window
+}
+
+func shortcut(k gkit.Key) *gkit.KeyEvent {
+	e := key(k)
+	e.Modifiers = gkit.ModControl
+	return e
+}
+
+func checkChanges(t *testing.T, name string, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+		t.Errorf("%s: OnChange got %q, want %q", name, got, want)
+	}
+}
+
+func TestTextFieldMaxLength(t *testing.T) {
+	var changes []string
+	field, _ := newTestTextField(t, &changes)
+	field.SetText("abcdef")
+	// Shortening the limit truncates the text without reporting it.
+	field.SetMaxLength(3)
+	if got := field.Text(); got != "abc" {
+		t.Errorf("Text() = %q after SetMaxLength(3), want \"abc\"", got)
+	}
+	checkChanges(t, "SetMaxLength", changes)
+
+	// Pastes are cut at the limit, in runes.
+	field.SetMaxLength(5)
+	field.Clipboard.SetClipboardText("dé\nfg")
+	field.Paste()
+	if got := field.Text(); got != "abcdé" {
+		t.Errorf("Text() = %q after paste, want \"abcdé\"", got)
+	}
+	if start, end := field.Selection(); start != 5 || end != 5 {
+		t.Errorf("Selection() = %d, %d after paste, want 5, 5", start, end)
+	}
+	checkChanges(t, "paste", changes, "abcdé")
+
+	// A full field takes nothing, unless the paste replaces a selection.
+	field.Paste()
+	checkChanges(t, "paste into a full field", changes, "abcdé")
+	field.SetSelection(1, 3)
+	field.Paste()
+	if got := field.Text(); got != "adédé" {
+		t.Errorf("Text() = %q after paste over a selection, want \"adédé\"", got)
+	}
+	checkChanges(t, "paste over a selection", changes, "abcdé", "adédé")
+}
+
+func TestTextFieldPassword(t *testing.T) {
+	var changes []string
+	field, _ := newTestTextField(t, &changes)
+	field.SetText("pa sé")
+	field.SetPassword(true)
+	// A bullet per rune, not per byte.
+	if got, want := field.displayText(), "•••••"; got != want {
+		t.Errorf("displayText() = %q, want %q", got, want)
+	}
+
+	// Carets are placed at the offsets of the bullets.
+	bullet := field.font.StringSize(16, passwordMask).Width
+	offsets := field.offsets()
+	if len(offsets) != 6 || offsets[3] != field.font.StringSize(16, "•••").Width {
+		t.Errorf("offsets() = %v, want 6 offsets of bullets %d wide", offsets, bullet)
+	}
+	field.MouseDown(&gkit.MouseEvent{
+		Type: gkit.MouseDown, Button: gkit.MouseButtonLeft,
+		Local: gkit.Point{X: field.Bounds().X + offsets[3], Y: 15},
+	})
+	field.MouseUp(&gkit.MouseEvent{Type: gkit.MouseUp, Button: gkit.MouseButtonLeft})
+	if start, end := field.Selection(); start != 3 || end != 3 {
+		t.Errorf("Selection() = %d, %d after clicking the third bullet, want 3, 3", start, end)
+	}
+
+	// Word movements don't give away the spaces.
+	word := key(gkit.KeyArrowLeft)
+	word.Modifiers = gkit.ModControl
+	field.KeyDown(word)
+	if start, _ := field.Selection(); start != 2 {
+		t.Errorf("caret at %d after a word left from 3, want 2", start)
+	}
+
+	// Nor do copying and cutting.
+	field.Clipboard.SetClipboardText("clipboard")
+	field.KeyDown(shortcut(gkit.KeyA))
+	field.KeyDown(shortcut(gkit.KeyC))
+	field.KeyDown(shortcut(gkit.KeyX))
+	if got := field.Clipboard.ClipboardText(); got != "clipboard" {
+		t.Errorf("clipboard = %q after copying a password, want it unchanged", got)
+	}
+	if got := field.Text(); got != "pa sé" {
+		t.Errorf("Text() = %q after cutting a password, want it unchanged", got)
+	}
+	checkChanges(t, "password", changes)
+}
+
+func TestTextFieldClipboard(t *testing.T) {
+	var changes []string
+	field, _ := newTestTextField(t, &changes)
+	field.SetText("hello world")
+	field.SetSelection(0, 5)
+	field.KeyDown(shortcut(gkit.KeyC))
+	if got := field.Clipboard.ClipboardText(); got != "hello" {
+		t.Errorf("clipboard = %q after copy, want \"hello\"", got)
+	}
+	checkChanges(t, "copy", changes)
+
+	field.SetSelection(5, 11)
+	field.KeyDown(shortcut(gkit.KeyX))
+	if got := field.Clipboard.ClipboardText(); got != " world" {
+		t.Errorf("clipboard = %q after cut, want \" world\"", got)
+	}
+	checkChanges(t, "cut", changes, "hello")
+
+	field.KeyDown(key(gkit.KeyHome))
+	field.KeyDown(shortcut(gkit.KeyV))
+	checkChanges(t, "paste", changes, "hello", " worldhello")
+	if start, end := field.Selection(); start != 6 || end != 6 {
+		t.Errorf("Selection() = %d, %d after paste, want 6, 6", start, end)
+	}
+
+	// Without a selection there is nothing to copy or cut.
+	field.Clipboard.SetClipboardText("x")
+	field.Copy()
+	field.Cut()
+	if got := field.Clipboard.ClipboardText(); got != "x" {
+		t.Errorf("clipboard = %q after copying nothing, want \"x\"", got)
+	}
+	checkChanges(t, "cut nothing", changes, "hello", " worldhello")
+
+	// Fields without a clipboard ignore the shortcuts.
+	field.Clipboard = nil
+	field.SetSelection(0, 3)
+	for _, k := range []gkit.Key{gkit.KeyC, gkit.KeyX, gkit.KeyV} {
+		field.KeyDown(shortcut(k))
+	}
+	if got := field.Text(); got != " worldhello" {
+		t.Errorf("Text() = %q without a clipboard, want it unchanged", got)
+	}
+}
+
+func TestTextFieldEvents(t *testing.T) {
+	var changes, submits []string
+	field, _ := newTestTextField(t, &changes)
+	field.OnSubmit = func(text string) { submits = append(submits, text) }
+	field.SetText("ab")
+	field.KeyDown(key(gkit.KeyEnd))
+	field.TextInput(&gkit.TextInputEvent{Text: "c\td"})
+	field.KeyDown(key(gkit.KeyBackspace))
+	field.KeyDown(key(gkit.KeyHome))
+	field.KeyDown(key(gkit.KeyBackspace))
+	field.KeyDown(key(gkit.KeyDelete))
+	field.KeyDown(key(gkit.KeyEnter))
+	// Only the edits that change the text report it, SetText doesn't.
+	checkChanges(t, "edits", changes, "abcd", "abc", "bc")
+	if len(submits) != 1 || submits[0] != "bc" {
+		t.Errorf("OnSubmit got %q, want [\"bc\"]", submits)
+	}
+	if got := field.Text(); got != "bc" {
+		t.Errorf("Text() = %q, want \"bc\"", got)
+	}
+}
+
+func TestTextFieldPlaceholder(t *testing.T) {
+	var changes []string
+	field, window := newTestTextField(t, &changes)
+	field.SetPlaceholder("Name")
+	field.PlaceholderColor = gkit.RGBA(255, 0, 0, 255)
+	field.Color = gkit.RGBA(0, 0, 255, 255)
+	// count returns how many pixels of the field are more red and more
+	// blue than white.
+	count := func() (red, blue int) {
+		window.Root().PropagateUpdate()
+		window.Root().PropagateLayout()
+		p := window.BeginPaint()
+		p.DrawLayer(gkit.Rect{Size: window.Size()}, window.Root())
+		window.EndPaint(p)
+		img := window.Image()
+		for y := 10; y < 40; y++ {
+			for x := 10; x < 160; x++ {
+				c := img.RGBAAt(x, y)
+				switch r, b := int(c.R), int(c.B); {
+				case r > b+50:
+					red++
+				case b > r+50:
+					blue++
+				}
+			}
+		}
+		return red, blue
+	}
+
+	if red, blue := count(); red == 0 || blue != 0 {
+		t.Errorf("empty field has %d placeholder and %d text pixels, want only placeholder ones", red, blue)
+	}
+	// Focused fields show the caret instead.
+	field.SetFocused(true)
+	caret := int(field.font.LineHeight(16))
+	if red, blue := count(); red != 0 || blue != caret {
+		t.Errorf("focused empty field has %d placeholder and %d text pixels, want only the %d of the caret", red, blue, caret)
+	}
+	field.SetFocused(false)
+	field.SetText("Bob")
+	if red, blue := count(); red != 0 || blue == 0 {
+		t.Errorf("field with text has %d placeholder and %d text pixels, want only text ones", red, blue)
+	}
+}
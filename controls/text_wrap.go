@@ -0,0 +1,94 @@
+package controls
+
+import (
+	"unicode"
+
+	"github.com/alex-ac/gkit"
+)
+
+type TextWrap uint8
+
+const (
+	WrapNone TextWrap = iota
+	WrapWord
+	WrapChar
+)
+
+// textLine is a line of laid out text as a rune range. A wrapped line ends
+// where the next one starts, so trailing spaces stay on the line they
+// follow; a line ended by a newline doesn't include it.
+type textLine struct {
+	start int
+	end   int
+}
+
+// wrapLines breaks text into lines at newlines and, unless width is zero or
+// mode is WrapNone, so that every line fits width. WrapWord breaks between
+// words and only breaks words that are wider than width between characters.
+func wrapLines(font *gkit.Font, size uint32, text []rune, width uint32, mode TextWrap) []textLine {
+	if mode == WrapNone {
+		width = 0
+	}
+	var lines []textLine
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) && text[i] != '\n' {
+			continue
+		}
+		lines = append(lines, wrapParagraph(font, size, text, start, i, width, mode)...)
+		start = i + 1
+	}
+	return lines
+}
+
+func wrapParagraph(font *gkit.Font, size uint32, text []rune, start, end int, width uint32, mode TextWrap) []textLine {
+	if width == 0 || start == end {
+		return []textLine{{start, end}}
+	}
+	offsets := font.Offsets(size, string(text[start:end]))
+	fits := func(from, to int) bool {
+		return offsets[to-start]-offsets[from-start] <= width
+	}
+
+	var lines []textLine
+	lineStart := start
+	for lineStart < end {
+		// Find the last word boundary that still fits, ignoring the spaces
+		// at the end of the line.
+		lineEnd := -1
+		for i := lineStart + 1; i <= end && mode == WrapWord; i++ {
+			if i < end && !(unicode.IsSpace(text[i-1]) && !unicode.IsSpace(text[i])) {
+				continue
+			}
+			visible := i
+			for visible > lineStart && unicode.IsSpace(text[visible-1]) {
+				visible--
+			}
+			if !fits(lineStart, visible) {
+				break
+			}
+			lineEnd = i
+		}
+		if lineEnd < 0 {
+			// Not even one word fits or words don't matter, break between
+			// characters.
+			lineEnd = lineStart + 1
+			for lineEnd < end && fits(lineStart, lineEnd+1) {
+				lineEnd++
+			}
+		}
+		lines = append(lines, textLine{lineStart, lineEnd})
+		lineStart = lineEnd
+	}
+	return lines
+}
+
+// lineAt returns the index of the line that shows position.
+func lineAt(lines []textLine, position int) int {
+	for i := len(lines) - 1; i > 0; i-- {
+		if lines[i].start <= position {
+			return i
+		}
+	}
+	return 0
+}
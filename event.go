@@ -0,0 +1,183 @@
+package gkit
+
+type EventType uint8
+
+const (
+	MouseMove EventType = iota
+	MouseDown
+	MouseUp
+	MouseEnter
+	MouseLeave
+	Scroll
+	KeyDown
+	KeyUp
+	TextInput
+)
+
+type MouseButton uint8
+
+const (
+	MouseButtonLeft MouseButton = iota
+	MouseButtonRight
+	MouseButtonMiddle
+)
+
+type Modifiers uint8
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModControl
+	ModAlt
+	ModSuper
+)
+
+type Key int
+
+const (
+	KeyUnknown Key = iota
+	KeySpace
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyEscape
+	KeyInsert
+	KeyArrowLeft
+	KeyArrowRight
+	KeyArrowUp
+	KeyArrowDown
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyShift
+	KeyControl
+	KeyAlt
+	KeySuper
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	KeyA
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+)
+
+type Event interface {
+	EventType() EventType
+}
+
+// MouseEvent describes MouseMove, MouseDown, MouseUp, MouseEnter and
+// MouseLeave events. Position is relative to the window, Local is relative
+// to the origin of the view the event is delivered to.
+type MouseEvent struct {
+	Type     EventType
+	Position Point
+	Local    Point
+	// Inside reports whether Position is inside the frame of the view the
+	// event is delivered to, no matter which views are on top of it. Local
+	// is clamped to 0 left of and above the view, so it doesn't tell.
+	Inside    bool
+	Button    MouseButton
+	Modifiers Modifiers
+}
+
+// setLocal sets Local and Inside for the last view of path.
+func (e *MouseEvent) setLocal(path []View) {
+	p := localPoint(path, e.Position)
+	e.Local = p.Point()
+	e.Inside = (RectF{SizeF: path[len(path)-1].Size().SizeF()}).Contains(p)
+}
+
+func (e *MouseEvent) EventType() EventType {
+	return e.Type
+}
+
+type ScrollEvent struct {
+	Position  Point
+	Local     Point
+	DeltaX    float32
+	DeltaY    float32
+	Modifiers Modifiers
+}
+
+func (e *ScrollEvent) EventType() EventType {
+	return Scroll
+}
+
+type KeyEvent struct {
+	Type      EventType
+	Key       Key
+	Modifiers Modifiers
+	Repeat    bool
+}
+
+func (e *KeyEvent) EventType() EventType {
+	return e.Type
+}
+
+type TextInputEvent struct {
+	Text string
+}
+
+func (e *TextInputEvent) EventType() EventType {
+	return TextInput
+}
+
+// Views implement the handler interfaces below to receive events. Returning
+// true consumes the event, otherwise it bubbles up to the view's ancestors.
+
+type MouseHandler interface {
+	MouseDown(e *MouseEvent) bool
+	MouseUp(e *MouseEvent) bool
+	MouseMove(e *MouseEvent) bool
+}
+
+// HoverHandler is notified when the pointer enters or leaves the view's
+// frame. These events don't bubble.
+type HoverHandler interface {
+	MouseEnter(e *MouseEvent)
+	MouseLeave(e *MouseEvent)
+}
+
+type ScrollHandler interface {
+	Scroll(e *ScrollEvent) bool
+}
+
+type KeyHandler interface {
+	KeyDown(e *KeyEvent) bool
+	KeyUp(e *KeyEvent) bool
+}
+
+type TextInputHandler interface {
+	TextInput(e *TextInputEvent) bool
+}
@@ -0,0 +1,170 @@
+package gkit
+
+// EventDispatcher delivers window events to a view tree. Windows keep one
+// dispatcher each and feed it with the events of their window system.
+type EventDispatcher struct {
+	hovered  []View
+	captured []View
+	buttons  uint32
+
+	focused View
+}
+
+func (d *EventDispatcher) Dispatch(root View, e Event) bool {
+	switch e := e.(type) {
+	case *MouseEvent:
+		return d.dispatchMouse(root, e)
+	case *ScrollEvent:
+		path := hitPath(root, e.Position)
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(ScrollHandler); ok {
+				e.Local = LocalPoint(path[:i+1], e.Position)
+				if handler.Scroll(e) {
+					return true
+				}
+			}
+		}
+	case *KeyEvent:
+		path := d.keyPath(root)
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(KeyHandler); ok {
+				consumed := false
+				if e.Type == KeyUp {
+					consumed = handler.KeyUp(e)
+				} else {
+					consumed = handler.KeyDown(e)
+				}
+				if consumed {
+					return true
+				}
+			}
+		}
+		if e.Type == KeyDown && e.Key == KeyTab {
+			d.MoveFocus(root, e.Modifiers&ModShift != 0)
+			return true
+		}
+	case *TextInputEvent:
+		path := d.keyPath(root)
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(TextInputHandler); ok && handler.TextInput(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyPath is the path keyboard events are delivered to: the focused view and
+// its ancestors, or just the root if nothing is focused. Focus on a view that
+// left the tree or can't be focused any more is dropped.
+func (d *EventDispatcher) keyPath(root View) []View {
+	if d.focused != nil {
+		if path := pathTo(root, d.focused); path != nil && canFocus(d.focused) {
+			return path
+		}
+		d.SetFocus(nil)
+	}
+	if root == nil {
+		return nil
+	}
+	return []View{root}
+}
+
+func (d *EventDispatcher) dispatchMouse(root View, e *MouseEvent) bool {
+	if e.Type == MouseLeave {
+		d.updateHovered(nil, e)
+		return false
+	}
+
+	path := hitPath(root, e.Position)
+	d.updateHovered(path, e)
+	if d.captured != nil && e.Type != MouseDown {
+		path = d.captured
+	}
+
+	switch e.Type {
+	case MouseDown:
+		d.buttons |= 1 << e.Button
+		if d.captured == nil {
+			d.focusPath(path)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(MouseHandler); ok {
+				e.setLocal(path[:i+1])
+				if handler.MouseDown(e) {
+					if d.captured == nil {
+						d.captured = path[:i+1]
+					}
+					return true
+				}
+			}
+		}
+	case MouseUp:
+		d.buttons &^= 1 << e.Button
+		if d.buttons == 0 {
+			d.captured = nil
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(MouseHandler); ok {
+				e.setLocal(path[:i+1])
+				if handler.MouseUp(e) {
+					return true
+				}
+			}
+		}
+	case MouseMove, MouseEnter:
+		for i := len(path) - 1; i >= 0; i-- {
+			if handler, ok := path[i].(MouseHandler); ok {
+				e.setLocal(path[:i+1])
+				if handler.MouseMove(e) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// focusPath focuses the deepest focusable view of path or clears focus if
+// there is none.
+func (d *EventDispatcher) focusPath(path []View) {
+	for i := len(path) - 1; i >= 0; i-- {
+		if canFocus(path[i]) {
+			d.SetFocus(path[i])
+			return
+		}
+	}
+	d.SetFocus(nil)
+}
+
+// updateHovered sends MouseLeave to the views that are no longer under the
+// pointer, deepest first, and MouseEnter to the new ones, outermost first.
+func (d *EventDispatcher) updateHovered(path []View, e *MouseEvent) {
+	common := 0
+	for common < len(path) && common < len(d.hovered) && path[common] == d.hovered[common] {
+		common++
+	}
+	for i := len(d.hovered) - 1; i >= common; i-- {
+		if handler, ok := d.hovered[i].(HoverHandler); ok {
+			event := &MouseEvent{
+				Type:      MouseLeave,
+				Position:  e.Position,
+				Modifiers: e.Modifiers,
+			}
+			event.setLocal(d.hovered[:i+1])
+			handler.MouseLeave(event)
+		}
+	}
+	for i := common; i < len(path); i++ {
+		if handler, ok := path[i].(HoverHandler); ok {
+			event := &MouseEvent{
+				Type:      MouseEnter,
+				Position:  e.Position,
+				Modifiers: e.Modifiers,
+			}
+			event.setLocal(path[:i+1])
+			handler.MouseEnter(event)
+		}
+	}
+	d.hovered = path
+}
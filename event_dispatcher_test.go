@@ -0,0 +1,288 @@
+package gkit_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/headless"
+)
+
+// testView records the events it receives into a shared log and consumes
+// the event types listed in consume.
+type testView struct {
+	gkit.ViewBase
+
+	name    string
+	log     *[]string
+	consume map[gkit.EventType]bool
+}
+
+func newTestView(log *[]string, name string, frame gkit.Rect) *testView {
+	view := &testView{
+		name:    name,
+		log:     log,
+		consume: make(map[gkit.EventType]bool),
+	}
+	view.ViewBase.View = view
+	view.SetFrame(frame)
+	return view
+}
+
+func (v *testView) record(format string, args ...interface{}) {
+	*v.log = append(*v.log, v.name+" "+fmt.Sprintf(format, args...))
+}
+
+func (v *testView) mouse(kind string, e *gkit.MouseEvent) bool {
+	v.record("%s %d,%d", kind, e.Local.X, e.Local.Y)
+	return v.consume[e.Type]
+}
+
+func (v *testView) MouseDown(e *gkit.MouseEvent) bool { return v.mouse("down", e) }
+func (v *testView) MouseUp(e *gkit.MouseEvent) bool   { return v.mouse("up", e) }
+func (v *testView) MouseMove(e *gkit.MouseEvent) bool { return v.mouse("move", e) }
+func (v *testView) MouseEnter(e *gkit.MouseEvent)     { v.record("enter") }
+func (v *testView) MouseLeave(e *gkit.MouseEvent)     { v.record("leave") }
+
+func (v *testView) Scroll(e *gkit.ScrollEvent) bool {
+	v.record("scroll %d,%d", e.Local.X, e.Local.Y)
+	return v.consume[gkit.Scroll]
+}
+
+func (v *testView) KeyDown(e *gkit.KeyEvent) bool {
+	v.record("keydown %d", e.Key)
+	return v.consume[gkit.KeyDown]
+}
+
+func (v *testView) KeyUp(e *gkit.KeyEvent) bool {
+	v.record("keyup %d", e.Key)
+	return v.consume[gkit.KeyUp]
+}
+
+func (v *testView) TextInput(e *gkit.TextInputEvent) bool {
+	v.record("text %s", e.Text)
+	return v.consume[gkit.TextInput]
+}
+
+func (v *testView) FocusGained() { v.record("focus") }
+func (v *testView) FocusLost()   { v.record("blur") }
+
+func (v *testView) Layout()             {}
+func (v *testView) Update()             {}
+func (v *testView) UpdateSizes()        {}
+func (v *testView) Draw(p gkit.Painter) {}
+
+func newTestWindow(t *testing.T, system *headless.WindowSystem, root gkit.View) *headless.Window {
+	t.Helper()
+	window, err := system.Create(200, 200, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := window.(*headless.Window)
+	w.SetRoot(root)
+	return w
+}
+
+// testTree builds root (200x200) > parent at (10, 10) > child at (20, 20),
+// so the window point (40, 40) is (30, 30) in parent and (10, 10) in child.
+func testTree(log *[]string) (root, parent, child *testView) {
+	root = newTestView(log, "root", gkit.Rect{Size: gkit.Size{Width: 200, Height: 200}})
+	parent = newTestView(log, "parent", gkit.Rect{
+		Point: gkit.Point{X: 10, Y: 10},
+		Size:  gkit.Size{Width: 100, Height: 100},
+	})
+	child = newTestView(log, "child", gkit.Rect{
+		Point: gkit.Point{X: 20, Y: 20},
+		Size:  gkit.Size{Width: 50, Height: 50},
+	})
+	root.AddChild(parent)
+	parent.AddChild(child)
+	return root, parent, child
+}
+
+func mouse(t gkit.EventType, x, y uint32) *gkit.MouseEvent {
+	return &gkit.MouseEvent{Type: t, Position: gkit.Point{X: x, Y: y}}
+}
+
+func checkLog(t *testing.T, log *[]string, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(*log, want) {
+		t.Errorf("events = %q, want %q", *log, want)
+	}
+	*log = nil
+}
+
+func TestDispatchMouseBubbles(t *testing.T) {
+	var log []string
+	root, parent, _ := testTree(&log)
+	parent.consume[gkit.MouseDown] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 40, 40))
+	checkLog(t, &log,
+		"root enter", "parent enter", "child enter",
+		"child move 10,10", "parent move 30,30", "root move 40,40")
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 40, 40))
+	checkLog(t, &log, "child down 10,10", "parent down 30,30")
+}
+
+func TestDispatchMouseCapture(t *testing.T) {
+	var log []string
+	root, parent, _ := testTree(&log)
+	parent.consume[gkit.MouseDown] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 40, 40))
+	log = nil
+
+	// The view that consumed MouseDown and its ancestors keep receiving
+	// mouse events while the button is held, even outside of their frames.
+	w.DispatchEvent(mouse(gkit.MouseMove, 150, 150))
+	checkLog(t, &log,
+		"child leave", "parent leave",
+		"parent move 140,140", "root move 150,150")
+
+	w.DispatchEvent(mouse(gkit.MouseUp, 150, 150))
+	checkLog(t, &log, "parent up 140,140", "root up 150,150")
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 150, 150))
+	checkLog(t, &log, "root move 150,150")
+}
+
+func TestDispatchCaptureHoldsUntilAllButtonsUp(t *testing.T) {
+	var log []string
+	root, parent, _ := testTree(&log)
+	parent.consume[gkit.MouseDown] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 40, 40))
+	right := mouse(gkit.MouseDown, 150, 150)
+	right.Button = gkit.MouseButtonRight
+	w.DispatchEvent(right)
+	log = nil
+
+	w.DispatchEvent(mouse(gkit.MouseUp, 150, 150))
+	checkLog(t, &log, "parent up 140,140", "root up 150,150")
+
+	right = mouse(gkit.MouseUp, 150, 150)
+	right.Button = gkit.MouseButtonRight
+	w.DispatchEvent(right)
+	checkLog(t, &log, "parent up 140,140", "root up 150,150")
+
+	w.DispatchEvent(mouse(gkit.MouseUp, 150, 150))
+	checkLog(t, &log, "root up 150,150")
+}
+
+func TestDispatchHover(t *testing.T) {
+	var log []string
+	root, parent, child := testTree(&log)
+	for _, view := range []*testView{root, parent, child} {
+		view.consume[gkit.MouseMove] = true
+	}
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 15, 15))
+	checkLog(t, &log, "root enter", "parent enter", "parent move 5,5")
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 40, 40))
+	checkLog(t, &log, "child enter", "child move 10,10")
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 45, 45))
+	checkLog(t, &log, "child move 15,15")
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 150, 150))
+	checkLog(t, &log, "child leave", "parent leave", "root move 150,150")
+
+	w.DispatchEvent(mouse(gkit.MouseMove, 40, 40))
+	log = nil
+	w.DispatchEvent(mouse(gkit.MouseLeave, 0, 0))
+	checkLog(t, &log, "child leave", "parent leave", "root leave")
+}
+
+func TestDispatchScrollBubbles(t *testing.T) {
+	var log []string
+	root, _, _ := testTree(&log)
+	root.consume[gkit.Scroll] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(&gkit.ScrollEvent{Position: gkit.Point{X: 40, Y: 40}, DeltaY: 1})
+	checkLog(t, &log, "child scroll 10,10", "parent scroll 30,30", "root scroll 40,40")
+}
+
+func TestDispatchKeysToFocusedPath(t *testing.T) {
+	var log []string
+	root, parent, child := testTree(&log)
+	parent.consume[gkit.KeyDown] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	// Without focus, keyboard events go to the root only.
+	w.DispatchEvent(&gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyA})
+	w.DispatchEvent(&gkit.TextInputEvent{Text: "a"})
+	checkLog(t, &log,
+		fmt.Sprintf("root keydown %d", gkit.KeyA), "root text a")
+
+	child.SetFocusable(true)
+	w.SetFocus(child)
+	checkLog(t, &log, "child focus")
+
+	w.DispatchEvent(&gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyA})
+	checkLog(t, &log,
+		fmt.Sprintf("child keydown %d", gkit.KeyA),
+		fmt.Sprintf("parent keydown %d", gkit.KeyA))
+
+	w.DispatchEvent(&gkit.KeyEvent{Type: gkit.KeyUp, Key: gkit.KeyA})
+	checkLog(t, &log,
+		fmt.Sprintf("child keyup %d", gkit.KeyA),
+		fmt.Sprintf("parent keyup %d", gkit.KeyA),
+		fmt.Sprintf("root keyup %d", gkit.KeyA))
+
+	w.DispatchEvent(&gkit.TextInputEvent{Text: "b"})
+	checkLog(t, &log, "child text b", "parent text b", "root text b")
+
+	// Focus on a view that left the tree is dropped.
+	parent.DeleteChild(child)
+	w.DispatchEvent(&gkit.TextInputEvent{Text: "c"})
+	checkLog(t, &log, "child blur", "root text c")
+	if w.Focus() != nil {
+		t.Errorf("Focus() = %v, want nil", w.Focus())
+	}
+}
+
+func TestDispatchMouseDownFocuses(t *testing.T) {
+	var log []string
+	root, parent, _ := testTree(&log)
+	parent.SetFocusable(true)
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 40, 40))
+	if w.Focus() != parent {
+		t.Errorf("Focus() = %v, want parent", w.Focus())
+	}
+	w.DispatchEvent(mouse(gkit.MouseUp, 40, 40))
+
+	w.DispatchEvent(mouse(gkit.MouseDown, 150, 150))
+	if w.Focus() != nil {
+		t.Errorf("Focus() = %v, want nil", w.Focus())
+	}
+}
+
+func TestWindowSystemPostEvent(t *testing.T) {
+	var log []string
+	root, _, _ := testTree(&log)
+	system := headless.NewWindowSystem()
+	w := newTestWindow(t, system, root)
+
+	system.PostEvent(w, mouse(gkit.MouseDown, 150, 150))
+	system.PostEvent(w, mouse(gkit.MouseUp, 150, 150))
+	if len(log) != 0 {
+		t.Fatalf("events dispatched before WaitEvents: %q", log)
+	}
+	system.WaitEvents()
+	checkLog(t, &log, "root enter", "root down 150,150", "root up 150,150")
+
+	system.Interrupt()
+	system.WaitEvents()
+	checkLog(t, &log)
+}
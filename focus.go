@@ -0,0 +1,127 @@
+package gkit
+
+import (
+	"sort"
+)
+
+// Focusable is implemented by ViewBase. Views that want to receive keyboard
+// focus call SetFocusable(true).
+//
+// Tab traversal visits views with a positive tab index first, in ascending
+// order, and then views with a zero tab index in tree order. Views with a
+// negative tab index can be focused by mouse or SetFocus only.
+type Focusable interface {
+	Focusable() bool
+	TabIndex() int
+	Focused() bool
+	SetFocused(bool)
+}
+
+// FocusHandler is notified after the view has gained or lost focus.
+type FocusHandler interface {
+	FocusGained()
+	FocusLost()
+}
+
+func canFocus(view View) bool {
+	f, ok := view.(Focusable)
+	return ok && f.Focusable()
+}
+
+func (d *EventDispatcher) Focus() View {
+	return d.focused
+}
+
+func (d *EventDispatcher) SetFocus(view View) {
+	if d.focused == view {
+		return
+	}
+	old := d.focused
+	d.focused = view
+	if old != nil {
+		if f, ok := old.(Focusable); ok {
+			f.SetFocused(false)
+		}
+		if handler, ok := old.(FocusHandler); ok {
+			handler.FocusLost()
+		}
+	}
+	if view != nil {
+		if f, ok := view.(Focusable); ok {
+			f.SetFocused(true)
+		}
+		if handler, ok := view.(FocusHandler); ok {
+			handler.FocusGained()
+		}
+	}
+}
+
+// MoveFocus focuses the next view in tab order, or the previous one if
+// backward is set. Focus wraps around at the ends of the tree.
+func (d *EventDispatcher) MoveFocus(root View, backward bool) {
+	order := tabOrder(root)
+	if len(order) == 0 {
+		d.SetFocus(nil)
+		return
+	}
+	current := -1
+	for i, view := range order {
+		if view == d.focused {
+			current = i
+			break
+		}
+	}
+	next := 0
+	switch {
+	case current < 0 && backward:
+		next = len(order) - 1
+	case current < 0:
+		next = 0
+	case backward:
+		next = (current + len(order) - 1) % len(order)
+	default:
+		next = (current + 1) % len(order)
+	}
+	d.SetFocus(order[next])
+}
+
+func tabOrder(root View) []View {
+	var order []View
+	var walk func(View)
+	walk = func(view View) {
+		if f, ok := view.(Focusable); ok && f.Focusable() && f.TabIndex() >= 0 {
+			order = append(order, view)
+		}
+		for _, child := range view.Children() {
+			walk(child)
+		}
+	}
+	if root != nil {
+		walk(root)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i].(Focusable).TabIndex(), order[j].(Focusable).TabIndex()
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return order
+}
+
+// pathTo returns the path from root to view, or nil if view isn't in the
+// tree.
+func pathTo(root, view View) []View {
+	if root == nil {
+		return nil
+	}
+	if root == view {
+		return []View{root}
+	}
+	for _, child := range root.Children() {
+		if path := pathTo(child, view); path != nil {
+			return append([]View{root}, path...)
+		}
+	}
+	return nil
+}
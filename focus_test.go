@@ -0,0 +1,128 @@
+package gkit_test
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/headless"
+)
+
+func tab(shift bool) *gkit.KeyEvent {
+	e := &gkit.KeyEvent{Type: gkit.KeyDown, Key: gkit.KeyTab}
+	if shift {
+		e.Modifiers = gkit.ModShift
+	}
+	return e
+}
+
+func focusName(w *headless.Window) string {
+	return hitName(w.Focus())
+}
+
+func TestTabOrder(t *testing.T) {
+	var log []string
+	root := newTestView(&log, "root", rect(0, 0, 200, 200))
+	group := newTestView(&log, "group", rect(0, 0, 100, 100))
+	tabIndices := map[string]int{
+		"a": 0,
+		"b": 2,
+		"c": -1,
+		"d": 1,
+		"e": 0,
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		view := newTestView(&log, name, rect(0, 0, 10, 10))
+		view.SetFocusable(true)
+		view.SetTabIndex(tabIndices[name])
+		group.AddChild(view)
+	}
+	root.AddChild(group)
+	for _, name := range []string{"d", "e"} {
+		view := newTestView(&log, name, rect(0, 0, 10, 10))
+		view.SetFocusable(true)
+		view.SetTabIndex(tabIndices[name])
+		root.AddChild(view)
+	}
+	// Views that aren't focusable are skipped whatever their tab index.
+	skipped := newTestView(&log, "skipped", rect(0, 0, 10, 10))
+	skipped.SetTabIndex(3)
+	root.AddChild(skipped)
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	var forward []string
+	for i := 0; i < 5; i++ {
+		w.DispatchEvent(tab(false))
+		forward = append(forward, focusName(w))
+	}
+	checkLog(t, &forward, "d", "b", "a", "e", "d")
+
+	var backward []string
+	for i := 0; i < 5; i++ {
+		w.DispatchEvent(tab(true))
+		backward = append(backward, focusName(w))
+	}
+	checkLog(t, &backward, "e", "a", "b", "d", "e")
+
+	// A view with a negative tab index can still be focused directly, and
+	// Tab moves on from the start of the order.
+	w.SetFocus(group.Children()[2])
+	w.DispatchEvent(tab(false))
+	if got := focusName(w); got != "d" {
+		t.Errorf("focus after Tab from c = %s, want d", got)
+	}
+}
+
+func TestTabWithoutFocusableViews(t *testing.T) {
+	var log []string
+	root, _, _ := testTree(&log)
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(tab(false))
+	if w.Focus() != nil {
+		t.Errorf("Focus() = %s, want nil", focusName(w))
+	}
+	w.DispatchEvent(tab(true))
+	if w.Focus() != nil {
+		t.Errorf("Focus() = %s, want nil", focusName(w))
+	}
+}
+
+func TestTabConsumedByFocusedView(t *testing.T) {
+	var log []string
+	root, parent, child := testTree(&log)
+	parent.SetFocusable(true)
+	child.SetFocusable(true)
+	child.consume[gkit.KeyDown] = true
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.DispatchEvent(tab(false))
+	if focusName(w) != "parent" {
+		t.Fatalf("Focus() = %s, want parent", focusName(w))
+	}
+	w.DispatchEvent(tab(false))
+	if focusName(w) != "child" {
+		t.Fatalf("Focus() = %s, want child", focusName(w))
+	}
+	// Views that handle Tab themselves, like a text area, keep focus.
+	w.DispatchEvent(tab(false))
+	if focusName(w) != "child" {
+		t.Errorf("Focus() = %s, want child", focusName(w))
+	}
+}
+
+func TestSetFocusNotifies(t *testing.T) {
+	var log []string
+	root, parent, child := testTree(&log)
+	parent.SetFocusable(true)
+	child.SetFocusable(true)
+	w := newTestWindow(t, headless.NewWindowSystem(), root)
+
+	w.SetFocus(parent)
+	w.SetFocus(parent)
+	w.SetFocus(child)
+	w.SetFocus(nil)
+	checkLog(t, &log, "parent focus", "parent blur", "child focus", "child blur")
+	if parent.Focused() || child.Focused() {
+		t.Errorf("Focused() = %v, %v after SetFocus(nil)", parent.Focused(), child.Focused())
+	}
+}
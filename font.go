@@ -1,11 +1,13 @@
 package gkit
 
 import (
+	"container/list"
 	"image"
 	"image/color"
 	"image/draw"
 	"io/ioutil"
 	"os"
+	"sync"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
@@ -14,8 +16,56 @@ import (
 	"github.com/golang/freetype/truetype"
 )
 
+// sizeCacheCapacity bounds the number of measured strings kept per font.
+const sizeCacheCapacity = 1024
+
 type Font struct {
 	font *truetype.Font
+
+	// mutex guards the caches and the faces, truetype faces aren't safe
+	// for concurrent use.
+	mutex sync.Mutex
+	// faces holds a face per pixel size. Painters pass sizes already
+	// multiplied by their scale factor, so every scale gets its own face.
+	faces map[uint32]font.Face
+	sizes sizeCache
+}
+
+type sizeKey struct {
+	size uint32
+	text string
+}
+
+type sizeEntry struct {
+	key  sizeKey
+	size Size
+}
+
+// sizeCache is a LRU cache of measured string sizes.
+type sizeCache struct {
+	entries map[sizeKey]*list.Element
+	order   list.List
+}
+
+func (c *sizeCache) get(key sizeKey) (Size, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return Size{}, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*sizeEntry).size, true
+}
+
+func (c *sizeCache) put(key sizeKey, size Size) {
+	if c.entries == nil {
+		c.entries = make(map[sizeKey]*list.Element)
+	}
+	if c.order.Len() >= sizeCacheCapacity {
+		oldest := c.order.Back()
+		delete(c.entries, oldest.Value.(*sizeEntry).key)
+		c.order.Remove(oldest)
+	}
+	c.entries[key] = c.order.PushFront(&sizeEntry{key: key, size: size})
 }
 
 func LoadFontFile(filename string) (*Font, error) {
@@ -34,39 +84,137 @@ func LoadFontFile(filename string) (*Font, error) {
 }
 
 func LoadFont(data []byte) (*Font, error) {
-	font, err := freetype.ParseFont(data)
+	ttf, err := freetype.ParseFont(data)
 	if err != nil {
 		return nil, err
 	}
 	return &Font{
-		font: font,
+		font:  ttf,
+		faces: make(map[uint32]font.Face),
 	}, nil
 }
 
+// drawer returns a drawer using the cached face for size. Callers must hold
+// f.mutex.
 func (f *Font) drawer(size uint32) font.Drawer {
-	options := truetype.Options{
-		Size: float64(size),
-		DPI:  0, // Fuck imperial system. 1pt = 1px now.
+	face, ok := f.faces[size]
+	if !ok {
+		options := truetype.Options{
+			Size: float64(size),
+			DPI:  0, // Fuck imperial system. 1pt = 1px now.
+		}
+		face = truetype.NewFace(f.font, &options)
+		f.faces[size] = face
 	}
-	face := truetype.NewFace(f.font, &options)
 	return font.Drawer{
 		Face: face,
 	}
 }
 
 func (f *Font) StringSize(size uint32, text string) Size {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := sizeKey{size, text}
+	if s, ok := f.sizes.get(key); ok {
+		return s
+	}
+
 	drawer := f.drawer(size)
 	metrics := drawer.Face.Metrics()
 	advance := drawer.MeasureString(text)
 	height := metrics.Height.Ceil()
 	width := advance.Ceil()
-	return Size{
+	s := Size{
 		Width:  uint32(width),
 		Height: uint32(height),
 	}
+	f.sizes.put(key, s)
+	return s
+}
+
+// Offsets returns the horizontal position of every rune boundary of text
+// as DrawString lays it out: offsets[i] is the width of the first i runes,
+// the last entry is the width of the whole text.
+func (f *Font) Offsets(size uint32, text string) []uint32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	drawer := f.drawer(size)
+	offsets := make([]uint32, 1, len(text)+1)
+	var advance fixed.Int26_6
+	prev := rune(-1)
+	for _, r := range text {
+		if prev >= 0 {
+			advance += drawer.Face.Kern(prev, r)
+		}
+		a, _ := drawer.Face.GlyphAdvance(r)
+		advance += a
+		offsets = append(offsets, uint32(advance.Round()))
+		prev = r
+	}
+	return offsets
+}
+
+// Positions returns the unrounded pen position of every rune of text in
+// pixels, the position DrawString draws the rune at.
+func (f *Font) Positions(size uint32, text string) []float32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	drawer := f.drawer(size)
+	positions := make([]float32, 0, len(text))
+	var pen fixed.Int26_6
+	prev := rune(-1)
+	for _, r := range text {
+		if prev >= 0 {
+			pen += drawer.Face.Kern(prev, r)
+		}
+		positions = append(positions, float32(pen)/64)
+		a, _ := drawer.Face.GlyphAdvance(r)
+		pen += a
+		prev = r
+	}
+	return positions
+}
+
+// GlyphMask rasterizes r the way DrawString does with the pen dx pixels
+// right of the origin, 0 <= dx < 1. It returns the coverage of the glyph
+// and the position of its top-left corner relative to the origin, or nil
+// if the glyph has no pixels.
+func (f *Font) GlyphMask(size uint32, r rune, dx float32) (*image.Alpha, image.Point) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	drawer := f.drawer(size)
+	dot := fixed.Point26_6{
+		X: fixed.Int26_6(dx * 64),
+		Y: drawer.Face.Metrics().Ascent,
+	}
+	dr, mask, maskp, _, ok := drawer.Face.Glyph(dot, r)
+	if !ok || dr.Empty() {
+		return nil, image.Point{}
+	}
+	// The face reuses its mask buffer, so the glyph has to be copied.
+	glyph := image.NewAlpha(image.Rectangle{Max: dr.Size()})
+	draw.Draw(glyph, glyph.Bounds(), mask, maskp, draw.Src)
+	return glyph, dr.Min
+}
+
+// LineHeight returns the height of a line of text, the same height
+// StringSize reports.
+func (f *Font) LineHeight(size uint32) uint32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	drawer := f.drawer(size)
+	return uint32(drawer.Face.Metrics().Height.Ceil())
 }
 
 func (f *Font) DrawString(size uint32, text string, p Point, dst draw.Image) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	drawer := f.drawer(size)
 	metrics := drawer.Face.Metrics()
 
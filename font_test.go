@@ -0,0 +1,131 @@
+package gkit
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const benchmarkText = "The quick brown fox jumps over the lazy dog"
+
+func loadTestFont(t testing.TB) *Font {
+	t.Helper()
+	font, err := LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
+
+func TestSizeCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var c sizeCache
+	for i := 0; i < sizeCacheCapacity; i++ {
+		c.put(sizeKey{size: 10, text: fmt.Sprint(i)}, Size{Width: uint32(i)})
+	}
+	// Touch the oldest entry so that the second oldest one goes first.
+	if s, ok := c.get(sizeKey{size: 10, text: "0"}); !ok || s.Width != 0 {
+		t.Fatalf("get(0) = %v, %v", s, ok)
+	}
+	c.put(sizeKey{size: 10, text: "new"}, Size{Width: 1})
+
+	if _, ok := c.get(sizeKey{size: 10, text: "1"}); ok {
+		t.Error("least recently used entry wasn't evicted")
+	}
+	for _, text := range []string{"0", "2", "new"} {
+		if _, ok := c.get(sizeKey{size: 10, text: text}); !ok {
+			t.Errorf("entry %q was evicted", text)
+		}
+	}
+	if len(c.entries) != sizeCacheCapacity || c.order.Len() != sizeCacheCapacity {
+		t.Errorf("cache holds %d entries in map and %d in list, want %d",
+			len(c.entries), c.order.Len(), sizeCacheCapacity)
+	}
+}
+
+func TestFontConcurrentAccess(t *testing.T) {
+	font := loadTestFont(t)
+	sizes := []uint32{10, 14, 20}
+	texts := make([]string, 200)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("%s %d", benchmarkText, i)
+	}
+	// The expected values come from a separate font, so that the one under
+	// test starts with empty caches.
+	reference := loadTestFont(t)
+	want := make(map[sizeKey]Size)
+	for _, size := range sizes {
+		for _, text := range texts {
+			want[sizeKey{size, text}] = reference.StringSize(size, text)
+		}
+	}
+
+	var wg sync.WaitGroup
+	failures := make(chan string, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			dst := image.NewRGBA(image.Rect(0, 0, 64, 32))
+			for i := range texts {
+				text := texts[(i*7+g)%len(texts)]
+				size := sizes[(i+g)%len(sizes)]
+				if got := font.StringSize(size, text); got != want[sizeKey{size, text}] {
+					failures <- fmt.Sprintf("StringSize(%d, %q) = %v, want %v", size, text, got, want[sizeKey{size, text}])
+					return
+				}
+				offsets := font.Offsets(size, text)
+				if last := offsets[len(offsets)-1]; last == 0 {
+					failures <- fmt.Sprintf("Offsets(%d, %q) ends at 0", size, text)
+					return
+				}
+				font.LineHeight(size)
+				font.GlyphMask(size, 'g', 0.5)
+				font.DrawString(size, text, Point{}, dst)
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(failures)
+	for err := range failures {
+		t.Error(err)
+	}
+}
+
+func BenchmarkStringSize(b *testing.B) {
+	font := loadTestFont(b)
+	b.Run("hit", func(b *testing.B) {
+		font.StringSize(14, benchmarkText)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			font.StringSize(14, benchmarkText)
+		}
+	})
+	b.Run("cold", func(b *testing.B) {
+		// Faces stay cached, only the measured sizes are dropped.
+		font.StringSize(14, benchmarkText)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			font.mutex.Lock()
+			font.sizes = sizeCache{}
+			font.mutex.Unlock()
+			font.StringSize(14, benchmarkText)
+		}
+	})
+}
+
+func BenchmarkDrawString(b *testing.B) {
+	font := loadTestFont(b)
+	dst := image.NewRGBA(image.Rect(0, 0, 400, 32))
+	for _, size := range []uint32{14, 28} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			font.DrawString(size, benchmarkText, Point{}, dst)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				font.DrawString(size, benchmarkText, Point{}, dst)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package gl
+
+import (
+	"image"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+
+	"github.com/alex-ac/gkit"
+)
+
+// pass holds the vertices drawn into one target: the window, or the
+// texture of a composited layer.
+type pass struct {
+	vertices []float32
+	batches  []batch
+	target   *texture
+}
+
+func (p *painter) DrawCompositedLayer(r gkit.Rect, l gkit.Layer, opacity float32, mode gkit.BlendMode) {
+	if l.NeedsRedraw() {
+		p.enableRedraw()
+	}
+	t := p.Transform()
+	p.drawComposited(l, t.Mul(gkit.Translation(float32(r.X), float32(r.Y))), p.clip.ClipTo(r.RectF(), t), 0, opacity, mode)
+	p.flush()
+}
+
+// drawComposited draws l into a pass of its own, which is rendered into a
+// texture and then drawn as a quad cut to clip.
+func (p *painter) drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, z uint32, opacity float32, mode gkit.BlendMode) {
+	clip = clip.Intersect(p.bounds())
+	if clip.Empty() || opacity <= 0 {
+		return
+	}
+	p.addInstruction(func(p *painter) {
+		p.suspended = append(p.suspended, p.pass)
+		p.pass = &pass{target: p.context.groupTexture(p.deviceSize())}
+	})
+	proxy := &painterProxy{
+		impl: p,
+		base: base,
+		clip: clip,
+	}
+	l.Draw(proxy)
+	l.PropagateDraw(proxy)
+	p.addInstruction(func(p *painter) {
+		group := p.pass
+		n := len(p.suspended) - 1
+		p.pass, p.suspended = p.suspended[n], p.suspended[:n]
+		p.groups = append(p.groups, group)
+
+		// Texture rows start at the bottom.
+		size := p.size.SizeF()
+		r := clip.Bounds
+		uv := gkit.RectF{
+			PointF: gkit.PointF{X: r.X / size.Width, Y: 1 - r.Y/size.Height},
+			SizeF:  gkit.SizeF{Width: r.Width / size.Width, Height: -r.Height / size.Height},
+		}
+		color := paint{color: [4]float32{1, 1, 1, opacity}}
+		blendMode := p.blendMode
+		p.blendMode = mode
+		p.appendQuad(r, gkit.Identity(), clip, z, color, gkit.RectF{}, uv, group.target, shape{})
+		p.blendMode = blendMode
+	})
+}
+
+func (p *painter) deviceSize() image.Point {
+	return image.Point{
+		int(float32(p.size.Width)*p.scaleFactor + 0.5),
+		int(float32(p.size.Height)*p.scaleFactor + 0.5),
+	}
+}
+
+func (p *painterProxy) DrawCompositedLayer(r gkit.Rect, l gkit.Layer, opacity float32, mode gkit.BlendMode) {
+	t := p.toRoot()
+	p.drawComposited(l, t.Mul(gkit.Translation(float32(r.X), float32(r.Y))), p.clip.ClipTo(r.RectF(), t), 0, opacity, mode)
+}
+
+func (p *painterProxy) drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, z uint32, opacity float32, mode gkit.BlendMode) {
+	p.impl.drawComposited(l, base, clip, z+1, opacity, mode)
+}
+
+// groupTexture returns a texture of the given size for a composited layer
+// to be drawn into in this frame.
+func (g *drawingContext) groupTexture(size image.Point) *texture {
+	for i, t := range g.freeGroupTextures {
+		if t.size == size {
+			g.freeGroupTextures = append(g.freeGroupTextures[:i], g.freeGroupTextures[i+1:]...)
+			g.usedGroupTextures = append(g.usedGroupTextures, t)
+			return t
+		}
+	}
+	t := &texture{size: size, layer: true}
+	g.usedGroupTextures = append(g.usedGroupTextures, t)
+	return t
+}
+
+// collectGroupTextures deletes the group textures that weren't used in the
+// frame and keeps the others for the next one.
+func (g *drawingContext) collectGroupTextures() {
+	for _, t := range g.freeGroupTextures {
+		if t.id != 0 {
+			gl.DeleteTextures(1, &t.id)
+		}
+	}
+	g.freeGroupTextures, g.usedGroupTextures = g.usedGroupTextures, nil
+}
+
+// drawGroups renders the passes of composited layers into their textures,
+// inner layers first, and then binds the framebuffer that was bound before.
+func (g *drawingContext) drawGroups(groups []*pass) {
+	if len(groups) == 0 {
+		return
+	}
+	if g.framebuffer == 0 {
+		gl.GenFramebuffers(1, &g.framebuffer)
+	}
+	var target int32
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &target)
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	var clearColor [4]float32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &clearColor[0])
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.framebuffer)
+	gl.ClearColor(0, 0, 0, 0)
+	for _, group := range groups {
+		t := group.target
+		if t.id == 0 {
+			gl.GenTextures(1, &t.id)
+			gl.BindTexture(gl.TEXTURE_2D, t.id)
+			gl.TexImage2D(
+				gl.TEXTURE_2D, 0, gl.RGBA, int32(t.size.X), int32(t.size.Y), 0,
+				gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		}
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.id, 0)
+		gl.Viewport(0, 0, int32(t.size.X), int32(t.size.Y))
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+		g.drawPass(group)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(target))
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	gl.ClearColor(clearColor[0], clearColor[1], clearColor[2], clearColor[3])
+}
+
+// drawPass draws the vertices of pass into the bound framebuffer.
+func (g *drawingContext) drawPass(pass *pass) {
+	if len(pass.vertices) == 0 {
+		return
+	}
+	gl.BufferData(gl.ARRAY_BUFFER, len(pass.vertices)*4, gl.Ptr(pass.vertices), gl.STREAM_DRAW)
+	var first int32
+	for _, b := range pass.batches {
+		if b.texture != nil {
+			gl.BindTexture(gl.TEXTURE_2D, b.texture.id)
+		}
+		setBlendFunc(b.mode)
+		gl.DrawArrays(gl.TRIANGLES, first, b.count)
+		first += b.count
+	}
+}
+
+// setBlendFunc sets the blend functions of mode for the premultiplied
+// colors the fragment shader outputs.
+func setBlendFunc(mode gkit.BlendMode) {
+	switch mode {
+	case gkit.BlendMultiply:
+		gl.BlendFuncSeparate(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	case gkit.BlendScreen:
+		gl.BlendFuncSeparate(gl.ONE, gl.ONE_MINUS_SRC_COLOR, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	case gkit.BlendAdditive:
+		gl.BlendFunc(gl.ONE, gl.ONE)
+	case gkit.BlendCopy:
+		gl.BlendFunc(gl.ONE, gl.ZERO)
+	case gkit.BlendClear:
+		gl.BlendFunc(gl.ZERO, gl.ONE_MINUS_SRC_ALPHA)
+	default:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	}
+}
@@ -0,0 +1,213 @@
+package gl
+
+import (
+	"image"
+	"testing"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/soft"
+)
+
+// testLayer draws the whole scene of a test.
+type testLayer func(p gkit.Painter)
+
+func (l testLayer) Draw(p gkit.Painter)          { l(p) }
+func (l testLayer) PropagateDraw(p gkit.Painter) {}
+func (l testLayer) NeedsRedraw() bool            { return true }
+
+func renderSoft(size gkit.Size, l gkit.Layer) *image.RGBA {
+	c := soft.NewDrawingContext(size)
+	p := c.BeginPaint()
+	p.DrawLayer(gkit.Rect{Size: size}, l)
+	c.EndPaint(p)
+	return c.Image()
+}
+
+// renderGL draws l into a transparent framebuffer of the given size and
+// reads it back.
+func renderGL(t *testing.T, size gkit.Size, l gkit.Layer) *image.RGBA {
+	t.Helper()
+	context, err := newDrawingContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer context.Destroy()
+
+	width, height := int32(size.Width), int32(size.Height)
+	var renderbuffer uint32
+	gl.GenRenderbuffers(1, &renderbuffer)
+	defer gl.DeleteRenderbuffers(1, &renderbuffer)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, renderbuffer)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.RGBA8, width, height)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	var framebuffer uint32
+	gl.GenFramebuffers(1, &framebuffer)
+	defer gl.DeleteFramebuffers(1, &framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, framebuffer)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, renderbuffer)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		t.Fatalf("framebuffer status = %#x", status)
+	}
+	gl.Viewport(0, 0, width, height)
+	gl.ClearColor(0, 0, 0, 0)
+
+	p := context.BeginPaint(size)
+	p.DrawLayer(gkit.Rect{Size: size}, l)
+	context.EndPaint(p)
+
+	img := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	gl.ReadPixels(0, 0, width, height, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	// Framebuffer rows start at the bottom.
+	row := make([]byte, img.Stride)
+	for y := 0; y < int(height)/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(int(height)-1-y)*img.Stride : (int(height)-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+	return img
+}
+
+// maxColorDifference is how far GL pixels may be from the soft ones. The
+// backends round differently.
+const maxColorDifference = 2
+
+// compareImages reports how many pixels of got differ from want by more
+// than maxColorDifference, and the first of them.
+func compareImages(got, want *image.RGBA) (int, image.Point) {
+	var n int
+	var first image.Point
+	for i := 0; i < len(want.Pix); i++ {
+		d := int(got.Pix[i]) - int(want.Pix[i])
+		if d < -maxColorDifference || d > maxColorDifference {
+			if n == 0 {
+				first = image.Point{i / 4 % want.Rect.Dx(), i / 4 / want.Rect.Dx()}
+			}
+			n++
+			i |= 3
+		}
+	}
+	return n, first
+}
+
+func fill(p gkit.Painter, c gkit.Color, x, y, w, h uint32) {
+	p.SetColor(c)
+	p.DrawRect(gkit.Rect{Point: gkit.Point{X: x, Y: y}, Size: gkit.Size{Width: w, Height: h}})
+}
+
+func TestCompositedLayersMatchSoft(t *testing.T) {
+	makeTestContextCurrent(t)
+
+	size := gkit.Size{Width: 64, Height: 48}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	blue := gkit.RGBA(0, 0, 255, 255)
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, red)
+	img.Set(1, 0, gkit.RGBA(0, 255, 0, 128))
+	img.Set(1, 1, gkit.RGBA(255, 255, 255, 64))
+	font, err := gkit.LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlapping := testLayer(func(p gkit.Painter) {
+		fill(p, red, 0, 0, 24, 24)
+		fill(p, blue, 12, 8, 24, 24)
+	})
+	for _, test := range []struct {
+		name  string
+		scene testLayer
+	}{
+		{"group opacity", func(p gkit.Painter) {
+			fill(p, white, 0, 0, 64, 48)
+			// The blue square covers the red one inside of the group.
+			p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 8, Y: 8}, Size: gkit.Size{Width: 40, Height: 32}},
+				overlapping, 0.5, gkit.BlendNormal)
+		}},
+		{"translucent over transparent", func(p gkit.Painter) {
+			p.DrawCompositedLayer(gkit.Rect{Size: size}, testLayer(func(p gkit.Painter) {
+				fill(p, gkit.RGBA(0, 255, 0, 128), 4, 4, 40, 24)
+				fill(p, gkit.RGBA(255, 0, 255, 64), 20, 12, 40, 24)
+			}), 0.75, gkit.BlendNormal)
+		}},
+		{"nested", func(p gkit.Painter) {
+			fill(p, gkit.RGBA(100, 100, 100, 255), 0, 0, 64, 48)
+			p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 4, Y: 4}, Size: gkit.Size{Width: 40, Height: 40}},
+				testLayer(func(p gkit.Painter) {
+					fill(p, red, 0, 0, 30, 30)
+					// Sticks out of the outer layer, which clips it.
+					p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 20, Y: 10}, Size: gkit.Size{Width: 40, Height: 20}},
+						overlapping, 0.5, gkit.BlendNormal)
+				}), 0.5, gkit.BlendNormal)
+		}},
+		{"blend modes", func(p gkit.Painter) {
+			fill(p, gkit.RGBA(255, 255, 0, 255), 0, 0, 32, 48)
+			fill(p, gkit.RGBA(0, 255, 255, 255), 32, 0, 32, 48)
+			p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 8, Y: 4}, Size: gkit.Size{Width: 48, Height: 16}},
+				testLayer(func(p gkit.Painter) {
+					fill(p, gkit.RGBA(128, 128, 128, 255), 0, 0, 48, 16)
+				}), 1, gkit.BlendMultiply)
+			p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 8, Y: 28}, Size: gkit.Size{Width: 48, Height: 16}},
+				testLayer(func(p gkit.Painter) {
+					fill(p, gkit.RGBA(128, 0, 128, 255), 0, 0, 48, 16)
+				}), 0.5, gkit.BlendScreen)
+		}},
+		{"anti-aliased content", func(p gkit.Painter) {
+			fill(p, gkit.RGBA(0, 0, 128, 255), 0, 0, 64, 48)
+			p.DrawCompositedLayer(gkit.Rect{Size: size}, testLayer(func(p gkit.Painter) {
+				p.SetColor(gkit.RGBA(255, 128, 0, 192))
+				p.FillRoundedRect(gkit.Rect{Point: gkit.Point{X: 4, Y: 4}, Size: gkit.Size{Width: 30, Height: 20}},
+					gkit.CornerRadii{TopLeft: 8, TopRight: 8, BottomRight: 8, BottomLeft: 8})
+				p.DrawImage(gkit.Rect{Point: gkit.Point{X: 40, Y: 4}, Size: gkit.Size{Width: 2, Height: 2}}, img)
+				p.SetColor(white)
+				p.SetFont(font)
+				p.SetFontSize(18)
+				// A single glyph, the atlas rounds the subpixel positions
+				// of the ones after it.
+				p.DrawText(gkit.Point{X: 4, Y: 26}, "g")
+			}), 0.75, gkit.BlendNormal)
+		}},
+		{"clipped and moved", func(p gkit.Painter) {
+			fill(p, white, 0, 0, 64, 48)
+			p.PushClip(gkit.Rect{Size: gkit.Size{Width: 40, Height: 48}})
+			p.Translate(12, 6)
+			p.DrawCompositedLayer(gkit.Rect{Size: gkit.Size{Width: 40, Height: 32}}, overlapping, 0.5, gkit.BlendNormal)
+			p.PopClip()
+		}},
+		{"rotated and skewed clips", func(p gkit.Painter) {
+			fill(p, white, 0, 0, 64, 48)
+			p.Save()
+			p.Translate(24, 24)
+			p.Rotate(0.5)
+			p.Translate(-14, -14)
+			p.PushClip(gkit.Rect{Size: gkit.Size{Width: 28, Height: 28}})
+			p.Restore()
+			fill(p, red, 0, 0, 64, 48)
+			// Nested in the rotated clip.
+			p.Save()
+			p.Translate(20, 8)
+			p.Skew(0.4, 0)
+			p.PushClip(gkit.Rect{Size: gkit.Size{Width: 24, Height: 32}})
+			p.Restore()
+			p.DrawCompositedLayer(gkit.Rect{Point: gkit.Point{X: 8, Y: 4}, Size: gkit.Size{Width: 48, Height: 40}},
+				overlapping, 0.5, gkit.BlendNormal)
+			p.DrawLayer(gkit.Rect{Point: gkit.Point{X: 30, Y: 20}, Size: gkit.Size{Width: 30, Height: 28}},
+				testLayer(func(p gkit.Painter) { fill(p, blue, 0, 0, 30, 28) }))
+			p.PopClip()
+			p.PopClip()
+		}},
+	} {
+		want := renderSoft(size, test.scene)
+		got := renderGL(t, size, test.scene)
+		if n, p := compareImages(got, want); n > 0 {
+			t.Errorf("%s: %d pixels differ from the soft backend, first at %v: %v, want %v",
+				test.name, n, p, got.RGBAAt(p.X, p.Y), want.RGBAAt(p.X, p.Y))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package gl
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// makeTestContextCurrent makes the GL context of a hidden window current
+// on the thread of the test until it ends, or skips the test if there is
+// no display to create the window on.
+func makeTestContextCurrent(t *testing.T) {
+	t.Helper()
+	runtime.LockOSThread()
+	t.Cleanup(runtime.UnlockOSThread)
+
+	if err := initGLFW(); err != nil {
+		t.Skipf("no GL context: %v", err)
+	}
+	t.Cleanup(glfw.Terminate)
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	contextHints()
+	window, err := glfw.CreateWindow(16, 16, "test", nil, nil)
+	if err != nil {
+		t.Skipf("no GL context: %v", err)
+	}
+	t.Cleanup(window.Destroy)
+	window.MakeContextCurrent()
+	if err := gl.Init(); err != nil {
+		t.Skipf("no GL context: %v", err)
+	}
+}
+
+// initGLFW initializes glfw, which panics on the errors it doesn't expect,
+// like a missing display.
+func initGLFW() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return glfw.Init()
+}
@@ -1,8 +1,6 @@
 package gl
 
 import (
-	"image"
-
 	"github.com/go-gl/gl/v3.2-core/gl"
 
 	"github.com/alex-ac/gkit"
@@ -15,9 +13,21 @@ layout(location = 0) in vec4 vPosition;
 layout(location = 1) in vec4 vColorIn;
 layout(location = 2) in vec2 vUVIn;
 layout(location = 3) in vec3 vImageUVIn;
+layout(location = 4) in vec2 vShapePositionIn;
+layout(location = 5) in vec4 vShapeSizeIn;
+layout(location = 6) in vec4 vShapeRadiiIn;
+layout(location = 7) in vec4 vShapeShadowIn;
+layout(location = 8) in vec2 vGradientPositionIn;
+layout(location = 9) in vec2 vGradientRampIn;
 out vec4 vColor;
 out vec2 vUV;
 out vec3 vImageUV;
+out vec2 vShapePosition;
+flat out vec4 vShapeSize;
+flat out vec4 vShapeRadii;
+flat out vec4 vShapeShadow;
+out vec2 vGradientPosition;
+flat out vec2 vGradientRamp;
 
 uniform uvec4 viewportSize;
 
@@ -31,6 +41,12 @@ void main() {
   vColor = vColorIn;
   vUV = vUVIn;
   vImageUV = vImageUVIn;
+  vShapePosition = vShapePositionIn;
+  vShapeSize = vShapeSizeIn;
+  vShapeRadii = vShapeRadiiIn;
+  vShapeShadow = vShapeShadowIn;
+  vGradientPosition = vGradientPositionIn;
+  vGradientRamp = vGradientRampIn;
 }
 `
 	glPainterFragmentShaderSource = `
@@ -39,20 +55,143 @@ void main() {
 in vec4 vColor;
 in vec2 vUV;
 in vec3 vImageUV;
+// The position relative to the center of the rounded rect, its half
+// size, border width and blur sigma, its corner radii, and the kind of
+// the shape with the offset and spread of shadows.
+in vec2 vShapePosition;
+flat in vec4 vShapeSize;
+flat in vec4 vShapeRadii;
+flat in vec4 vShapeShadow;
+// The position in the coordinates of the gradient, the kind of the
+// gradient and its row in the gradients texture.
+in vec2 vGradientPosition;
+flat in vec2 vGradientRamp;
 
 out vec4 fColor;
 
 uniform uvec2 maskSize;
 uniform sampler2D mask;
-uniform sampler2DArray images;
+uniform sampler2D image;
+uniform uvec2 gradientsSize;
+uniform sampler2D gradients;
+// linear is set when colors are blended in linear light, into an sRGB
+// framebuffer.
+uniform bool linear;
+
+const float shapeRoundedRect = 1.0;
+const float shapeShadow = 2.0;
+const float shapeInsetShadow = 3.0;
+const float gradientLinear = 1.0;
+
+vec3 toLinear(vec3 c) {
+  return mix(c / 12.92, pow((c + 0.055) / 1.055, vec3(2.4)), step(0.04045, c));
+}
+
+// premultiply turns a straight sRGB color into a premultiplied one in the
+// space colors are blended in.
+vec4 premultiply(vec4 c) {
+  if (linear) {
+    c.rgb = toLinear(c.rgb);
+  }
+  return vec4(c.rgb * c.a, c.a);
+}
+
+float cornerRadius(vec2 p, vec4 radii) {
+  return p.x > 0.0 ? (p.y > 0.0 ? radii.z : radii.y) : (p.y > 0.0 ? radii.w : radii.x);
+}
+
+float roundedRectDistance(vec2 p, vec2 halfSize, vec4 radii) {
+  float r = cornerRadius(p, radii);
+  vec2 q = abs(p) - halfSize + r;
+  return min(max(q.x, q.y), 0.0) + length(max(q, 0.0)) - r;
+}
+
+float edgeCoverage(float distance, float pixel) {
+  return clamp(0.5 - distance / pixel, 0.0, 1.0);
+}
+
+vec2 erf(vec2 x) {
+  vec2 s = sign(x);
+  vec2 a = abs(x);
+  x = 1.0 + (0.278393 + (0.230389 + 0.078108 * (a * a)) * a) * a;
+  x *= x;
+  return s - s / (x * x);
+}
+
+float blurredRoundedRect(vec2 p, vec2 halfSize, vec4 radii, float sigma) {
+  float corner = min(cornerRadius(p, radii), min(halfSize.x, halfSize.y));
+  float low = p.y - halfSize.y;
+  float high = p.y + halfSize.y;
+  float start = clamp(-3.0 * sigma, low, high);
+  float end = clamp(3.0 * sigma, low, high);
+  float stride = (end - start) / 4.0;
+  float y = start + stride * 0.5;
+  float value = 0.0;
+  for (int i = 0; i < 4; i++) {
+    float row = p.y - y;
+    float delta = min(halfSize.y - corner - abs(row), 0.0);
+    float curved = halfSize.x - corner + sqrt(max(0.0, corner * corner - delta * delta));
+    vec2 integral = 0.5 + 0.5 * erf((p.x + vec2(-curved, curved)) * (sqrt(0.5) / sigma));
+    float gaussian = exp(-y * y / (2.0 * sigma * sigma)) / (sqrt(2.0 * 3.14159265) * sigma);
+    value += (integral.y - integral.x) * gaussian * stride;
+    y += stride;
+  }
+  return value;
+}
+
+float shapeCoverage(float pixel) {
+  float kind = vShapeShadow.x;
+  vec2 halfSize = vShapeSize.xy;
+  float box = edgeCoverage(roundedRectDistance(vShapePosition, halfSize, vShapeRadii), pixel);
+  if (kind == shapeRoundedRect) {
+    float border = vShapeSize.z;
+    if (border > 0.0) {
+      vec4 inner = vShapeRadii - border;
+      inner = mix(vec4(0.0), max(inner, 0.0), greaterThan(vShapeRadii, vec4(0.0)));
+      box *= 1.0 - edgeCoverage(roundedRectDistance(vShapePosition, halfSize - border, inner), pixel);
+    }
+    return box;
+  }
+
+  float spread = kind == shapeInsetShadow ? -vShapeShadow.w : vShapeShadow.w;
+  vec2 shadowHalfSize = max(halfSize + spread, 0.0);
+  vec4 shadowRadii = mix(vec4(0.0), max(vShapeRadii + spread, 0.0), greaterThan(vShapeRadii, vec4(0.0)));
+  vec2 p = vShapePosition - vShapeShadow.yz;
+  float sigma = vShapeSize.w;
+  float shadow = sigma > 0.0 ?
+      blurredRoundedRect(p, shadowHalfSize, shadowRadii, sigma) :
+      edgeCoverage(roundedRectDistance(p, shadowHalfSize, shadowRadii), pixel);
+  return kind == shapeInsetShadow ? box * (1.0 - shadow) : shadow * (1.0 - box);
+}
 
 void main() {
+  // The size of a pixel in the coordinates of the shape, computed before
+  // any branch for the derivatives to be defined.
+  float pixel = max(length(fwidth(vShapePosition)) * sqrt(0.5), 1e-6);
   vec2 uv = vUV / maskSize;
-  fColor = vColor;
+  // The output is premultiplied and in the space colors are blended in.
+  fColor = premultiply(vColor);
+  if (vGradientRamp.x > 0.0) {
+    float offset = vGradientRamp.x == gradientLinear ? vGradientPosition.x : length(vGradientPosition);
+    vec2 size = vec2(gradientsSize);
+    fColor = premultiply(texture(gradients, vec2(
+        (clamp(offset, 0.0, 1.0) * (size.x - 1.0) + 0.5) / size.x,
+        (vGradientRamp.y + 0.5) / size.y)));
+  }
   if (vImageUV.z >= 0) {
-    fColor = texture(images, vec3(vImageUV));
+    // Images are premultiplied sRGB, layers are in the blending space
+    // already.
+    fColor = texture(image, vImageUV.xy);
+    if (linear && vImageUV.z < 0.5 && fColor.a > 0) {
+      fColor = premultiply(vec4(fColor.rgb / fColor.a, fColor.a));
+    }
+    fColor *= vColor.a;
+  }
+  float coverage = texture(mask, uv).r;
+  if (vShapeShadow.x > 0.0) {
+    coverage *= shapeCoverage(pixel);
   }
-  fColor.a *= texture(mask, uv).r;
+  fColor *= coverage;
   if (fColor.a == 0) {
     discard;
   }
@@ -65,6 +204,12 @@ const (
 	attrColors
 	attrUv
 	attrImageUv
+	attrShapePosition
+	attrShapeSize
+	attrShapeRadii
+	attrShapeShadow
+	attrGradientPosition
+	attrGradientRamp
 
 	attrFloatSize     = 4
 	attrCoordsCount   = 3
@@ -80,7 +225,27 @@ const (
 	attrImageUvOffset = attrUvOffset + attrUvSize
 	attrImageUvSize   = attrImageUvCount * attrFloatSize
 
-	attrStride = attrImageUvOffset + attrImageUvSize
+	attrShapePositionCount  = 2
+	attrShapePositionOffset = attrImageUvOffset + attrImageUvSize
+	attrShapePositionSize   = attrShapePositionCount * attrFloatSize
+	attrShapeSizeCount      = 4
+	attrShapeSizeOffset     = attrShapePositionOffset + attrShapePositionSize
+	attrShapeSizeSize       = attrShapeSizeCount * attrFloatSize
+	attrShapeRadiiCount     = 4
+	attrShapeRadiiOffset    = attrShapeSizeOffset + attrShapeSizeSize
+	attrShapeRadiiSize      = attrShapeRadiiCount * attrFloatSize
+	attrShapeShadowCount    = 4
+	attrShapeShadowOffset   = attrShapeRadiiOffset + attrShapeRadiiSize
+	attrShapeShadowSize     = attrShapeShadowCount * attrFloatSize
+
+	attrGradientPositionCount  = 2
+	attrGradientPositionOffset = attrShapeShadowOffset + attrShapeShadowSize
+	attrGradientPositionSize   = attrGradientPositionCount * attrFloatSize
+	attrGradientRampCount      = 2
+	attrGradientRampOffset     = attrGradientPositionOffset + attrGradientPositionSize
+	attrGradientRampSize       = attrGradientRampCount * attrFloatSize
+
+	attrStride = attrGradientRampOffset + attrGradientRampSize
 )
 
 type drawingContext struct {
@@ -89,17 +254,31 @@ type drawingContext struct {
 	vao uint32
 	vbo uint32
 
-	viewportSizeLocation int32
-	maskLocation         int32
-	maskSizeLocation     int32
-	imagesLocation       int32
-
-	texture      uint32
-	textureArray uint32
-	sampler      uint32
-	samplerArray uint32
+	viewportSizeLocation  int32
+	maskLocation          int32
+	maskSizeLocation      int32
+	imageLocation         int32
+	gradientsLocation     int32
+	gradientsSizeLocation int32
+	linearLocation        int32
+
+	texture          uint32
+	gradientsTexture uint32
+	sampler          uint32
+	imageSampler     uint32
+
+	glyphs    *glyphAtlas
+	images    *imageCache
+	gradients *gradientRamps
+
+	// framebuffer renders composited layers into their textures, which
+	// are kept for the next frame when they are used in this one.
+	framebuffer       uint32
+	freeGroupTextures []*texture
+	usedGroupTextures []*texture
 
 	scaleFactor float32
+	linear      bool
 }
 
 func newDrawingContext() (*drawingContext, error) {
@@ -168,9 +347,24 @@ func newDrawingContext() (*drawingContext, error) {
 		return nil, glPainterGetUniformLocationError("maskSize")
 	}
 
-	imagesLocation := gl.GetUniformLocation(program, gl.Str("images\x00"))
-	if imagesLocation < 0 {
-		return nil, glPainterGetUniformLocationError("images")
+	imageLocation := gl.GetUniformLocation(program, gl.Str("image\x00"))
+	if imageLocation < 0 {
+		return nil, glPainterGetUniformLocationError("image")
+	}
+
+	gradientsLocation := gl.GetUniformLocation(program, gl.Str("gradients\x00"))
+	if gradientsLocation < 0 {
+		return nil, glPainterGetUniformLocationError("gradients")
+	}
+
+	gradientsSizeLocation := gl.GetUniformLocation(program, gl.Str("gradientsSize\x00"))
+	if gradientsSizeLocation < 0 {
+		return nil, glPainterGetUniformLocationError("gradientsSize")
+	}
+
+	linearLocation := gl.GetUniformLocation(program, gl.Str("linear\x00"))
+	if linearLocation < 0 {
+		return nil, glPainterGetUniformLocationError("linear")
 	}
 
 	var textures [2]uint32
@@ -185,6 +379,7 @@ func newDrawingContext() (*drawingContext, error) {
 			gl.DeleteTextures(int32(len(textures)), &textures[0])
 		}
 	}()
+	texture, gradientsTexture := textures[0], textures[1]
 
 	var samplers [2]uint32
 	gl.GenSamplers(int32(len(samplers)), &samplers[0])
@@ -201,39 +396,56 @@ func newDrawingContext() (*drawingContext, error) {
 	sampler := samplers[0]
 	gl.SamplerParameteri(sampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 	gl.SamplerParameteri(sampler, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-	arraySampler := samplers[1]
-	gl.SamplerParameteri(arraySampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.SamplerParameteri(arraySampler, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	imageSampler := samplers[1]
+	gl.SamplerParameteri(imageSampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.SamplerParameteri(imageSampler, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.SamplerParameteri(imageSampler, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.SamplerParameteri(imageSampler, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 
 	ok = true
 	return &drawingContext{
-		program:              program,
-		vao:                  vao,
-		vbo:                  vbo,
-		viewportSizeLocation: viewportSizeLocation,
-		maskLocation:         maskLocation,
-		maskSizeLocation:     maskSizeLocation,
-		imagesLocation:       imagesLocation,
-		texture:              textures[0],
-		textureArray:         textures[1],
-		sampler:              sampler,
-		samplerArray:         arraySampler,
-		scaleFactor:          1,
+		program:               program,
+		vao:                   vao,
+		vbo:                   vbo,
+		viewportSizeLocation:  viewportSizeLocation,
+		maskLocation:          maskLocation,
+		maskSizeLocation:      maskSizeLocation,
+		imageLocation:         imageLocation,
+		gradientsLocation:     gradientsLocation,
+		gradientsSizeLocation: gradientsSizeLocation,
+		linearLocation:        linearLocation,
+		texture:               texture,
+		gradientsTexture:      gradientsTexture,
+		sampler:               sampler,
+		imageSampler:          imageSampler,
+		glyphs:                newGlyphAtlas(),
+		images:                newImageCache(),
+		gradients:             newGradientRamps(),
+		scaleFactor:           1,
 	}, nil
 }
 
 func (g *drawingContext) Destroy() {
 	gl.BindVertexArray(g.vao)
-	textures := []uint32{
-		g.texture,
-		g.textureArray,
-	}
 	samplers := []uint32{
 		g.sampler,
-		g.samplerArray,
+		g.imageSampler,
 	}
 	gl.DeleteSamplers(int32(len(samplers)), &samplers[0])
+	textures := []uint32{
+		g.texture,
+		g.gradientsTexture,
+	}
 	gl.DeleteTextures(int32(len(textures)), &textures[0])
+	g.images.destroy()
+	for _, t := range append(g.freeGroupTextures, g.usedGroupTextures...) {
+		if t.id != 0 {
+			gl.DeleteTextures(1, &t.id)
+		}
+	}
+	if g.framebuffer != 0 {
+		gl.DeleteFramebuffers(1, &g.framebuffer)
+	}
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	gl.DeleteBuffers(1, &g.vbo)
 	gl.BindVertexArray(0)
@@ -242,35 +454,28 @@ func (g *drawingContext) Destroy() {
 	gl.DeleteProgram(g.program)
 }
 
-func nearestPowerOf2(x uint32) uint32 {
-	if x == 0 {
-		return 1
-	}
-	return nearestPowerOf2(x>>1) + 1
-}
-
-func textureSideSize(s gkit.Size) uint32 {
-	if s.Width > s.Height {
-		return 1 << nearestPowerOf2(s.Width)
-	}
-	return 1 << nearestPowerOf2(s.Height)
+// SetLinearBlending makes colors blend in linear light rather than in sRGB,
+// which needs the framebuffer to be sRGB, like the ones of windows are.
+// Composited layers keep linear colors in 8 bits then, dark ones lose
+// precision.
+func (g *drawingContext) SetLinearBlending(linear bool) {
+	g.linear = linear
 }
 
 func (g *drawingContext) BeginPaint(size gkit.Size) gkit.Painter {
-	maskSideSize := textureSideSize(size)
-	mask := image.NewGray(image.Rectangle{
-		Max: image.Point{int(float32(maskSideSize) * g.scaleFactor), int(float32(maskSideSize) * g.scaleFactor)},
-	})
-	mask.Pix[0] = 0xff
-	return &painter{
+	p := &painter{
 		context:      g,
-		mask:         mask,
-		images:       make([]*image.RGBA, 0),
 		size:         size,
-		vertices:     make([]float32, 0),
+		pass:         &pass{},
 		scaleFactor:  g.scaleFactor,
+		clip:         noClip,
 		instructions: make([]instruction, 0),
 	}
+	if g.glyphs.beginFrame() {
+		p.enableRedraw()
+	}
+	g.gradients.reset()
+	return p
 }
 
 func (g *drawingContext) EndPaint(gkitPainter gkit.Painter) {
@@ -278,7 +483,7 @@ func (g *drawingContext) EndPaint(gkitPainter gkit.Painter) {
 	if !p.doRedraw {
 		return
 	}
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
 	if len(p.vertices) == 0 {
 		return
 	}
@@ -294,7 +499,6 @@ func (g *drawingContext) EndPaint(gkitPainter gkit.Painter) {
 	gl.BindBuffer(gl.ARRAY_BUFFER, g.vbo)
 	defer gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 
-	gl.BufferData(gl.ARRAY_BUFFER, len(p.vertices)*4, gl.Ptr(p.vertices), gl.STREAM_DRAW)
 	gl.VertexAttribPointer(
 		attrCoords, attrCoordsCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrCoordsOffset))
 	gl.VertexAttribPointer(
@@ -303,49 +507,72 @@ func (g *drawingContext) EndPaint(gkitPainter gkit.Painter) {
 		attrUv, attrUvCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrUvOffset))
 	gl.VertexAttribPointer(
 		attrImageUv, attrImageUvCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrImageUvOffset))
+	gl.VertexAttribPointer(
+		attrShapePosition, attrShapePositionCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrShapePositionOffset))
+	gl.VertexAttribPointer(
+		attrShapeSize, attrShapeSizeCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrShapeSizeOffset))
+	gl.VertexAttribPointer(
+		attrShapeRadii, attrShapeRadiiCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrShapeRadiiOffset))
+	gl.VertexAttribPointer(
+		attrShapeShadow, attrShapeShadowCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrShapeShadowOffset))
+	gl.VertexAttribPointer(
+		attrGradientPosition, attrGradientPositionCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrGradientPositionOffset))
+	gl.VertexAttribPointer(
+		attrGradientRamp, attrGradientRampCount, gl.FLOAT, false, attrStride, gl.PtrOffset(attrGradientRampOffset))
 	gl.EnableVertexAttribArray(attrCoords)
 	gl.EnableVertexAttribArray(attrColors)
 	gl.EnableVertexAttribArray(attrUv)
 	gl.EnableVertexAttribArray(attrImageUv)
+	gl.EnableVertexAttribArray(attrShapePosition)
+	gl.EnableVertexAttribArray(attrShapeSize)
+	gl.EnableVertexAttribArray(attrShapeRadii)
+	gl.EnableVertexAttribArray(attrShapeShadow)
+	gl.EnableVertexAttribArray(attrGradientPosition)
+	gl.EnableVertexAttribArray(attrGradientRamp)
 
 	gl.Uniform1i(g.maskLocation, 0)
 
 	gl.ActiveTexture(gl.TEXTURE0)
 
 	gl.BindTexture(gl.TEXTURE_2D, g.texture)
-	gl.TexImage2D(
-		gl.TEXTURE_2D, 0, gl.RED, int32(p.mask.Rect.Max.X), int32(p.mask.Rect.Max.Y), 0,
-		gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(p.mask.Pix))
+	g.glyphs.upload()
 
 	gl.BindSampler(0, g.sampler)
 	defer gl.BindSampler(0, 0)
 
-	gl.Uniform1i(g.imagesLocation, 1)
+	gl.Uniform1i(g.gradientsLocation, 2)
+	gl.ActiveTexture(gl.TEXTURE2)
+	gl.BindTexture(gl.TEXTURE_2D, g.gradientsTexture)
+	g.gradients.upload()
+	gl.Uniform2ui(g.gradientsSizeLocation, gradientRampWidth, uint32(g.gradients.height()))
+
+	gl.BindSampler(2, g.imageSampler)
+	defer gl.BindSampler(2, 0)
+
+	gl.Uniform1i(g.imageLocation, 1)
 	gl.ActiveTexture(gl.TEXTURE1)
-	gl.BindTexture(gl.TEXTURE_2D_ARRAY, g.textureArray)
-	var images []uint8
-	for _, image := range p.images {
-		if images == nil {
-			images = image.Pix
-		} else {
-			images = append(images, image.Pix...)
-		}
-	}
-	if len(images) > 0 {
-		gl.TexImage3D(
-			gl.TEXTURE_2D_ARRAY, 0, gl.RGBA,
-			int32(p.mask.Rect.Max.X), int32(p.mask.Rect.Max.Y), int32(len(p.images)), 0,
-			gl.RGBA, gl.UNSIGNED_INT_8_8_8_8_REV, gl.Ptr(images))
-	}
+	g.images.upload()
 
-	gl.BindSampler(1, g.samplerArray)
+	gl.BindSampler(1, g.imageSampler)
 	defer gl.BindSampler(1, 0)
 
+	side := uint32(g.glyphs.side())
+	gl.Uniform2ui(g.maskSizeLocation, side, side)
+
+	if g.linear {
+		gl.Uniform1i(g.linearLocation, 1)
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+		defer gl.Disable(gl.FRAMEBUFFER_SRGB)
+	} else {
+		gl.Uniform1i(g.linearLocation, 0)
+	}
+
 	gl.Enablei(gl.BLEND, 0)
 	gl.BlendEquationSeparate(gl.FUNC_ADD, gl.FUNC_ADD)
-	gl.BlendFuncSeparate(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ZERO)
-
-	gl.Uniform2ui(g.maskSizeLocation, uint32(float32(p.mask.Rect.Max.X)/p.scaleFactor), uint32(float32(p.mask.Rect.Max.Y)/p.scaleFactor))
+	g.drawGroups(p.groups)
+	g.drawPass(p.pass)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
 
-	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(p.vertices)/8))
+	g.images.collect()
+	g.collectGroupTextures()
 }
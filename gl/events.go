@@ -0,0 +1,145 @@
+package gl
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"github.com/alex-ac/gkit"
+)
+
+var keys = map[glfw.Key]gkit.Key{
+	glfw.KeySpace:        gkit.KeySpace,
+	glfw.KeyEnter:        gkit.KeyEnter,
+	glfw.KeyKPEnter:      gkit.KeyEnter,
+	glfw.KeyTab:          gkit.KeyTab,
+	glfw.KeyBackspace:    gkit.KeyBackspace,
+	glfw.KeyDelete:       gkit.KeyDelete,
+	glfw.KeyEscape:       gkit.KeyEscape,
+	glfw.KeyInsert:       gkit.KeyInsert,
+	glfw.KeyLeft:         gkit.KeyArrowLeft,
+	glfw.KeyRight:        gkit.KeyArrowRight,
+	glfw.KeyUp:           gkit.KeyArrowUp,
+	glfw.KeyDown:         gkit.KeyArrowDown,
+	glfw.KeyHome:         gkit.KeyHome,
+	glfw.KeyEnd:          gkit.KeyEnd,
+	glfw.KeyPageUp:       gkit.KeyPageUp,
+	glfw.KeyPageDown:     gkit.KeyPageDown,
+	glfw.KeyLeftShift:    gkit.KeyShift,
+	glfw.KeyRightShift:   gkit.KeyShift,
+	glfw.KeyLeftControl:  gkit.KeyControl,
+	glfw.KeyRightControl: gkit.KeyControl,
+	glfw.KeyLeftAlt:      gkit.KeyAlt,
+	glfw.KeyRightAlt:     gkit.KeyAlt,
+	glfw.KeyLeftSuper:    gkit.KeySuper,
+	glfw.KeyRightSuper:   gkit.KeySuper,
+}
+
+func convertKey(key glfw.Key) gkit.Key {
+	switch {
+	case key >= glfw.KeyA && key <= glfw.KeyZ:
+		return gkit.KeyA + gkit.Key(key-glfw.KeyA)
+	case key >= glfw.Key0 && key <= glfw.Key9:
+		return gkit.Key0 + gkit.Key(key-glfw.Key0)
+	}
+	if k, ok := keys[key]; ok {
+		return k
+	}
+	return gkit.KeyUnknown
+}
+
+func convertModifiers(mods glfw.ModifierKey) gkit.Modifiers {
+	var m gkit.Modifiers
+	if mods&glfw.ModShift != 0 {
+		m |= gkit.ModShift
+	}
+	if mods&glfw.ModControl != 0 {
+		m |= gkit.ModControl
+	}
+	if mods&glfw.ModAlt != 0 {
+		m |= gkit.ModAlt
+	}
+	if mods&glfw.ModSuper != 0 {
+		m |= gkit.ModSuper
+	}
+	return m
+}
+
+func convertButton(button glfw.MouseButton) gkit.MouseButton {
+	switch button {
+	case glfw.MouseButtonRight:
+		return gkit.MouseButtonRight
+	case glfw.MouseButtonMiddle:
+		return gkit.MouseButtonMiddle
+	}
+	return gkit.MouseButtonLeft
+}
+
+func convertPosition(x, y float64) gkit.Point {
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return gkit.Point{X: uint32(x), Y: uint32(y)}
+}
+
+func (w *Window) setupCallbacks() {
+	w.window.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
+		w.cursor = convertPosition(x, y)
+		w.DispatchEvent(&gkit.MouseEvent{
+			Type:      gkit.MouseMove,
+			Position:  w.cursor,
+			Modifiers: w.modifiers,
+		})
+	})
+	w.window.SetCursorEnterCallback(func(_ *glfw.Window, entered bool) {
+		eventType := gkit.MouseLeave
+		if entered {
+			eventType = gkit.MouseEnter
+		}
+		w.DispatchEvent(&gkit.MouseEvent{
+			Type:      eventType,
+			Position:  w.cursor,
+			Modifiers: w.modifiers,
+		})
+	})
+	w.window.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		w.modifiers = convertModifiers(mods)
+		eventType := gkit.MouseDown
+		if action == glfw.Release {
+			eventType = gkit.MouseUp
+		}
+		w.DispatchEvent(&gkit.MouseEvent{
+			Type:      eventType,
+			Position:  w.cursor,
+			Button:    convertButton(button),
+			Modifiers: w.modifiers,
+		})
+	})
+	w.window.SetScrollCallback(func(_ *glfw.Window, x, y float64) {
+		w.DispatchEvent(&gkit.ScrollEvent{
+			Position:  w.cursor,
+			DeltaX:    float32(x),
+			DeltaY:    float32(y),
+			Modifiers: w.modifiers,
+		})
+	})
+	w.window.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		w.modifiers = convertModifiers(mods)
+		eventType := gkit.KeyDown
+		if action == glfw.Release {
+			eventType = gkit.KeyUp
+		}
+		w.DispatchEvent(&gkit.KeyEvent{
+			Type:      eventType,
+			Key:       convertKey(key),
+			Modifiers: w.modifiers,
+			Repeat:    action == glfw.Repeat,
+		})
+	})
+	w.window.SetCharCallback(func(_ *glfw.Window, char rune) {
+		w.DispatchEvent(&gkit.TextInputEvent{
+			Text: string(char),
+		})
+	})
+}
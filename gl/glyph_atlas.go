@@ -0,0 +1,160 @@
+package gl
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+
+	"github.com/alex-ac/gkit"
+)
+
+const (
+	glyphAtlasInitialSize = 512
+	glyphAtlasMaxSize     = 4096
+	// glyphSubpixelSteps is the number of horizontal positions within a
+	// pixel a glyph is rasterized at.
+	glyphSubpixelSteps = 4
+	glyphPadding       = 1
+)
+
+type glyphKey struct {
+	font     *gkit.Font
+	size     uint32
+	r        rune
+	subpixel uint8
+}
+
+type glyph struct {
+	// rect is where the glyph is in the atlas, empty for glyphs without
+	// pixels. offset is the position of rect's corner relative to the pen.
+	rect   image.Rectangle
+	offset image.Point
+}
+
+// glyphAtlas keeps rasterized glyphs in a single texture that lives as long
+// as the drawing context. The atlas doubles in size when it's full.
+type glyphAtlas struct {
+	image  *image.Alpha
+	glyphs map[glyphKey]glyph
+	packer shelfPacker
+
+	// dirty is the part of image that isn't uploaded yet, resized is set
+	// when the whole texture has to be uploaded.
+	dirty   image.Rectangle
+	resized bool
+	// overflowed is set when a glyph didn't fit into the atlas of the
+	// maximal size. The atlas is cleared before the next frame then.
+	// retried is set once a frame was drawn again into a cleared atlas;
+	// if that frame overflows too, the glyphs that don't fit are dropped
+	// instead of redrawing every frame.
+	overflowed bool
+	retried    bool
+}
+
+func newGlyphAtlas() *glyphAtlas {
+	a := &glyphAtlas{}
+	a.reset(glyphAtlasInitialSize)
+	return a
+}
+
+func (a *glyphAtlas) reset(side int) {
+	a.image = image.NewAlpha(image.Rect(0, 0, side, side))
+	// The top-left texel is opaque, everything that isn't text samples it.
+	a.image.Pix[0] = 0xff
+	a.glyphs = make(map[glyphKey]glyph)
+	a.packer = newShelfPacker(side)
+	a.packer.allocate(image.Point{1 + glyphPadding, 1 + glyphPadding})
+	a.dirty = image.Rectangle{}
+	a.resized = true
+	a.overflowed = false
+}
+
+// beginFrame clears the atlas if the last frame overflowed it and reports
+// whether the frame has to be drawn again to show the skipped glyphs.
+func (a *glyphAtlas) beginFrame() bool {
+	if !a.overflowed {
+		a.retried = false
+		return false
+	}
+	redraw := !a.retried
+	a.reset(a.side())
+	a.retried = true
+	return redraw
+}
+
+func (a *glyphAtlas) side() int {
+	return a.image.Rect.Dx()
+}
+
+// glyph returns the glyph of r rasterized with the pen dx pixels right of
+// a pixel boundary, adding it to the atlas if needed.
+func (a *glyphAtlas) glyph(font *gkit.Font, size uint32, r rune, dx float32) (glyph, bool) {
+	key := glyphKey{
+		font:     font,
+		size:     size,
+		r:        r,
+		subpixel: uint8(dx * glyphSubpixelSteps),
+	}
+	if g, ok := a.glyphs[key]; ok {
+		return g, true
+	}
+
+	mask, offset := font.GlyphMask(size, r, float32(key.subpixel)/glyphSubpixelSteps)
+	g := glyph{offset: offset}
+	if mask != nil {
+		at, ok := a.allocate(mask.Rect.Size())
+		if !ok {
+			a.overflowed = true
+			return glyph{}, false
+		}
+		g.rect = mask.Rect.Add(at)
+		draw.Draw(a.image, g.rect, mask, image.Point{}, draw.Src)
+		a.dirty = a.dirty.Union(g.rect)
+	}
+	a.glyphs[key] = g
+	return g, true
+}
+
+func (a *glyphAtlas) allocate(size image.Point) (image.Point, bool) {
+	size = size.Add(image.Point{glyphPadding, glyphPadding})
+	for {
+		if at, ok := a.packer.allocate(size); ok {
+			return at, true
+		}
+		if a.side() >= glyphAtlasMaxSize {
+			return image.Point{}, false
+		}
+		a.grow()
+	}
+}
+
+// grow doubles the atlas side. Glyph coordinates are in pixels, so they
+// stay valid.
+func (a *glyphAtlas) grow() {
+	old := a.image
+	side := old.Rect.Dx() * 2
+	a.image = image.NewAlpha(image.Rect(0, 0, side, side))
+	draw.Draw(a.image, old.Rect, old, image.Point{}, draw.Src)
+	a.packer.side = side
+	a.resized = true
+}
+
+// upload sends the new glyphs to the texture bound to TEXTURE_2D.
+func (a *glyphAtlas) upload() {
+	side := int32(a.side())
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	if a.resized {
+		gl.TexImage2D(
+			gl.TEXTURE_2D, 0, gl.RED, side, side, 0,
+			gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(a.image.Pix))
+	} else if !a.dirty.Empty() {
+		// Whole rows are uploaded, so the row length of the atlas applies.
+		rows := a.image.Pix[a.dirty.Min.Y*a.image.Stride:]
+		gl.TexSubImage2D(
+			gl.TEXTURE_2D, 0, 0, int32(a.dirty.Min.Y), side, int32(a.dirty.Dy()),
+			gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(rows))
+	}
+	a.resized = false
+	a.dirty = image.Rectangle{}
+}
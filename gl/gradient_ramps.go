@@ -0,0 +1,121 @@
+package gl
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+
+	"github.com/alex-ac/gkit"
+)
+
+const (
+	gradientRampWidth     = 256
+	gradientInitialRows   = 16
+	gradientMaxRows       = 1024
+	gradientKindLinear    = 1
+	gradientKindRadial    = 2
+	gradientStopKeyLength = 8
+)
+
+// paint is how vertices are colored: with color, or with a row of the
+// gradient texture at the offsets toGradient maps root coordinates to.
+type paint struct {
+	color      [4]float32
+	kind       float32
+	row        float32
+	toGradient gkit.Transform
+}
+
+// paint returns the paint of b drawn with t mapping the drawing
+// coordinates into root ones. It must be called while instructions run.
+func (p *painter) paint(b gkit.Brush, t gkit.Transform) paint {
+	if b.IsSolid() {
+		return paint{color: vec4(b.Color())}
+	}
+	inverse, ok := t.Invert()
+	row, added := p.context.gradients.row(b)
+	if !ok || !added {
+		return paint{color: vec4(b.ColorAt(0))}
+	}
+	kind := float32(gradientKindLinear)
+	if b.IsRadial() {
+		kind = gradientKindRadial
+	}
+	return paint{kind: kind, row: float32(row), toGradient: b.GradientTransform().Mul(inverse)}
+}
+
+// gradientRamps holds the colors of the gradients drawn in a frame, one
+// row of the gradient texture per gradient. The rows are filled anew in
+// every frame that is redrawn.
+type gradientRamps struct {
+	image *image.NRGBA
+	rows  map[string]int
+	count int
+	// dirty is set when rows were added since the last upload.
+	dirty bool
+}
+
+func newGradientRamps() *gradientRamps {
+	r := &gradientRamps{
+		image: image.NewNRGBA(image.Rect(0, 0, gradientRampWidth, gradientInitialRows)),
+	}
+	r.reset()
+	return r
+}
+
+func (r *gradientRamps) reset() {
+	r.rows = make(map[string]int)
+	r.count = 0
+}
+
+func (r *gradientRamps) height() int {
+	return r.image.Rect.Dy()
+}
+
+// row returns the row of the gradient of b, false if there are no rows
+// left.
+func (r *gradientRamps) row(b gkit.Brush) (int, bool) {
+	stops := b.Stops()
+	key := make([]byte, 0, len(stops)*gradientStopKeyLength)
+	for _, stop := range stops {
+		offset := math.Float32bits(stop.Offset)
+		key = append(key,
+			byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset),
+			stop.Color.R(), stop.Color.G(), stop.Color.B(), stop.Color.A())
+	}
+	if row, ok := r.rows[string(key)]; ok {
+		return row, true
+	}
+	if r.count == r.height() {
+		if r.height() >= gradientMaxRows {
+			return 0, false
+		}
+		old := r.image
+		r.image = image.NewNRGBA(image.Rect(0, 0, gradientRampWidth, old.Rect.Dy()*2))
+		draw.Draw(r.image, old.Rect, old, image.Point{}, draw.Src)
+	}
+
+	row := r.count
+	r.count++
+	for x := 0; x < gradientRampWidth; x++ {
+		c := b.ColorAt(float32(x) / (gradientRampWidth - 1))
+		r.image.SetNRGBA(x, row, color.NRGBA{c.R(), c.G(), c.B(), c.A()})
+	}
+	r.rows[string(key)] = row
+	r.dirty = true
+	return row, true
+}
+
+// upload sends the gradients to the texture bound to TEXTURE_2D.
+func (r *gradientRamps) upload() {
+	if !r.dirty {
+		return
+	}
+	gl.TexImage2D(
+		gl.TEXTURE_2D, 0, gl.RGBA, gradientRampWidth, int32(r.height()), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(r.image.Pix))
+	r.dirty = false
+}
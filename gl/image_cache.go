@@ -0,0 +1,256 @@
+package gl
+
+import (
+	"image"
+	"image/draw"
+	"reflect"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+const (
+	imageAtlasSize = 1024
+	// Images not larger than this on both sides are packed into atlases,
+	// larger ones get textures of their own.
+	imageAtlasMaxImageSize = 256
+	// imageCacheMaxAge is the number of drawn frames an image is kept on
+	// the GPU without being drawn.
+	imageCacheMaxAge = 120
+)
+
+type texture struct {
+	id   uint32
+	size image.Point
+	// released textures are deleted, or never created if they weren't
+	// uploaded yet.
+	released bool
+	// layer textures are the ones composited layers are drawn into. Their
+	// colors are in the space colors are blended in, the ones of images
+	// are sRGB. Both are premultiplied.
+	layer bool
+}
+
+type cachedImage struct {
+	texture *texture
+	// page is the atlas the image is packed into, nil if the texture is
+	// the image's own.
+	page *imagePage
+	// rect is the image in texture pixels.
+	rect     image.Rectangle
+	lastUsed uint64
+}
+
+type imagePage struct {
+	texture *texture
+	packer  shelfPacker
+	images  int
+}
+
+type imageUpload struct {
+	texture *texture
+	at      image.Point
+	pixels  *image.RGBA
+}
+
+// imageCache keeps images drawn by the painter in textures between frames.
+// Images are identified by their value, so an image changed in place has to
+// be invalidated. All GL calls are made in upload and collect, which run
+// in EndPaint with the context current.
+type imageCache struct {
+	entries map[image.Image]*cachedImage
+	// transient images can't be map keys, they are uploaded for a single
+	// frame.
+	transient []*cachedImage
+	pages     []*imagePage
+	frame     uint64
+
+	uploads  []imageUpload
+	released []uint32
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{
+		entries: make(map[image.Image]*cachedImage),
+	}
+}
+
+// image returns the cached copy of img, scheduling an upload if it isn't
+// cached yet.
+func (c *imageCache) image(img image.Image) *cachedImage {
+	comparable := reflect.TypeOf(img).Comparable()
+	if comparable {
+		if cached, ok := c.entries[img]; ok {
+			cached.lastUsed = c.frame
+			return cached
+		}
+	}
+
+	cached := c.add(img)
+	if comparable {
+		c.entries[img] = cached
+	} else {
+		c.transient = append(c.transient, cached)
+	}
+	return cached
+}
+
+func (c *imageCache) add(img image.Image) *cachedImage {
+	bounds := img.Bounds()
+	size := bounds.Size()
+	if size.X > imageAtlasMaxImageSize || size.Y > imageAtlasMaxImageSize {
+		pixels := image.NewRGBA(image.Rectangle{Max: size})
+		draw.Draw(pixels, pixels.Rect, img, bounds.Min, draw.Src)
+		t := &texture{size: size}
+		c.uploads = append(c.uploads, imageUpload{texture: t, pixels: pixels})
+		return &cachedImage{
+			texture:  t,
+			rect:     pixels.Rect,
+			lastUsed: c.frame,
+		}
+	}
+
+	// Packed images get a border of repeated edge pixels, so that linear
+	// filtering doesn't pick up their neighbours.
+	pixels := image.NewRGBA(image.Rectangle{Max: size.Add(image.Point{2, 2})})
+	draw.Draw(pixels, pixels.Rect.Inset(1), img, bounds.Min, draw.Src)
+	extrude(pixels)
+
+	page, at := c.allocate(pixels.Rect.Size())
+	page.images++
+	c.uploads = append(c.uploads, imageUpload{texture: page.texture, at: at, pixels: pixels})
+	return &cachedImage{
+		texture:  page.texture,
+		page:     page,
+		rect:     pixels.Rect.Inset(1).Add(at),
+		lastUsed: c.frame,
+	}
+}
+
+func (c *imageCache) allocate(size image.Point) (*imagePage, image.Point) {
+	for _, page := range c.pages {
+		if at, ok := page.packer.allocate(size); ok {
+			return page, at
+		}
+	}
+	page := &imagePage{
+		texture: &texture{size: image.Point{imageAtlasSize, imageAtlasSize}},
+		packer:  newShelfPacker(imageAtlasSize),
+	}
+	c.pages = append(c.pages, page)
+	at, _ := page.packer.allocate(size)
+	return page, at
+}
+
+// extrude copies the pixels next to the one pixel border of img over it.
+func extrude(img *image.RGBA) {
+	r := img.Rect
+	for x := r.Min.X + 1; x < r.Max.X-1; x++ {
+		img.SetRGBA(x, r.Min.Y, img.RGBAAt(x, r.Min.Y+1))
+		img.SetRGBA(x, r.Max.Y-1, img.RGBAAt(x, r.Max.Y-2))
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.SetRGBA(r.Min.X, y, img.RGBAAt(r.Min.X+1, y))
+		img.SetRGBA(r.Max.X-1, y, img.RGBAAt(r.Max.X-2, y))
+	}
+}
+
+func (c *imageCache) invalidate(img image.Image) {
+	if !reflect.TypeOf(img).Comparable() {
+		return
+	}
+	if cached, ok := c.entries[img]; ok {
+		delete(c.entries, img)
+		c.release(cached)
+	}
+}
+
+func (c *imageCache) release(cached *cachedImage) {
+	page := cached.page
+	if page == nil {
+		c.releaseTexture(cached.texture)
+		return
+	}
+	// The border goes back to the page too.
+	page.packer.free(cached.rect.Inset(-1))
+	page.images--
+	if page.images > 0 {
+		return
+	}
+	for i, p := range c.pages {
+		if p == page {
+			c.pages = append(c.pages[:i], c.pages[i+1:]...)
+			break
+		}
+	}
+	c.releaseTexture(page.texture)
+}
+
+func (c *imageCache) releaseTexture(t *texture) {
+	t.released = true
+	if t.id != 0 {
+		c.released = append(c.released, t.id)
+		t.id = 0
+	}
+}
+
+// upload creates the textures and uploads the images added since the last
+// call.
+func (c *imageCache) upload() {
+	for _, u := range c.uploads {
+		t := u.texture
+		if t.released {
+			continue
+		}
+		if t.id == 0 {
+			gl.GenTextures(1, &t.id)
+			gl.BindTexture(gl.TEXTURE_2D, t.id)
+			gl.TexImage2D(
+				gl.TEXTURE_2D, 0, gl.RGBA, int32(t.size.X), int32(t.size.Y), 0,
+				gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		} else {
+			gl.BindTexture(gl.TEXTURE_2D, t.id)
+		}
+		size := u.pixels.Rect.Size()
+		gl.TexSubImage2D(
+			gl.TEXTURE_2D, 0, int32(u.at.X), int32(u.at.Y), int32(size.X), int32(size.Y),
+			gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(u.pixels.Pix))
+	}
+	c.uploads = c.uploads[:0]
+}
+
+// collect ends a drawn frame: it releases images that weren't drawn for a
+// while and deletes released textures.
+func (c *imageCache) collect() {
+	for _, cached := range c.transient {
+		c.release(cached)
+	}
+	c.transient = c.transient[:0]
+	for img, cached := range c.entries {
+		if c.frame-cached.lastUsed > imageCacheMaxAge {
+			delete(c.entries, img)
+			c.release(cached)
+		}
+	}
+	c.frame++
+	c.deleteReleased()
+}
+
+func (c *imageCache) deleteReleased() {
+	if len(c.released) > 0 {
+		gl.DeleteTextures(int32(len(c.released)), &c.released[0])
+		c.released = c.released[:0]
+	}
+}
+
+func (c *imageCache) destroy() {
+	for img, cached := range c.entries {
+		delete(c.entries, img)
+		c.release(cached)
+	}
+	for _, cached := range c.transient {
+		c.release(cached)
+	}
+	c.transient = nil
+	c.uploads = nil
+	c.deleteReleased()
+}
@@ -0,0 +1,205 @@
+package gl
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// funcImage isn't comparable, so it can't be a map key.
+type funcImage struct {
+	size image.Point
+	at   func(x, y int) color.Color
+}
+
+func (f funcImage) ColorModel() color.Model { return color.RGBAModel }
+func (f funcImage) Bounds() image.Rectangle { return image.Rectangle{Max: f.size} }
+func (f funcImage) At(x, y int) color.Color { return f.at(x, y) }
+
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestImageCacheIdentity(t *testing.T) {
+	c := newImageCache()
+	red := color.RGBA{255, 0, 0, 255}
+	a := solidImage(4, 4, red)
+	first := c.image(a)
+	if second := c.image(a); second != first {
+		t.Errorf("image(a) twice = %p, %p, want the same entry", first, second)
+	}
+	// Images are keyed by identity, not by their pixels.
+	b := solidImage(4, 4, red)
+	if other := c.image(b); other == first || other.rect.Overlaps(first.rect) {
+		t.Errorf("image(b) = %v, shares the entry of a at %v", other.rect, first.rect)
+	}
+	if len(c.uploads) != 2 {
+		t.Errorf("%d uploads, want 2", len(c.uploads))
+	}
+	// Both are packed into the same page.
+	if len(c.pages) != 1 || first.page != c.pages[0] || first.page.images != 2 {
+		t.Errorf("pages = %v, want a single one holding both images", c.pages)
+	}
+}
+
+func TestImageCachePacking(t *testing.T) {
+	c := newImageCache()
+	img := image.NewRGBA(image.Rect(10, 20, 12, 22))
+	img.SetRGBA(10, 20, color.RGBA{255, 0, 0, 255})
+	img.SetRGBA(11, 20, color.RGBA{0, 255, 0, 255})
+	img.SetRGBA(10, 21, color.RGBA{0, 0, 255, 255})
+	img.SetRGBA(11, 21, color.RGBA{255, 255, 255, 255})
+	cached := c.image(img)
+	if cached.page == nil {
+		t.Fatalf("small image isn't packed")
+	}
+	// The image is inside a border of a pixel.
+	upload := c.uploads[0]
+	if got, want := cached.rect, image.Rect(1, 1, 3, 3).Add(upload.at); got != want {
+		t.Errorf("rect = %v, want %v", got, want)
+	}
+	if got := upload.pixels.Rect.Size(); got != (image.Point{4, 4}) {
+		t.Fatalf("uploaded %v pixels, want 4x4", got)
+	}
+	// The border repeats the edge pixels, the corners the corner ones.
+	for y, row := range []string{
+		"rrgg",
+		"rrgg",
+		"bbww",
+		"bbww",
+	} {
+		for x, name := range row {
+			want := map[rune]color.RGBA{
+				'r': {255, 0, 0, 255},
+				'g': {0, 255, 0, 255},
+				'b': {0, 0, 255, 255},
+				'w': {255, 255, 255, 255},
+			}[name]
+			if got := upload.pixels.RGBAAt(x, y); got != want {
+				t.Errorf("uploaded pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+
+	// Large images get textures of their own.
+	large := c.image(image.NewRGBA(image.Rect(0, 0, imageAtlasMaxImageSize+1, 10)))
+	if large.page != nil || large.texture == cached.texture {
+		t.Errorf("large image is packed")
+	}
+	if got, want := large.rect, image.Rect(0, 0, imageAtlasMaxImageSize+1, 10); got != want {
+		t.Errorf("large image rect = %v, want %v", got, want)
+	}
+}
+
+func TestImageCacheTransient(t *testing.T) {
+	c := newImageCache()
+	img := funcImage{image.Point{2, 2}, func(x, y int) color.Color { return color.White }}
+	first := c.image(img)
+	second := c.image(img)
+	if first == second {
+		t.Errorf("transient image is cached")
+	}
+	if len(c.entries) != 0 || len(c.transient) != 2 {
+		t.Errorf("%d entries and %d transient images, want 0 and 2", len(c.entries), len(c.transient))
+	}
+	// Invalidating does nothing to them.
+	c.invalidate(img)
+	if len(c.transient) != 2 {
+		t.Errorf("invalidate removed transient images")
+	}
+
+	// The frame releases them and their page with them.
+	page := first.page
+	c.collect()
+	if len(c.transient) != 0 || len(c.pages) != 0 || !page.texture.released {
+		t.Errorf("after the frame: %d transient images, %d pages, page released %v, want 0, 0, true",
+			len(c.transient), len(c.pages), page.texture.released)
+	}
+}
+
+func TestImageCacheInvalidate(t *testing.T) {
+	c := newImageCache()
+	img := solidImage(4, 4, color.RGBA{255, 0, 0, 255})
+	other := solidImage(4, 4, color.RGBA{0, 255, 0, 255})
+	first := c.image(img)
+	c.image(other)
+
+	c.invalidate(img)
+	if _, ok := c.entries[img]; ok {
+		t.Errorf("invalidated image is cached")
+	}
+	// The image is uploaded again, into the space it had.
+	c.uploads = c.uploads[:0]
+	again := c.image(img)
+	if again == first {
+		t.Errorf("image after invalidate = the old entry")
+	}
+	if again.rect != first.rect || again.page != first.page {
+		t.Errorf("image after invalidate at %v, want the freed %v", again.rect, first.rect)
+	}
+	if len(c.uploads) != 1 {
+		t.Errorf("%d uploads after invalidate, want 1", len(c.uploads))
+	}
+
+	// Large images release their textures.
+	large := image.NewRGBA(image.Rect(0, 0, imageAtlasMaxImageSize+1, 1))
+	cached := c.image(large)
+	c.invalidate(large)
+	if !cached.texture.released {
+		t.Errorf("texture of invalidated large image isn't released")
+	}
+	// Invalidating images that aren't cached does nothing.
+	c.invalidate(large)
+	c.invalidate(solidImage(1, 1, color.RGBA{}))
+}
+
+func TestImageCacheCollect(t *testing.T) {
+	c := newImageCache()
+	used := solidImage(4, 4, color.RGBA{255, 0, 0, 255})
+	unused := solidImage(4, 4, color.RGBA{0, 255, 0, 255})
+	c.image(used)
+	dropped := c.image(unused)
+	// Images are kept for imageCacheMaxAge frames after the one they
+	// were last drawn in.
+	for i := 0; i <= imageCacheMaxAge; i++ {
+		c.collect()
+		c.image(used)
+	}
+	if _, ok := c.entries[unused]; !ok {
+		t.Fatalf("image dropped %d frames after it was drawn", imageCacheMaxAge)
+	}
+	c.collect()
+	if _, ok := c.entries[unused]; ok {
+		t.Errorf("image kept %d frames after it was drawn", imageCacheMaxAge+1)
+	}
+	if _, ok := c.entries[used]; !ok {
+		t.Errorf("drawn image dropped")
+	}
+	// Its space is reused.
+	if got := c.image(solidImage(4, 4, color.RGBA{})); got.rect != dropped.rect {
+		t.Errorf("new image at %v, want the space %v of the dropped one", got.rect, dropped.rect)
+	}
+}
+
+func TestImageCacheEviction(t *testing.T) {
+	// Images added and evicted forever go to the space of the evicted
+	// ones on the first page.
+	c := newImageCache()
+	var live []*image.RGBA
+	for i := 0; i < 2000; i++ {
+		img := solidImage(imageAtlasMaxImageSize-i%50, imageAtlasMaxImageSize-i%30, color.RGBA{})
+		if cached := c.image(img); cached.page != c.pages[0] {
+			t.Fatalf("image %d is on page %d with %d images on the first one", i, len(c.pages), c.pages[0].images)
+		}
+		live = append(live, img)
+		if len(live) > 8 {
+			c.invalidate(live[0])
+			live = live[1:]
+		}
+		c.uploads = c.uploads[:0]
+	}
+}
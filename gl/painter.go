@@ -2,59 +2,76 @@ package gl
 
 import (
 	"image"
-
-	"golang.org/x/image/draw"
+	"math"
 
 	"github.com/alex-ac/gkit"
 )
 
+// The internal drawing methods take r or o in the coordinates of the layer
+// that draws, t mapping them into the coordinates of the root painter, and
+// a clip in root coordinates.
 type glPainterInternal interface {
-	setColor(c gkit.Color)
-	drawRect(r gkit.Rect, z uint32)
+	setBrush(b gkit.Brush)
+	setBlendMode(mode gkit.BlendMode)
+	drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32)
 	setFont(font *gkit.Font)
 	setFontSize(size uint32)
-	drawText(o gkit.Point, z uint32, text string)
-	drawImage(r gkit.Rect, z uint32, image image.Image)
+	drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, z uint32, text string)
+	drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32, image image.Image)
+	// fillPolygons takes polygons in root coordinates, t only maps the
+	// brush.
+	fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip, z uint32)
+	// drawShape draws shadows in their color rather than the current one.
+	drawShape(s shape, t gkit.Transform, clip gkit.Clip, z uint32)
+	// tolerance is how far flattened paths may deviate in root
+	// coordinates.
+	tolerance() float32
+	// drawComposited takes base, mapping the layer into root
+	// coordinates, and clip already limited by the caller.
+	drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, z uint32, opacity float32, mode gkit.BlendMode)
 	enableRedraw()
 }
 
-func min(a, b uint32) uint32 {
-	if a < b {
-		return a
-	}
-	return b
-}
+// noClip is used as the clip of draw calls that are only limited by the
+// layers they are made in.
+var noClip = gkit.ClipRect(gkit.RectF{
+	PointF: gkit.PointF{X: -1 << 30, Y: -1 << 30},
+	SizeF:  gkit.SizeF{Width: 1 << 31, Height: 1 << 31},
+})
 
-func normalizeCoords(r gkit.Rect, max gkit.Size) gkit.Rect {
-	r.X = min(r.X, max.Width)
-	r.Y = min(r.Y, max.Height)
-	r.Width = min(r.Width, max.Width-r.X)
-	r.Height = min(r.Height, max.Height-r.Y)
-	return r
-}
+type instruction func(*painter)
 
-func getFloats(r gkit.Rect, z uint32) (float32, float32, float32, float32, float32) {
-	left, top := r.Point.AsFloats()
-	right, bottom := r.RightBottom().AsFloats()
-	Z := float32(z)
-	return left, top, right, bottom, Z
+// batch is a run of vertices drawn with the same image texture bound.
+type batch struct {
+	texture *texture
+	count   int32
+	mode    gkit.BlendMode
 }
 
-type instruction func(*painter)
-
 type painter struct {
+	gkit.TransformStack
+
 	context *drawingContext
 	size    gkit.Size
 
-	mask   *image.Gray
-	images []*image.RGBA
+	// pass is where vertices go now: the window, or a composited layer.
+	// suspended holds the passes of the layers it is nested in, groups
+	// the finished passes of composited layers.
+	*pass
+	suspended []*pass
+	groups    []*pass
 
-	vertices     []float32
-	currentColor [4]float32
+	currentBrush gkit.Brush
+	blendMode    gkit.BlendMode
 
 	currentFont     *gkit.Font
 	currentFontSize uint32
 
+	// clip limits everything drawn, clips holds the clips saved by
+	// PushClip.
+	clip  gkit.Clip
+	clips []gkit.Clip
+
 	scaleFactor float32
 	doRedraw    bool
 
@@ -65,17 +82,34 @@ var _ gkit.Painter = &painter{}
 var _ glPainterInternal = &painter{}
 
 func (p *painter) DrawLayer(r gkit.Rect, l gkit.Layer) {
-	r = normalizeCoords(r, p.size)
-	painter := &painterProxy{
-		impl:  p,
-		frame: r,
-	}
+	t := p.Transform()
+	p.drawLayer(&painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(float32(r.X), float32(r.Y))),
+		clip: p.clip.ClipTo(r.RectF(), t),
+	}, l)
+}
 
+func (p *painter) DrawScrolledLayer(clip gkit.Rect, offset gkit.Point, l gkit.Layer) {
+	t := p.Transform()
+	p.drawLayer(&painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(-float32(offset.X), -float32(offset.Y))),
+		clip: p.clip.ClipTo(clip.RectF(), t),
+	}, l)
+}
+
+func (p *painter) drawLayer(painter *painterProxy, l gkit.Layer) {
 	if l.NeedsRedraw() {
 		p.enableRedraw()
 	}
 	l.Draw(painter)
 	l.PropagateDraw(painter)
+	p.flush()
+}
+
+// flush runs the instructions if the frame is redrawn.
+func (p *painter) flush() {
 	if p.doRedraw {
 		for _, i := range p.instructions {
 			i(p)
@@ -84,16 +118,52 @@ func (p *painter) DrawLayer(r gkit.Rect, l gkit.Layer) {
 	}
 }
 
+func (p *painter) bounds() gkit.Clip {
+	return gkit.ClipRect(gkit.RectF{SizeF: p.size.SizeF()})
+}
+
 func (p *painter) enableRedraw() {
 	p.doRedraw = true
 }
 
+func (p *painter) PushClip(r gkit.Rect) {
+	p.clips = append(p.clips, p.clip)
+	p.clip = p.clip.ClipTo(r.RectF(), p.Transform())
+}
+
+func (p *painter) PopClip() {
+	if n := len(p.clips); n > 0 {
+		p.clip = p.clips[n-1]
+		p.clips = p.clips[:n-1]
+	}
+}
+
 func (p *painter) SetColor(c gkit.Color) {
-	p.setColor(c)
+	p.setBrush(gkit.SolidBrush(c))
 }
 
-func (p *painter) setColor(c gkit.Color) {
-	p.currentColor = [4]float32{
+func (p *painter) SetBrush(b gkit.Brush) {
+	p.setBrush(b)
+}
+
+func (p *painter) setBrush(b gkit.Brush) {
+	p.currentBrush = b
+}
+
+func (p *painter) SetBlendMode(mode gkit.BlendMode) {
+	p.setBlendMode(mode)
+}
+
+// setBlendMode is an instruction, the mode goes with the batches of the
+// vertices appended after it.
+func (p *painter) setBlendMode(mode gkit.BlendMode) {
+	p.addInstruction(func(p *painter) {
+		p.blendMode = mode
+	})
+}
+
+func vec4(c gkit.Color) [4]float32 {
+	return [4]float32{
 		float32(c.R()) / 255,
 		float32(c.G()) / 255,
 		float32(c.B()) / 255,
@@ -102,22 +172,17 @@ func (p *painter) setColor(c gkit.Color) {
 }
 
 func (p *painter) DrawRect(r gkit.Rect) {
-	p.drawRect(r, 0)
+	p.drawRect(r.RectF(), p.Transform(), p.clip, 0)
 }
 
-func (p *painter) drawRect(r gkit.Rect, z uint32) {
-	R, G, B, A := p.currentColor[0], p.currentColor[1], p.currentColor[2], p.currentColor[3]
+func (p *painter) drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32) {
+	clip = clip.Intersect(p.bounds())
+	if t.BoundingBox(r).Intersect(clip.Bounds).Empty() {
+		return
+	}
+	brush := p.currentBrush
 	p.addInstruction(func(p *painter) {
-		left, top, right, bottom, Z := getFloats(r, z)
-		U, V, W := float32(0), float32(0), float32(-1)
-		p.vertices = append(p.vertices,
-			left, top, Z, R, G, B, A, U, V, U, V, W,
-			right, top, Z, R, G, B, A, U, V, U, V, W,
-			left, bottom, Z, R, G, B, A, U, V, U, V, W,
-			right, top, Z, R, G, B, A, U, V, U, V, W,
-			right, bottom, Z, R, G, B, A, U, V, U, V, W,
-			left, bottom, Z, R, G, B, A, U, V, U, V, W,
-		)
+		p.appendQuad(r, t, clip, z, p.paint(brush, t), gkit.RectF{}, gkit.RectF{}, nil, shape{})
 	})
 }
 
@@ -138,62 +203,256 @@ func (p *painter) setFontSize(size uint32) {
 }
 
 func (p *painter) DrawText(o gkit.Point, text string) {
-	p.drawText(o, 0, text)
+	p.drawText(o.PointF(), p.Transform(), p.clip, 0, text)
 }
 
-func (p *painter) drawText(o gkit.Point, z uint32, text string) {
+func (p *painter) drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, z uint32, text string) {
 	font := p.currentFont
 	fontSize := p.currentFontSize
-	R, G, B, A := p.currentColor[0], p.currentColor[1], p.currentColor[2], p.currentColor[3]
+	if font == nil {
+		return
+	}
+	brush := p.currentBrush
+	clip = clip.Intersect(p.bounds())
 	p.addInstruction(func(p *painter) {
-		size := font.StringSize(fontSize, text)
-		font.DrawString(uint32(float32(fontSize)*p.scaleFactor), text, o.Scale(p.scaleFactor), p.mask)
-		r := gkit.Rect{o, size}
-
-		left, top, right, bottom, Z := getFloats(r, z)
-		U, V, W := float32(0), float32(0), float32(-1)
-		p.vertices = append(p.vertices,
-			left, top, Z, R, G, B, A, left, top, U, V, W,
-			right, top, Z, R, G, B, A, right, top, U, V, W,
-			left, bottom, Z, R, G, B, A, left, bottom, U, V, W,
-			right, top, Z, R, G, B, A, right, top, U, V, W,
-			right, bottom, Z, R, G, B, A, right, bottom, U, V, W,
-			left, bottom, Z, R, G, B, A, left, bottom, U, V, W,
-		)
+		size := font.StringSize(fontSize, text).SizeF()
+		if t.BoundingBox(gkit.RectF{PointF: o, SizeF: size}).Intersect(clip.Bounds).Empty() {
+			return
+		}
+		paint := p.paint(brush, t)
+
+		// Glyphs are rasterized in device pixels, the pen keeps the
+		// fractional part for the subpixel position. Text that is only
+		// moved and uniformly scaled is snapped to the device pixels,
+		// otherwise glyph quads are mapped by t.
+		scale := p.scaleFactor * t.Scale()
+		origin := gkit.PointF{}
+		toRoot := t.Mul(gkit.Translation(o.X, o.Y)).Mul(gkit.Scaling(1/scale, 1/scale))
+		if t.IsAxisAligned() && t.A == t.D && t.A > 0 {
+			origin = t.Apply(o).Scale(p.scaleFactor)
+			origin.Y = float32(math.Floor(float64(origin.Y)))
+			toRoot = gkit.Scaling(1/p.scaleFactor, 1/p.scaleFactor)
+		}
+		deviceSize := uint32(float32(fontSize)*scale + 0.5)
+		positions := font.Positions(deviceSize, text)
+		i := 0
+		for _, r := range text {
+			pen := origin.X + positions[i]
+			i++
+			x := float32(math.Floor(float64(pen)))
+			g, ok := p.context.glyphs.glyph(font, deviceSize, r, pen-x)
+			if !ok || g.rect.Empty() {
+				continue
+			}
+			mask := rectF(g.rect)
+			r := gkit.RectF{
+				PointF: gkit.PointF{X: x + float32(g.offset.X), Y: origin.Y + float32(g.offset.Y)},
+				SizeF:  mask.SizeF,
+			}
+			p.appendQuad(r, toRoot, clip, z, paint, mask, gkit.RectF{}, nil, shape{})
+		}
 	})
 }
 
+func rectF(r image.Rectangle) gkit.RectF {
+	return gkit.RectF{
+		PointF: gkit.PointF{X: float32(r.Min.X), Y: float32(r.Min.Y)},
+		SizeF:  gkit.SizeF{Width: float32(r.Dx()), Height: float32(r.Dy())},
+	}
+}
+
 func (p *painter) DrawImage(r gkit.Rect, img image.Image) {
-	p.drawImage(r, 0, img)
+	p.drawImage(r.RectF(), p.Transform(), p.clip, 0, img)
 }
 
-func (p *painter) drawImage(r gkit.Rect, z uint32, img image.Image) {
-	R, G, B, A := p.currentColor[0], p.currentColor[1], p.currentColor[2], p.currentColor[3]
+func (p *painter) drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32, img image.Image) {
+	clip = clip.Intersect(p.bounds())
+	if t.BoundingBox(r).Intersect(clip.Bounds).Empty() || img.Bounds().Empty() {
+		return
+	}
 	p.addInstruction(func(p *painter) {
-		size := textureSideSize(p.size)
-		size = uint32(float32(size) * p.scaleFactor)
-		imageCopy := image.NewRGBA(image.Rectangle{
-			Max: image.Point{int(size), int(size)},
-		})
-		bounds := img.Bounds()
-		draw.Copy(imageCopy, image.Point{}, img, bounds, draw.Over, nil)
-		left, top, right, bottom, Z := getFloats(r, z)
-		W := float32(len(p.images))
-		p.images = append(p.images, imageCopy)
-		U, V := float32(0), float32(0)
-		imageWidth, imageHeight := bounds.Max.X-bounds.Min.X, bounds.Max.Y-bounds.Min.Y
-		uvLeft, uvTop, uvRight, uvBottom := float32(0), float32(0), float32(imageWidth)/float32(size), float32(imageHeight)/float32(size)
-		p.vertices = append(p.vertices,
-			left, top, Z, R, G, B, A, U, V, uvLeft, uvTop, W,
-			right, top, Z, R, G, B, A, U, V, uvRight, uvTop, W,
-			left, bottom, Z, R, G, B, A, U, V, uvLeft, uvBottom, W,
-			right, top, Z, R, G, B, A, U, V, uvRight, uvTop, W,
-			right, bottom, Z, R, G, B, A, U, V, uvRight, uvBottom, W,
-			left, bottom, Z, R, G, B, A, U, V, uvLeft, uvBottom, W,
-		)
+		cached := p.context.images.image(img)
+		tex := cached.texture
+		// The whole image is stretched over r.
+		uv := rectF(cached.rect)
+		uv = gkit.RectF{
+			PointF: gkit.PointF{X: uv.X / float32(tex.size.X), Y: uv.Y / float32(tex.size.Y)},
+			SizeF:  gkit.SizeF{Width: uv.Width / float32(tex.size.X), Height: uv.Height / float32(tex.size.Y)},
+		}
+		p.appendQuad(r, t, clip, z, paint{color: [4]float32{1, 1, 1, 1}}, gkit.RectF{}, uv, tex, shape{})
+	})
+}
+
+func (p *painter) FillPath(path *gkit.Path, rule gkit.FillRule) {
+	t := p.Transform()
+	p.fillPolygons(path.Polygons(t, p.tolerance()), rule, t, p.clip, 0)
+}
+
+func (p *painter) StrokePath(path *gkit.Path, style gkit.StrokeStyle) {
+	t := p.Transform()
+	p.fillPolygons(path.Stroke(style, t, p.tolerance()), gkit.NonZero, t, p.clip, 0)
+}
+
+func (p *painter) tolerance() float32 {
+	return gkit.PathTolerance / p.scaleFactor
+}
+
+func (p *painter) fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip, z uint32) {
+	clip = clip.Intersect(p.bounds())
+	if len(polygons) == 0 || clip.Empty() {
+		return
+	}
+	brush := p.currentBrush
+	p.addInstruction(func(p *painter) {
+		paint := p.paint(brush, t)
+		for _, trapezoid := range tessellate(polygons, rule) {
+			polygon := make([]quadVertex, len(trapezoid))
+			for i, point := range trapezoid {
+				polygon[i] = quadVertex{x: point.X, y: point.Y}
+			}
+			p.appendPolygon(polygon, clip, z, paint, nil, shape{})
+		}
 	})
 }
 
+func (p *painter) FillRoundedRect(r gkit.Rect, radii gkit.CornerRadii) {
+	p.drawShape(roundedRect(r, radii, 0), p.Transform(), p.clip, 0)
+}
+
+func (p *painter) StrokeRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(strokedRoundedRect(r, radii, width), p.Transform(), p.clip, 0)
+}
+
+func (p *painter) DrawBorder(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(roundedRect(r, radii, width), p.Transform(), p.clip, 0)
+}
+
+func (p *painter) DrawBoxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) {
+	p.drawShape(boxShadow(r, radii, shadow), p.Transform(), p.clip, 0)
+}
+
+// quadVertex is a corner of a clipped quad: the position in root
+// coordinates, mask and image texture coordinates, and the position in the
+// coordinates of the quad.
+type quadVertex struct {
+	x, y, u, v, s, t, lx, ly float32
+}
+
+func (a quadVertex) lerp(b quadVertex, f float32) quadVertex {
+	return quadVertex{
+		x:  a.x + (b.x-a.x)*f,
+		y:  a.y + (b.y-a.y)*f,
+		u:  a.u + (b.u-a.u)*f,
+		v:  a.v + (b.v-a.v)*f,
+		s:  a.s + (b.s-a.s)*f,
+		t:  a.t + (b.t-a.t)*f,
+		lx: a.lx + (b.lx-a.lx)*f,
+		ly: a.ly + (b.ly-a.ly)*f,
+	}
+}
+
+// appendQuad appends r mapped by t and cut to clip. mask and img are the
+// texture coordinates at the corners of r, img samples tex if it's not
+// nil, s is cut out of it. Since t is affine, the coordinates of the cut
+// corners are linear interpolations.
+func (p *painter) appendQuad(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32, paint paint, mask, img gkit.RectF, tex *texture, s shape) {
+	corner := func(x, y float32) quadVertex {
+		local := gkit.PointF{X: r.X + x*r.Width, Y: r.Y + y*r.Height}
+		position := t.Apply(local)
+		return quadVertex{
+			x:  position.X,
+			y:  position.Y,
+			lx: local.X,
+			ly: local.Y,
+			u:  mask.X + x*mask.Width,
+			v:  mask.Y + y*mask.Height,
+			s:  img.X + x*img.Width,
+			t:  img.Y + y*img.Height,
+		}
+	}
+	p.appendPolygon([]quadVertex{corner(0, 0), corner(1, 0), corner(1, 1), corner(0, 1)}, clip, z, paint, tex, s)
+}
+
+// appendPolygon appends the convex polygon cut to clip as a triangle fan.
+// Rect clips only take cutting to their bounds. Clips that are polygons
+// are convex, so cutting along their edges too does what a stencil test
+// would.
+func (p *painter) appendPolygon(polygon []quadVertex, clip gkit.Clip, z uint32, paint paint, tex *texture, s shape) {
+	bounds := clip.Bounds
+	rb := bounds.RightBottom()
+	polygon = clipPolygon(polygon, func(v quadVertex) float32 { return v.x - bounds.X })
+	polygon = clipPolygon(polygon, func(v quadVertex) float32 { return rb.X - v.x })
+	polygon = clipPolygon(polygon, func(v quadVertex) float32 { return v.y - bounds.Y })
+	polygon = clipPolygon(polygon, func(v quadVertex) float32 { return rb.Y - v.y })
+	for i, a := range clip.Polygon {
+		b := clip.Polygon[(i+1)%len(clip.Polygon)]
+		polygon = clipPolygon(polygon, func(v quadVertex) float32 {
+			return gkit.EdgeDistance(a, b, gkit.PointF{X: v.x, Y: v.y})
+		})
+	}
+	if len(polygon) < 3 {
+		return
+	}
+
+	R, G, B, A := paint.color[0], paint.color[1], paint.color[2], paint.color[3]
+	Z, W := float32(z), float32(-1)
+	if tex != nil {
+		W = 0
+		if tex.layer {
+			W = 1
+		}
+	}
+	vertices := make([]float32, 0, (len(polygon)-2)*3*attrStride/attrFloatSize)
+	for i := 1; i+1 < len(polygon); i++ {
+		for _, v := range [3]quadVertex{polygon[0], polygon[i], polygon[i+1]} {
+			vertices = append(vertices, v.x, v.y, Z, R, G, B, A, v.u, v.v, v.s, v.t, W)
+			attributes := s.attributes(gkit.PointF{X: v.lx, Y: v.ly})
+			vertices = append(vertices, attributes[:]...)
+			var offset gkit.PointF
+			if paint.kind != 0 {
+				offset = paint.toGradient.Apply(gkit.PointF{X: v.x, Y: v.y})
+			}
+			vertices = append(vertices, offset.X, offset.Y, paint.kind, paint.row)
+		}
+	}
+	p.appendVertices(tex, vertices...)
+}
+
+// clipPolygon cuts the convex polygon to the part where distance is not
+// negative.
+func clipPolygon(polygon []quadVertex, distance func(quadVertex) float32) []quadVertex {
+	result := make([]quadVertex, 0, len(polygon)+1)
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		da, db := distance(a), distance(b)
+		if da >= 0 {
+			result = append(result, a)
+		}
+		if (da < 0) != (db < 0) {
+			result = append(result, a.lerp(b, da/(da-db)))
+		}
+	}
+	return result
+}
+
+// appendVertices appends vertices sampling the image texture t, nil if they
+// don't sample images and can go to any batch.
+func (p *painter) appendVertices(t *texture, vertices ...float32) {
+	count := int32(len(vertices) * attrFloatSize / attrStride)
+	last := len(p.batches) - 1
+	same := last >= 0 && p.batches[last].mode == p.blendMode
+	switch {
+	case same && (t == nil || p.batches[last].texture == t):
+		p.batches[last].count += count
+	case same && p.batches[last].texture == nil:
+		p.batches[last].texture = t
+		p.batches[last].count += count
+	default:
+		p.batches = append(p.batches, batch{texture: t, count: count, mode: p.blendMode})
+	}
+	p.vertices = append(p.vertices, vertices...)
+}
+
 func (p *painter) addInstruction(i instruction) {
 	p.instructions = append(p.instructions, i)
 }
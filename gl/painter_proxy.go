@@ -7,40 +7,88 @@ import (
 )
 
 type painterProxy struct {
+	gkit.TransformStack
+
 	impl glPainterInternal
 
-	frame gkit.Rect
+	// base maps the layer's coordinates into the coordinates of the root
+	// painter, clip is the visible part of the layer in root coordinates.
+	base gkit.Transform
+	clip gkit.Clip
+	// clips holds the clips saved by PushClip.
+	clips []gkit.Clip
 }
 
 var _ gkit.Painter = &painterProxy{}
 var _ glPainterInternal = &painterProxy{}
 
+// toRoot maps what the layer draws now into root coordinates.
+func (p *painterProxy) toRoot() gkit.Transform {
+	return p.base.Mul(p.Transform())
+}
+
 func (p *painterProxy) DrawRect(r gkit.Rect) {
-	p.drawRect(r, 0)
+	p.drawRect(r.RectF(), p.toRoot(), noClip, 0)
 }
 
-func (p *painterProxy) drawRect(r gkit.Rect, z uint32) {
-	r = normalizeCoords(r, p.frame.Size)
-	p.impl.drawRect(r.Offset(p.frame.Point), z+1)
+func (p *painterProxy) drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32) {
+	p.impl.drawRect(r, t, clip.Intersect(p.clip), z+1)
 }
 
 func (p *painterProxy) DrawLayer(r gkit.Rect, l gkit.Layer) {
-	r = normalizeCoords(r, p.frame.Size)
+	t := p.toRoot()
+	painter := &painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(float32(r.X), float32(r.Y))),
+		clip: p.clip.ClipTo(r.RectF(), t),
+	}
+
+	l.Draw(painter)
+	l.PropagateDraw(painter)
+}
+
+func (p *painterProxy) DrawScrolledLayer(clip gkit.Rect, offset gkit.Point, l gkit.Layer) {
+	t := p.toRoot()
 	painter := &painterProxy{
-		impl:  p,
-		frame: r,
+		impl: p,
+		base: t.Mul(gkit.Translation(-float32(offset.X), -float32(offset.Y))),
+		clip: p.clip.ClipTo(clip.RectF(), t),
 	}
 
 	l.Draw(painter)
 	l.PropagateDraw(painter)
 }
 
+func (p *painterProxy) PushClip(r gkit.Rect) {
+	p.clips = append(p.clips, p.clip)
+	p.clip = p.clip.ClipTo(r.RectF(), p.toRoot())
+}
+
+func (p *painterProxy) PopClip() {
+	if n := len(p.clips); n > 0 {
+		p.clip = p.clips[n-1]
+		p.clips = p.clips[:n-1]
+	}
+}
+
 func (p *painterProxy) SetColor(c gkit.Color) {
-	p.setColor(c)
+	p.setBrush(gkit.SolidBrush(c))
+}
+
+func (p *painterProxy) SetBrush(b gkit.Brush) {
+	p.setBrush(b)
+}
+
+func (p *painterProxy) setBrush(b gkit.Brush) {
+	p.impl.setBrush(b)
+}
+
+func (p *painterProxy) SetBlendMode(mode gkit.BlendMode) {
+	p.setBlendMode(mode)
 }
 
-func (p *painterProxy) setColor(c gkit.Color) {
-	p.impl.setColor(c)
+func (p *painterProxy) setBlendMode(mode gkit.BlendMode) {
+	p.impl.setBlendMode(mode)
 }
 
 func (p *painterProxy) SetFont(f *gkit.Font) {
@@ -59,21 +107,57 @@ func (p *painterProxy) setFontSize(size uint32) {
 }
 
 func (p *painterProxy) DrawText(o gkit.Point, text string) {
-	p.drawText(o, 0, text)
+	p.drawText(o.PointF(), p.toRoot(), noClip, 0, text)
 }
-func (p *painterProxy) drawText(o gkit.Point, z uint32, text string) {
-	r := normalizeCoords(
-		gkit.Rect{Point: o}, p.frame.Size)
-	p.impl.drawText(r.Point.Offset(p.frame.Point), z+1, text)
+
+func (p *painterProxy) drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, z uint32, text string) {
+	p.impl.drawText(o, t, clip.Intersect(p.clip), z+1, text)
 }
 
 func (p *painterProxy) DrawImage(r gkit.Rect, image image.Image) {
-	p.drawImage(r, 0, image)
+	p.drawImage(r.RectF(), p.toRoot(), noClip, 0, image)
+}
+
+func (p *painterProxy) drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, z uint32, image image.Image) {
+	p.impl.drawImage(r, t, clip.Intersect(p.clip), z+1, image)
+}
+
+func (p *painterProxy) FillPath(path *gkit.Path, rule gkit.FillRule) {
+	t := p.toRoot()
+	p.fillPolygons(path.Polygons(t, p.tolerance()), rule, t, noClip, 0)
+}
+
+func (p *painterProxy) StrokePath(path *gkit.Path, style gkit.StrokeStyle) {
+	t := p.toRoot()
+	p.fillPolygons(path.Stroke(style, t, p.tolerance()), gkit.NonZero, t, noClip, 0)
+}
+
+func (p *painterProxy) fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip, z uint32) {
+	p.impl.fillPolygons(polygons, rule, t, clip.Intersect(p.clip), z+1)
+}
+
+func (p *painterProxy) FillRoundedRect(r gkit.Rect, radii gkit.CornerRadii) {
+	p.drawShape(roundedRect(r, radii, 0), p.toRoot(), noClip, 0)
+}
+
+func (p *painterProxy) StrokeRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(strokedRoundedRect(r, radii, width), p.toRoot(), noClip, 0)
+}
+
+func (p *painterProxy) DrawBorder(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(roundedRect(r, radii, width), p.toRoot(), noClip, 0)
+}
+
+func (p *painterProxy) DrawBoxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) {
+	p.drawShape(boxShadow(r, radii, shadow), p.toRoot(), noClip, 0)
+}
+
+func (p *painterProxy) drawShape(s shape, t gkit.Transform, clip gkit.Clip, z uint32) {
+	p.impl.drawShape(s, t, clip.Intersect(p.clip), z+1)
 }
 
-func (p *painterProxy) drawImage(r gkit.Rect, z uint32, image image.Image) {
-	r = normalizeCoords(r, p.frame.Size)
-	p.impl.drawImage(r.Offset(p.frame.Point), z+1, image)
+func (p *painterProxy) tolerance() float32 {
+	return p.impl.tolerance()
 }
 
 func (p *painterProxy) enableRedraw() {
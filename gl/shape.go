@@ -0,0 +1,88 @@
+package gl
+
+import (
+	"github.com/alex-ac/gkit"
+)
+
+type shapeKind uint8
+
+// The kinds of shapes the fragment shader cuts out of quads.
+const (
+	shapeNone shapeKind = iota
+	shapeRoundedRect
+	shapeShadow
+	shapeInsetShadow
+)
+
+// shape is a rounded rect, filled or stroked border wide inside of rect,
+// or the shadow of it. The fragment shader computes its coverage from
+// signed distances.
+type shape struct {
+	kind   shapeKind
+	rect   gkit.RectF
+	radii  gkit.CornerRadii
+	border float32
+	shadow gkit.BoxShadow
+}
+
+func roundedRect(r gkit.Rect, radii gkit.CornerRadii, border float32) shape {
+	return shape{kind: shapeRoundedRect, rect: r.RectF(), radii: radii.Fit(r.SizeF()), border: border}
+}
+
+// strokedRoundedRect strokes the outline of r centered on it.
+func strokedRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) shape {
+	s := roundedRect(r, radii, width)
+	s.rect = s.rect.Expand(width / 2)
+	s.radii = s.radii.Expand(width / 2)
+	return s
+}
+
+func boxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) shape {
+	kind := shapeShadow
+	if shadow.Inset {
+		kind = shapeInsetShadow
+	}
+	return shape{kind: kind, rect: r.RectF(), radii: radii.Fit(r.SizeF()), shadow: shadow}
+}
+
+func (s shape) bounds() gkit.RectF {
+	if s.kind == shapeShadow || s.kind == shapeInsetShadow {
+		return s.shadow.Bounds(s.rect)
+	}
+	return s.rect
+}
+
+// attributes returns the shape attributes of a vertex at p, in the
+// coordinates of the shape.
+func (s shape) attributes(p gkit.PointF) [14]float32 {
+	if s.kind == shapeNone {
+		return [14]float32{}
+	}
+	halfWidth, halfHeight := s.rect.Width/2, s.rect.Height/2
+	return [14]float32{
+		p.X - s.rect.X - halfWidth, p.Y - s.rect.Y - halfHeight,
+		halfWidth, halfHeight, s.border, s.shadow.Blur / 2,
+		s.radii.TopLeft, s.radii.TopRight, s.radii.BottomRight, s.radii.BottomLeft,
+		float32(s.kind), s.shadow.Offset.X, s.shadow.Offset.Y, s.shadow.Spread,
+	}
+}
+
+func (p *painter) drawShape(s shape, t gkit.Transform, clip gkit.Clip, z uint32) {
+	clip = clip.Intersect(p.bounds())
+	scale := t.Scale() * p.scaleFactor
+	if scale == 0 {
+		return
+	}
+	// The quad leaves a device pixel around the shape for antialiasing.
+	r := s.bounds().Expand(1 / scale)
+	if t.BoundingBox(r).Intersect(clip.Bounds).Empty() {
+		return
+	}
+	brush := p.currentBrush
+	if s.kind == shapeShadow || s.kind == shapeInsetShadow {
+		brush = gkit.SolidBrush(s.shadow.Color)
+	}
+	p.addInstruction(func(p *painter) {
+		p.appendQuad(r, t, clip, z, p.paint(brush, t), gkit.RectF{}, gkit.RectF{}, nil, s)
+	})
+}
@@ -0,0 +1,122 @@
+package gl
+
+import (
+	"image"
+	"sort"
+)
+
+// shelfPacker allocates rectangles in a square texture in shelves: rows
+// filled from left to right, a new row starts below the previous one. The
+// last row grows to the highest rectangle in it, the others keep their
+// height. Freed rectangles go back to their row, rows emptied at the
+// bottom are dropped, so that rows of other heights can take their space.
+type shelfPacker struct {
+	side    int
+	shelves []shelf
+}
+
+// shelf is a row of the packer. x is where its unused part starts, free
+// holds the spans freed left of it, sorted and merged.
+type shelf struct {
+	y, height int
+	x         int
+	free      []span
+}
+
+type span struct {
+	x, width int
+}
+
+func newShelfPacker(side int) shelfPacker {
+	return shelfPacker{side: side}
+}
+
+func (s *shelfPacker) allocate(size image.Point) (image.Point, bool) {
+	if size.X > s.side {
+		return image.Point{}, false
+	}
+	// The row wasting the least height takes the rectangle.
+	best, bestSpan := -1, -1
+	for i := range s.shelves {
+		sh := &s.shelves[i]
+		if sh.height < size.Y || best >= 0 && s.shelves[best].height <= sh.height {
+			continue
+		}
+		if j := sh.fit(size.X); j >= 0 {
+			best, bestSpan = i, j
+		} else if sh.x+size.X <= s.side {
+			best, bestSpan = i, -1
+		}
+	}
+	if best < 0 {
+		best = len(s.shelves) - 1
+		if best >= 0 && s.shelves[best].x+size.X <= s.side && s.shelves[best].y+size.Y <= s.side {
+			// The last row grows.
+			s.shelves[best].height = size.Y
+		} else {
+			y := 0
+			if best >= 0 {
+				y = s.shelves[best].y + s.shelves[best].height
+			}
+			if y+size.Y > s.side {
+				return image.Point{}, false
+			}
+			s.shelves = append(s.shelves, shelf{y: y, height: size.Y})
+			best = len(s.shelves) - 1
+		}
+	}
+
+	sh := &s.shelves[best]
+	if bestSpan >= 0 {
+		free := &sh.free[bestSpan]
+		at := image.Point{free.x, sh.y}
+		free.x += size.X
+		free.width -= size.X
+		if free.width == 0 {
+			sh.free = append(sh.free[:bestSpan], sh.free[bestSpan+1:]...)
+		}
+		return at, true
+	}
+	at := image.Point{sh.x, sh.y}
+	sh.x += size.X
+	return at, true
+}
+
+// fit returns the first free span at least width wide, or -1.
+func (sh *shelf) fit(width int) int {
+	for i, free := range sh.free {
+		if free.width >= width {
+			return i
+		}
+	}
+	return -1
+}
+
+// free returns r, allocated before, to its row.
+func (s *shelfPacker) free(r image.Rectangle) {
+	i := sort.Search(len(s.shelves), func(i int) bool { return s.shelves[i].y >= r.Min.Y })
+	if i == len(s.shelves) || s.shelves[i].y != r.Min.Y {
+		return
+	}
+	sh := &s.shelves[i]
+	j := sort.Search(len(sh.free), func(j int) bool { return sh.free[j].x >= r.Min.X })
+	sh.free = append(sh.free, span{})
+	copy(sh.free[j+1:], sh.free[j:])
+	sh.free[j] = span{r.Min.X, r.Dx()}
+	// Merge with the spans next to it.
+	if j+1 < len(sh.free) && sh.free[j].x+sh.free[j].width == sh.free[j+1].x {
+		sh.free[j].width += sh.free[j+1].width
+		sh.free = append(sh.free[:j+1], sh.free[j+2:]...)
+	}
+	if j > 0 && sh.free[j-1].x+sh.free[j-1].width == sh.free[j].x {
+		sh.free[j-1].width += sh.free[j].width
+		sh.free = append(sh.free[:j], sh.free[j+1:]...)
+	}
+	if last := len(sh.free) - 1; sh.free[last].x+sh.free[last].width == sh.x {
+		sh.x = sh.free[last].x
+		sh.free = sh.free[:last]
+	}
+	for n := len(s.shelves); n > 0 && s.shelves[n-1].x == 0; n-- {
+		s.shelves = s.shelves[:n-1]
+	}
+}
@@ -0,0 +1,106 @@
+package gl
+
+import (
+	"image"
+	"testing"
+)
+
+func TestShelfPacker(t *testing.T) {
+	s := newShelfPacker(100)
+	for _, step := range []struct {
+		size image.Point
+		at   image.Point
+		ok   bool
+	}{
+		{image.Point{40, 10}, image.Point{0, 0}, true},
+		// The last row grows to the highest rectangle.
+		{image.Point{40, 20}, image.Point{40, 0}, true},
+		{image.Point{30, 5}, image.Point{0, 20}, true},
+		{image.Point{101, 5}, image.Point{}, false},
+		{image.Point{20, 30}, image.Point{30, 20}, true},
+		{image.Point{60, 60}, image.Point{}, false},
+		// Short rectangles go to the rows wasting the least height.
+		{image.Point{20, 5}, image.Point{80, 0}, true},
+		{image.Point{50, 30}, image.Point{50, 20}, true},
+		{image.Point{100, 50}, image.Point{0, 50}, true},
+		{image.Point{1, 1}, image.Point{}, false},
+	} {
+		at, ok := s.allocate(step.size)
+		if at != step.at || ok != step.ok {
+			t.Errorf("allocate(%v) = %v, %v, want %v, %v", step.size, at, ok, step.at, step.ok)
+		}
+	}
+}
+
+func rectAt(x, y, w, h int) image.Rectangle {
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func TestShelfPackerFree(t *testing.T) {
+	s := newShelfPacker(100)
+	allocate := func(w, h int) image.Rectangle {
+		t.Helper()
+		at, ok := s.allocate(image.Point{w, h})
+		if !ok {
+			t.Fatalf("allocate(%d, %d) failed", w, h)
+		}
+		return rectAt(at.X, at.Y, w, h)
+	}
+	a := allocate(30, 40)
+	b := allocate(30, 40)
+	allocate(30, 40)
+	d := allocate(100, 50)
+	if _, ok := s.allocate(image.Point{30, 40}); ok {
+		t.Fatalf("allocate in a full packer succeeded")
+	}
+
+	// Freed space is reused by rectangles that fit into it.
+	s.free(b)
+	if got := allocate(20, 40); got != rectAt(30, 0, 20, 40) {
+		t.Errorf("allocate in the freed space = %v, want %v", got, rectAt(30, 0, 20, 40))
+	}
+	if _, ok := s.allocate(image.Point{20, 40}); ok {
+		t.Errorf("allocate wider than the free span succeeded")
+	}
+	allocate(10, 40)
+
+	// Spans next to each other merge.
+	s.free(rectAt(30, 0, 20, 40))
+	s.free(a)
+	if got := allocate(50, 40); got != rectAt(0, 0, 50, 40) {
+		t.Errorf("allocate in merged spans = %v, want %v", got, rectAt(0, 0, 50, 40))
+	}
+
+	// A row emptied at the bottom makes room for a higher one.
+	s.free(d)
+	if got := allocate(100, 60); got != rectAt(0, 40, 100, 60) {
+		t.Errorf("allocate after freeing the last row = %v, want %v", got, rectAt(0, 40, 100, 60))
+	}
+}
+
+func TestShelfPackerReuseAll(t *testing.T) {
+	// Allocating and freeing forever doesn't run out of space.
+	s := newShelfPacker(64)
+	var live []image.Rectangle
+	for i := 0; i < 1000; i++ {
+		size := image.Point{4 + i%13, 4 + i%7}
+		at, ok := s.allocate(size)
+		if !ok {
+			t.Fatalf("allocate %d of %v failed with %d rectangles allocated", i, size, len(live))
+		}
+		r := image.Rectangle{Min: at, Max: at.Add(size)}
+		for _, other := range live {
+			if r.Overlaps(other) {
+				t.Fatalf("allocate %d = %v overlaps %v", i, r, other)
+			}
+		}
+		if !r.In(image.Rect(0, 0, 64, 64)) {
+			t.Fatalf("allocate %d = %v is outside of the packer", i, r)
+		}
+		live = append(live, r)
+		if len(live) > 8 {
+			s.free(live[0])
+			live = live[1:]
+		}
+	}
+}
@@ -22,10 +22,7 @@ func NewWindowSystem() (gkit.WindowSystem, error) {
 	}()
 
 	glfw.WindowHint(glfw.Resizable, glfw.True)
-	glfw.WindowHint(glfw.ContextVersionMajor, 3)
-	glfw.WindowHint(glfw.ContextVersionMinor, 2)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	contextHints()
 
 	ok = true
 	return &WindowSystem{
@@ -33,6 +30,16 @@ func NewWindowSystem() (gkit.WindowSystem, error) {
 	}, nil
 }
 
+// contextHints requests the GL context the drawing context needs for the
+// windows created next.
+func contextHints() {
+	glfw.WindowHint(glfw.ContextVersionMajor, 3)
+	glfw.WindowHint(glfw.ContextVersionMinor, 2)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfw.WindowHint(glfw.SRGBCapable, glfw.True)
+}
+
 type WindowSystem struct {
 	glInited bool
 	stopWait chan struct{}
@@ -68,6 +75,7 @@ func (s *WindowSystem) Create(w, h uint32, title string) (gkit.Window, error) {
 	if err != nil {
 		return nil, err
 	}
+	window.setupCallbacks()
 
 	ok = true
 	return window, nil
@@ -0,0 +1,118 @@
+package gl
+
+import (
+	"sort"
+
+	"github.com/alex-ac/gkit"
+)
+
+// edge is a non-horizontal polygon edge, top above bottom. winding is 1 if
+// the polygon runs downwards along it and -1 otherwise.
+type edge struct {
+	top, bottom gkit.PointF
+	winding     int
+}
+
+func (e edge) x(y float32) float32 {
+	return e.top.X + (y-e.top.Y)*(e.bottom.X-e.top.X)/(e.bottom.Y-e.top.Y)
+}
+
+// tessellate splits the area that rule fills inside polygons into
+// trapezoids. Every horizontal band between the ys of vertices and edge
+// crossings is cut at the edges spanning it, which don't cross inside the
+// band, and the pieces inside are filled.
+func tessellate(polygons [][]gkit.PointF, rule gkit.FillRule) [][4]gkit.PointF {
+	var edges []edge
+	var ys []float32
+	for _, polygon := range polygons {
+		for i, a := range polygon {
+			b := polygon[(i+1)%len(polygon)]
+			switch {
+			case a.Y < b.Y:
+				edges = append(edges, edge{a, b, 1})
+			case a.Y > b.Y:
+				edges = append(edges, edge{b, a, -1})
+			default:
+				continue
+			}
+			ys = append(ys, a.Y)
+		}
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].top.Y < edges[j].top.Y })
+	sort.Slice(ys, func(i, j int) bool { return ys[i] < ys[j] })
+
+	var trapezoids [][4]gkit.PointF
+	var active []edge
+	next := 0
+	for i := 0; i+1 < len(ys); i++ {
+		y0, y1 := ys[i], ys[i+1]
+		if y0 == y1 {
+			continue
+		}
+		kept := active[:0]
+		for _, e := range active {
+			if e.bottom.Y > y0 {
+				kept = append(kept, e)
+			}
+		}
+		active = kept
+		for ; next < len(edges) && edges[next].top.Y <= y0; next++ {
+			if edges[next].bottom.Y > y0 {
+				active = append(active, edges[next])
+			}
+		}
+
+		// Edges crossing inside the band split it further.
+		splits := []float32{y0}
+		for j, e := range active {
+			for _, f := range active[j+1:] {
+				d0, d1 := e.x(y0)-f.x(y0), e.x(y1)-f.x(y1)
+				if d0*d1 < 0 {
+					splits = append(splits, y0+(y1-y0)*d0/(d0-d1))
+				}
+			}
+		}
+		sort.Slice(splits, func(i, j int) bool { return splits[i] < splits[j] })
+		splits = append(splits, y1)
+		for j := 0; j+1 < len(splits); j++ {
+			if splits[j] < splits[j+1] {
+				trapezoids = fillBand(trapezoids, active, splits[j], splits[j+1], rule)
+			}
+		}
+	}
+	return trapezoids
+}
+
+// fillBand appends the trapezoids rule fills between y0 and y1, where
+// edges don't cross.
+func fillBand(trapezoids [][4]gkit.PointF, edges []edge, y0, y1 float32, rule gkit.FillRule) [][4]gkit.PointF {
+	mid := (y0 + y1) / 2
+	sort.Slice(edges, func(i, j int) bool { return edges[i].x(mid) < edges[j].x(mid) })
+	inside := func(winding int) bool {
+		if rule == gkit.EvenOdd {
+			return winding%2 != 0
+		}
+		return winding != 0
+	}
+	winding := 0
+	var left edge
+	for _, e := range edges {
+		was := inside(winding)
+		winding += e.winding
+		switch is := inside(winding); {
+		case !was && is:
+			left = e
+		case was && !is:
+			trapezoids = append(trapezoids, [4]gkit.PointF{
+				{X: left.x(y0), Y: y0},
+				{X: e.x(y0), Y: y0},
+				{X: e.x(y1), Y: y1},
+				{X: left.x(y1), Y: y1},
+			})
+		}
+	}
+	return trapezoids
+}
@@ -0,0 +1,107 @@
+package gl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func square(x, y, size float32, clockwise bool) []gkit.PointF {
+	polygon := []gkit.PointF{{X: x, Y: y}, {X: x + size, Y: y}, {X: x + size, Y: y + size}, {X: x, Y: y + size}}
+	if !clockwise {
+		polygon[1], polygon[3] = polygon[3], polygon[1]
+	}
+	return polygon
+}
+
+// star returns a pentagram of radius 10 around (10, 10), its edges cross
+// around the pentagon in the middle, which winds twice.
+func star() []gkit.PointF {
+	var polygon []gkit.PointF
+	for i := 0; i < 5; i++ {
+		sin, cos := math.Sincos(-math.Pi/2 + float64(i)*4*math.Pi/5)
+		polygon = append(polygon, gkit.PointF{X: 10 + 10*float32(cos), Y: 10 + 10*float32(sin)})
+	}
+	return polygon
+}
+
+func trapezoidArea(t [4]gkit.PointF) float32 {
+	return ((t[1].X - t[0].X) + (t[2].X - t[3].X)) / 2 * (t[3].Y - t[0].Y)
+}
+
+// trapezoidsCovering returns how many trapezoids p is inside of.
+func trapezoidsCovering(trapezoids [][4]gkit.PointF, p gkit.PointF) int {
+	var n int
+	for _, t := range trapezoids {
+		if p.Y <= t[0].Y || p.Y >= t[3].Y {
+			continue
+		}
+		f := (p.Y - t[0].Y) / (t[3].Y - t[0].Y)
+		left := t[0].X + (t[3].X-t[0].X)*f
+		right := t[1].X + (t[2].X-t[1].X)*f
+		if p.X > left && p.X < right {
+			n++
+		}
+	}
+	return n
+}
+
+func TestTessellate(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		polygons [][]gkit.PointF
+		rule     gkit.FillRule
+		area     float32
+		inside   []gkit.PointF
+		outside  []gkit.PointF
+	}{
+		{"square", [][]gkit.PointF{square(0, 0, 10, true)}, gkit.NonZero, 100,
+			[]gkit.PointF{{X: 5, Y: 5}}, []gkit.PointF{{X: 11, Y: 5}}},
+		{"counterclockwise square", [][]gkit.PointF{square(0, 0, 10, false)}, gkit.EvenOdd, 100,
+			[]gkit.PointF{{X: 5, Y: 5}}, nil},
+		{"nested nonzero", [][]gkit.PointF{square(0, 0, 10, true), square(2, 2, 6, true)}, gkit.NonZero, 100,
+			[]gkit.PointF{{X: 5, Y: 5}, {X: 1, Y: 1}}, nil},
+		{"nested evenodd", [][]gkit.PointF{square(0, 0, 10, true), square(2, 2, 6, true)}, gkit.EvenOdd, 64,
+			[]gkit.PointF{{X: 1, Y: 1}}, []gkit.PointF{{X: 5, Y: 5}}},
+		{"hole nonzero", [][]gkit.PointF{square(0, 0, 10, true), square(2, 2, 6, false)}, gkit.NonZero, 64,
+			[]gkit.PointF{{X: 1, Y: 1}}, []gkit.PointF{{X: 5, Y: 5}}},
+		{"overlapping", [][]gkit.PointF{square(0, 0, 10, true), square(5, 5, 10, true)}, gkit.NonZero, 175,
+			[]gkit.PointF{{X: 7, Y: 7}, {X: 12, Y: 12}}, []gkit.PointF{{X: 12, Y: 2}}},
+		{"overlapping evenodd", [][]gkit.PointF{square(0, 0, 10, true), square(5, 5, 10, true)}, gkit.EvenOdd, 150,
+			[]gkit.PointF{{X: 2, Y: 2}, {X: 12, Y: 12}}, []gkit.PointF{{X: 7, Y: 7}}},
+		// The area of a pentagram of radius 10 is 112.26, the pentagon in
+		// the middle 34.69 of it.
+		{"star nonzero", [][]gkit.PointF{star()}, gkit.NonZero, 112.26,
+			[]gkit.PointF{{X: 10, Y: 10}, {X: 10, Y: 2}}, []gkit.PointF{{X: 2, Y: 18}}},
+		{"star evenodd", [][]gkit.PointF{star()}, gkit.EvenOdd, 112.26 - 34.69,
+			[]gkit.PointF{{X: 10, Y: 2}}, []gkit.PointF{{X: 10, Y: 10}}},
+	} {
+		trapezoids := tessellate(test.polygons, test.rule)
+		var area float32
+		for _, trapezoid := range trapezoids {
+			if trapezoid[0].Y != trapezoid[1].Y || trapezoid[2].Y != trapezoid[3].Y {
+				t.Errorf("%s: trapezoid %v isn't horizontal at the top and bottom", test.name, trapezoid)
+			}
+			area += trapezoidArea(trapezoid)
+		}
+		if math.Abs(float64(area-test.area)) > 0.01 {
+			t.Errorf("%s: area = %g, want %g", test.name, area, test.area)
+		}
+		// Trapezoids don't overlap, so blending them draws every pixel
+		// once.
+		for _, p := range test.inside {
+			if n := trapezoidsCovering(trapezoids, p); n != 1 {
+				t.Errorf("%s: %v is inside of %d trapezoids, want 1", test.name, p, n)
+			}
+		}
+		for _, p := range test.outside {
+			if n := trapezoidsCovering(trapezoids, p); n != 0 {
+				t.Errorf("%s: %v is inside of %d trapezoids, want 0", test.name, p, n)
+			}
+		}
+	}
+	if trapezoids := tessellate(nil, gkit.NonZero); len(trapezoids) != 0 {
+		t.Errorf("tessellate(nil) = %v, want none", trapezoids)
+	}
+}
@@ -1,6 +1,8 @@
 package gl
 
 import (
+	"image"
+
 	"github.com/go-gl/gl/v3.2-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 
@@ -14,10 +16,16 @@ type Window struct {
 
 	size gkit.Size
 
-	root gkit.View
+	root       gkit.View
+	dispatcher gkit.EventDispatcher
+
+	cursor    gkit.Point
+	modifiers gkit.Modifiers
 }
 
 var _ gkit.Window = &Window{}
+var _ gkit.Clipboard = &Window{}
+var _ gkit.ImageCache = &Window{}
 
 func (w *Window) Size() gkit.Size {
 	return w.size
@@ -34,11 +42,21 @@ func (w *Window) Root() gkit.View {
 	return w.root
 }
 
+func (w *Window) DispatchEvent(e gkit.Event) {
+	if w.root == nil {
+		return
+	}
+	w.dispatcher.Dispatch(w.root, e)
+}
+
 func (w *Window) glSetup() error {
 	w.window.MakeContextCurrent()
 
-	gl.Enable(gl.DEPTH_TEST)
-	gl.DepthFunc(gl.LEQUAL)
+	// Vertices are drawn in the order the painter received them, so later
+	// draw calls cover earlier ones. Nested layers get a deeper z, which a
+	// depth test would put in front of everything drawn after them, like
+	// the scrollbars of a ScrollView over its content.
+	gl.Disable(gl.DEPTH_TEST)
 
 	context, err := newDrawingContext()
 	if err != nil {
@@ -49,6 +67,14 @@ func (w *Window) glSetup() error {
 	return nil
 }
 
+func (w *Window) Focus() gkit.View {
+	return w.dispatcher.Focus()
+}
+
+func (w *Window) SetFocus(view gkit.View) {
+	w.dispatcher.SetFocus(view)
+}
+
 func (w *Window) Destroy() {
 	w.window.MakeContextCurrent()
 	w.drawingContext.Destroy()
@@ -84,3 +110,19 @@ func (w *Window) Maximize() error {
 func (w *Window) ShouldClose() bool {
 	return w.window.ShouldClose()
 }
+
+func (w *Window) InvalidateImage(img image.Image) {
+	w.drawingContext.images.invalidate(img)
+}
+
+func (w *Window) ClipboardText() string {
+	text, err := w.window.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+func (w *Window) SetClipboardText(text string) {
+	w.window.SetClipboardString(text)
+}
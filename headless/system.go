@@ -0,0 +1,77 @@
+package headless
+
+import (
+	"sync"
+
+	"github.com/alex-ac/gkit"
+)
+
+func NewWindowSystem() *WindowSystem {
+	return &WindowSystem{
+		notify:   make(chan struct{}, 1),
+		stopWait: make(chan struct{}, 1),
+	}
+}
+
+type WindowSystem struct {
+	mutex  sync.Mutex
+	events []func()
+
+	notify   chan struct{}
+	stopWait chan struct{}
+}
+
+var _ gkit.WindowSystem = &WindowSystem{}
+
+func (s *WindowSystem) Create(w, h uint32, title string) (gkit.Window, error) {
+	return newWindow(gkit.Size{Width: w, Height: h}, title), nil
+}
+
+// Post queues f to be run by the next WaitEvents call. It is safe to call
+// from any goroutine.
+func (s *WindowSystem) Post(f func()) {
+	s.mutex.Lock()
+	s.events = append(s.events, f)
+	s.mutex.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// PostEvent queues e to be dispatched to the view tree of w.
+func (s *WindowSystem) PostEvent(w *Window, e gkit.Event) {
+	s.Post(func() {
+		w.DispatchEvent(e)
+	})
+}
+
+func (s *WindowSystem) takeEvents() []func() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	events := s.events
+	s.events = nil
+	return events
+}
+
+// WaitEvents blocks until at least one event was posted or Interrupt was
+// called and then runs all queued events.
+func (s *WindowSystem) WaitEvents() {
+	select {
+	case <-s.stopWait:
+		return
+	case <-s.notify:
+		for _, event := range s.takeEvents() {
+			event()
+		}
+	}
+}
+
+func (s *WindowSystem) Interrupt() {
+	select {
+	case s.stopWait <- struct{}{}:
+	default:
+	}
+}
+
+func (s *WindowSystem) Terminate() {}
@@ -0,0 +1,121 @@
+package headless
+
+import (
+	"image"
+
+	"github.com/alex-ac/gkit"
+	"github.com/alex-ac/gkit/soft"
+)
+
+type Window struct {
+	context *soft.DrawingContext
+
+	title string
+	size  gkit.Size
+
+	root       gkit.View
+	dispatcher gkit.EventDispatcher
+
+	clipboard string
+
+	shouldClose bool
+	destroyed   bool
+}
+
+var _ gkit.Window = &Window{}
+var _ gkit.Clipboard = &Window{}
+
+func newWindow(size gkit.Size, title string) *Window {
+	return &Window{
+		context: soft.NewDrawingContext(size),
+		title:   title,
+		size:    size,
+	}
+}
+
+func (w *Window) Title() string {
+	return w.title
+}
+
+func (w *Window) Size() gkit.Size {
+	return w.size
+}
+
+// Resize changes the window size. It takes effect on the next UpdateSize
+// call, like a resize of a real window does.
+func (w *Window) Resize(size gkit.Size) {
+	w.size = size
+}
+
+func (w *Window) SetRoot(view gkit.View) {
+	w.root = view
+	if w.root != nil {
+		w.root.SetSize(w.size)
+	}
+}
+
+func (w *Window) Root() gkit.View {
+	return w.root
+}
+
+func (w *Window) DispatchEvent(e gkit.Event) {
+	w.dispatcher.Dispatch(w.root, e)
+}
+
+func (w *Window) Focus() gkit.View {
+	return w.dispatcher.Focus()
+}
+
+func (w *Window) SetFocus(view gkit.View) {
+	w.dispatcher.SetFocus(view)
+}
+
+func (w *Window) Destroy() {
+	w.destroyed = true
+}
+
+func (w *Window) Destroyed() bool {
+	return w.destroyed
+}
+
+func (w *Window) UpdateSize() {
+	w.context.SetSize(w.size)
+	if w.root != nil {
+		w.root.SetSize(w.size)
+	}
+}
+
+func (w *Window) BeginPaint() gkit.Painter {
+	return w.context.BeginPaint()
+}
+
+func (w *Window) EndPaint(p gkit.Painter) {
+	w.context.EndPaint(p)
+}
+
+// Image returns the last painted frame.
+func (w *Window) Image() *image.RGBA {
+	return w.context.Image()
+}
+
+func (w *Window) Maximize() error {
+	return nil
+}
+
+// Close makes ShouldClose report true, so Application.Run destroys the
+// window on its next iteration.
+func (w *Window) Close() {
+	w.shouldClose = true
+}
+
+func (w *Window) ShouldClose() bool {
+	return w.shouldClose
+}
+
+func (w *Window) ClipboardText() string {
+	return w.clipboard
+}
+
+func (w *Window) SetClipboardText(text string) {
+	w.clipboard = text
+}
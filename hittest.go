@@ -0,0 +1,83 @@
+package gkit
+
+// HitTest returns the deepest view under p, a point relative to root, and
+// its ancestors starting from root. Children are tested in reverse paint
+// order, so the topmost view wins. A view only receives hits inside the
+// frame of its parent. Views that aren't drawn because of zero opacity
+// don't receive hits, nor do their children.
+func HitTest(root View, p Point) (View, []View) {
+	path := hitPath(root, p)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	return path[len(path)-1], path[:len(path)-1]
+}
+
+// hitPath returns the views under p from root down to the deepest one.
+func hitPath(root View, p Point) []View {
+	q := p.PointF()
+	if root == nil || !(Rect{Size: root.Size()}).RectF().Contains(q) {
+		return nil
+	}
+	path := []View{root}
+	view := root
+	for {
+		if scroller, ok := view.(Scroller); ok {
+			if !view.Bounds().RectF().Contains(q) {
+				return path
+			}
+			q = q.Add(scroller.ContentOffset().PointF())
+		}
+		var hit View
+		children := view.Children()
+		for i := len(children) - 1; i >= 0; i-- {
+			if children[i].Opacity() <= 0 {
+				continue
+			}
+			local, ok := childPoint(children[i], q)
+			if ok && (Rect{Size: children[i].Size()}).RectF().Contains(local) {
+				hit, q = children[i], local
+				break
+			}
+		}
+		if hit == nil {
+			return path
+		}
+		path = append(path, hit)
+		view = hit
+	}
+}
+
+// childPoint maps p from the coordinates of the parent of child into the
+// coordinates of child, undoing its transform.
+func childPoint(child View, p PointF) (PointF, bool) {
+	p = p.Sub(child.Origin().PointF())
+	if t := child.Transform(); !t.IsIdentity() {
+		inverse, ok := t.Invert()
+		if !ok {
+			return PointF{}, false
+		}
+		p = inverse.Apply(p)
+	}
+	return p, true
+}
+
+// LocalPoint translates a point relative to path[0] into the coordinate space
+// of the last view of path, as returned by HitTest. The root view is always
+// placed at the window origin. Coordinates left of or above the view are
+// clamped to 0.
+func LocalPoint(path []View, p Point) Point {
+	return localPoint(path, p).Point()
+}
+
+// localPoint is LocalPoint without the clamping.
+func localPoint(path []View, p Point) PointF {
+	q := p.PointF()
+	for i, view := range path[1:] {
+		if scroller, ok := path[i].(Scroller); ok {
+			q = q.Add(scroller.ContentOffset().PointF())
+		}
+		q, _ = childPoint(view, q)
+	}
+	return q
+}
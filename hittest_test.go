@@ -0,0 +1,159 @@
+package gkit_test
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+type testScroller struct {
+	*testView
+	offset gkit.Point
+}
+
+func (s *testScroller) ContentOffset() gkit.Point {
+	return s.offset
+}
+
+func rect(x, y, w, h uint32) gkit.Rect {
+	return gkit.Rect{Point: gkit.Point{X: x, Y: y}, Size: gkit.Size{Width: w, Height: h}}
+}
+
+func hitName(view gkit.View) string {
+	if view == nil {
+		return "<nil>"
+	}
+	switch v := view.(type) {
+	case *testView:
+		return v.name
+	case *testScroller:
+		return v.name
+	}
+	return "?"
+}
+
+func TestHitTest(t *testing.T) {
+	var log []string
+	root, parent, _ := testTree(&log)
+	// sibling overlaps parent and is added later, so it is painted on top.
+	sibling := newTestView(&log, "sibling", rect(100, 100, 50, 50))
+	root.AddChild(sibling)
+	// overflow sticks out of parent, which clips hits to its frame.
+	overflow := newTestView(&log, "overflow", rect(90, 0, 50, 10))
+	parent.AddChild(overflow)
+
+	for _, test := range []struct {
+		p     gkit.Point
+		want  string
+		depth int
+		local gkit.Point
+	}{
+		{gkit.Point{X: 5, Y: 5}, "root", 0, gkit.Point{X: 5, Y: 5}},
+		{gkit.Point{X: 15, Y: 15}, "parent", 1, gkit.Point{X: 5, Y: 5}},
+		{gkit.Point{X: 40, Y: 40}, "child", 2, gkit.Point{X: 10, Y: 10}},
+		{gkit.Point{X: 105, Y: 105}, "sibling", 1, gkit.Point{X: 5, Y: 5}},
+		{gkit.Point{X: 105, Y: 15}, "overflow", 2, gkit.Point{X: 5, Y: 5}},
+		{gkit.Point{X: 115, Y: 15}, "root", 0, gkit.Point{X: 115, Y: 15}},
+		{gkit.Point{X: 200, Y: 5}, "<nil>", 0, gkit.Point{}},
+	} {
+		view, ancestors := gkit.HitTest(root, test.p)
+		if got := hitName(view); got != test.want || len(ancestors) != test.depth {
+			t.Errorf("HitTest(%v) = %s with %d ancestors, want %s with %d",
+				test.p, got, len(ancestors), test.want, test.depth)
+			continue
+		}
+		if view == nil {
+			continue
+		}
+		if test.depth > 0 && ancestors[0] != gkit.View(root) {
+			t.Errorf("HitTest(%v) ancestors don't start at root", test.p)
+		}
+		if local := gkit.LocalPoint(append(ancestors, view), test.p); local != test.local {
+			t.Errorf("LocalPoint(%v) = %v, want %v", test.p, local, test.local)
+		}
+	}
+}
+
+func TestHitTestScroller(t *testing.T) {
+	var log []string
+	root := newTestView(&log, "root", rect(0, 0, 200, 200))
+	scroller := &testScroller{
+		testView: newTestView(&log, "scroller", rect(10, 10, 100, 100)),
+		offset:   gkit.Point{X: 0, Y: 150},
+	}
+	scroller.ViewBase.View = scroller
+	scroller.SetBorders(gkit.SideValues{Left: 5, Right: 5, Top: 5, Bottom: 5})
+	content := newTestView(&log, "content", rect(0, 0, 100, 300))
+	root.AddChild(scroller)
+	scroller.AddChild(content)
+
+	view, ancestors := gkit.HitTest(root, gkit.Point{X: 20, Y: 20})
+	if hitName(view) != "content" {
+		t.Fatalf("HitTest = %s, want content", hitName(view))
+	}
+	want := gkit.Point{X: 10, Y: 160}
+	if local := gkit.LocalPoint(append(ancestors, view), gkit.Point{X: 20, Y: 20}); local != want {
+		t.Errorf("LocalPoint = %v, want %v", local, want)
+	}
+
+	// The border of the scroller is outside of its Bounds and doesn't
+	// forward hits to the content.
+	if view, _ := gkit.HitTest(root, gkit.Point{X: 12, Y: 12}); hitName(view) != "scroller" {
+		t.Errorf("HitTest on border = %s, want scroller", hitName(view))
+	}
+}
+
+func TestHitTestTransform(t *testing.T) {
+	var log []string
+	root := newTestView(&log, "root", rect(0, 0, 200, 200))
+	scaled := newTestView(&log, "scaled", rect(10, 10, 50, 50))
+	scaled.SetTransform(gkit.Scaling(2, 2))
+	root.AddChild(scaled)
+	flat := newTestView(&log, "flat", rect(100, 100, 50, 50))
+	flat.SetTransform(gkit.Scaling(1, 0))
+	root.AddChild(flat)
+
+	for _, test := range []struct {
+		p     gkit.Point
+		want  string
+		local gkit.Point
+	}{
+		{gkit.Point{X: 20, Y: 20}, "scaled", gkit.Point{X: 5, Y: 5}},
+		{gkit.Point{X: 100, Y: 100}, "scaled", gkit.Point{X: 45, Y: 45}},
+		{gkit.Point{X: 115, Y: 115}, "root", gkit.Point{X: 115, Y: 115}},
+		// A transform that can't be inverted never receives hits.
+		{gkit.Point{X: 120, Y: 100}, "root", gkit.Point{X: 120, Y: 100}},
+	} {
+		view, ancestors := gkit.HitTest(root, test.p)
+		if hitName(view) != test.want {
+			t.Errorf("HitTest(%v) = %s, want %s", test.p, hitName(view), test.want)
+			continue
+		}
+		if local := gkit.LocalPoint(append(ancestors, view), test.p); local != test.local {
+			t.Errorf("LocalPoint(%v) = %v, want %v", test.p, local, test.local)
+		}
+	}
+}
+
+func TestHitTestSkipsTransparent(t *testing.T) {
+	var log []string
+	root, parent, child := testTree(&log)
+	// A transparent view on top doesn't hide the views below it.
+	cover := newTestView(&log, "cover", rect(0, 0, 200, 200))
+	cover.SetOpacity(0)
+	root.AddChild(cover)
+
+	if view, _ := gkit.HitTest(root, gkit.Point{X: 40, Y: 40}); hitName(view) != "child" {
+		t.Errorf("HitTest under transparent cover = %s, want child", hitName(view))
+	}
+
+	parent.SetOpacity(0)
+	if view, _ := gkit.HitTest(root, gkit.Point{X: 40, Y: 40}); hitName(view) != "root" {
+		t.Errorf("HitTest in transparent subtree = %s, want root", hitName(view))
+	}
+
+	parent.SetOpacity(0.5)
+	if view, _ := gkit.HitTest(root, gkit.Point{X: 40, Y: 40}); view != gkit.View(child) {
+		t.Errorf("HitTest in translucent subtree = %s, want child", hitName(view))
+	}
+}
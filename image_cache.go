@@ -0,0 +1,12 @@
+package gkit
+
+import (
+	"image"
+)
+
+// ImageCache is implemented by windows that keep images drawn with
+// Painter.DrawImage between frames. Images are identified by their value,
+// an image changed in place has to be invalidated to be drawn again.
+type ImageCache interface {
+	InvalidateImage(img image.Image)
+}
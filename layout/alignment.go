@@ -0,0 +1,39 @@
+package layout
+
+type Alignment uint8
+
+const (
+	// AlignAuto is only meaningful for FlexItem.AlignSelf and the
+	// alignments of a GridCell, where it means the child follows the
+	// alignment of its container. It is the zero value so that a FlexItem
+	// or GridCell literal doesn't override the container by accident.
+	AlignAuto Alignment = iota
+	AlignStart
+	AlignCenter
+	AlignEnd
+	AlignFill
+)
+
+// align places an item of the given size into space and returns its offset
+// and final size.
+func (a Alignment) align(size, min, max, space uint32) (uint32, uint32) {
+	if a == AlignFill {
+		size = space
+		if max != 0 && size > max {
+			size = max
+		}
+		if size < min {
+			size = min
+		}
+	}
+	if size > space {
+		return 0, size
+	}
+	switch a {
+	case AlignCenter:
+		return (space - size) / 2, size
+	case AlignEnd:
+		return space - size, size
+	}
+	return 0, size
+}
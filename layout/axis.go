@@ -0,0 +1,79 @@
+package layout
+
+import (
+	"github.com/alex-ac/gkit"
+)
+
+type axis uint8
+
+const (
+	horizontal axis = iota
+	vertical
+)
+
+func (a axis) main(s gkit.Size) uint32 {
+	if a == horizontal {
+		return s.Width
+	}
+	return s.Height
+}
+
+func (a axis) cross(s gkit.Size) uint32 {
+	if a == horizontal {
+		return s.Height
+	}
+	return s.Width
+}
+
+func (a axis) size(main, cross uint32) gkit.Size {
+	if a == horizontal {
+		return gkit.Size{Width: main, Height: cross}
+	}
+	return gkit.Size{Width: cross, Height: main}
+}
+
+func (a axis) point(main, cross uint32) gkit.Point {
+	if a == horizontal {
+		return gkit.Point{X: main, Y: cross}
+	}
+	return gkit.Point{X: cross, Y: main}
+}
+
+func (a axis) origin(r gkit.Rect) (uint32, uint32) {
+	if a == horizontal {
+		return r.X, r.Y
+	}
+	return r.Y, r.X
+}
+
+// outsetMax adds insets to a max size, keeping zero as "unbounded".
+func outsetMax(s gkit.Size, insets gkit.SideValues) gkit.Size {
+	if s.Width != 0 {
+		s.Width += insets.Left + insets.Right
+	}
+	if s.Height != 0 {
+		s.Height += insets.Top + insets.Bottom
+	}
+	return s
+}
+
+func max(a, b uint32) uint32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func sub(a, b uint32) uint32 {
+	if a < b {
+		return 0
+	}
+	return a - b
+}
+
+func gaps(count int, gap uint32) uint32 {
+	if count < 2 {
+		return 0
+	}
+	return uint32(count-1) * gap
+}
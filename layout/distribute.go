@@ -0,0 +1,99 @@
+package layout
+
+// item is a single child along the main axis of a container. A zero max
+// means the item can grow without limit.
+type item struct {
+	min     float64
+	size    float64
+	max     float64
+	stretch float64
+	shrink  float64
+}
+
+func (i *item) frozenGrow() bool {
+	return i.stretch <= 0 || (i.max != 0 && i.size >= i.max)
+}
+
+// distribute resizes items so that they fill space. Extra space is shared
+// between items in proportion to their stretch factors and up to their
+// maximum sizes. Missing space is taken in proportion to shrink factors
+// weighted by the item size, down to the minimum sizes.
+func distribute(items []item, space float64) {
+	total := 0.0
+	for _, i := range items {
+		total += i.size
+	}
+	if total < space {
+		grow(items, space-total)
+	} else if total > space {
+		shrink(items, total-space)
+	}
+}
+
+func grow(items []item, extra float64) {
+	for extra > 0.5 {
+		factors := 0.0
+		for i := range items {
+			if !items[i].frozenGrow() {
+				factors += items[i].stretch
+			}
+		}
+		if factors == 0 {
+			return
+		}
+		used := 0.0
+		for i := range items {
+			it := &items[i]
+			if it.frozenGrow() {
+				continue
+			}
+			add := extra * it.stretch / factors
+			if it.max != 0 && it.size+add > it.max {
+				add = it.max - it.size
+			}
+			it.size += add
+			used += add
+		}
+		if used == 0 {
+			return
+		}
+		extra -= used
+	}
+}
+
+func shrink(items []item, deficit float64) {
+	for deficit > 0.5 {
+		weights := 0.0
+		for _, it := range items {
+			weights += it.shrinkWeight()
+		}
+		if weights == 0 {
+			return
+		}
+		used := 0.0
+		for i := range items {
+			it := &items[i]
+			weight := it.shrinkWeight()
+			if weight == 0 {
+				continue
+			}
+			cut := deficit * weight / weights
+			if it.size-cut < it.min {
+				cut = it.size - it.min
+			}
+			it.size -= cut
+			used += cut
+		}
+		if used == 0 {
+			return
+		}
+		deficit -= used
+	}
+}
+
+func (i *item) shrinkWeight() float64 {
+	if i.size <= i.min {
+		return 0
+	}
+	return i.shrink * i.size
+}
@@ -0,0 +1,71 @@
+package layout
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistribute(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		items []item
+		space float64
+		want  []float64
+	}{
+		{
+			name:  "grow evenly",
+			items: []item{{size: 10, stretch: 1}, {size: 10, stretch: 1}},
+			space: 40,
+			want:  []float64{20, 20},
+		},
+		{
+			name:  "grow by stretch",
+			items: []item{{stretch: 1}, {stretch: 3}},
+			space: 40,
+			want:  []float64{10, 30},
+		},
+		{
+			name:  "grow up to max",
+			items: []item{{max: 5, stretch: 1}, {stretch: 1}},
+			space: 40,
+			want:  []float64{5, 35},
+		},
+		{
+			name:  "no stretch",
+			items: []item{{size: 10}, {size: 10}},
+			space: 40,
+			want:  []float64{10, 10},
+		},
+		{
+			name:  "exact fit",
+			items: []item{{size: 10, stretch: 1, shrink: 1}, {size: 30, stretch: 1, shrink: 1}},
+			space: 40,
+			want:  []float64{10, 30},
+		},
+		{
+			name:  "shrink by size",
+			items: []item{{size: 30, shrink: 1}, {size: 10, shrink: 1}},
+			space: 20,
+			want:  []float64{15, 5},
+		},
+		{
+			name:  "shrink down to min",
+			items: []item{{min: 25, size: 30, shrink: 1}, {size: 10, shrink: 1}},
+			space: 20,
+			want:  []float64{25, 0},
+		},
+		{
+			name:  "no shrink",
+			items: []item{{size: 30}, {size: 10, shrink: 1}},
+			space: 30,
+			want:  []float64{30, 0},
+		},
+	} {
+		distribute(test.items, test.space)
+		for i, it := range test.items {
+			if math.Abs(it.size-test.want[i]) > 1e-9 {
+				t.Errorf("%s: item %d size = %v, want %v", test.name, i, it.size, test.want[i])
+			}
+		}
+	}
+}
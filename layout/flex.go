@@ -0,0 +1,387 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/alex-ac/gkit"
+)
+
+type FlexDirection uint8
+
+const (
+	FlexRow FlexDirection = iota
+	FlexRowReverse
+	FlexColumn
+	FlexColumnReverse
+)
+
+func (d FlexDirection) axis() axis {
+	if d == FlexColumn || d == FlexColumnReverse {
+		return vertical
+	}
+	return horizontal
+}
+
+func (d FlexDirection) reversed() bool {
+	return d == FlexRowReverse || d == FlexColumnReverse
+}
+
+type FlexWrap uint8
+
+const (
+	FlexNoWrap FlexWrap = iota
+	FlexWrapForward
+	FlexWrapReverse
+)
+
+type Justify uint8
+
+const (
+	JustifyStart Justify = iota
+	JustifyCenter
+	JustifyEnd
+	JustifySpaceBetween
+	JustifySpaceAround
+	JustifySpaceEvenly
+)
+
+// offsets returns where the first item starts and how much extra space goes
+// between two adjacent items when free space is left on a line.
+func (j Justify) offsets(free float64, count int) (float64, float64) {
+	if free <= 0 || count == 0 {
+		return 0, 0
+	}
+	n := float64(count)
+	switch j {
+	case JustifyCenter:
+		return free / 2, 0
+	case JustifyEnd:
+		return free, 0
+	case JustifySpaceBetween:
+		if count == 1 {
+			return 0, 0
+		}
+		return 0, free / (n - 1)
+	case JustifySpaceAround:
+		return free / n / 2, free / n
+	case JustifySpaceEvenly:
+		return free / (n + 1), free / (n + 1)
+	}
+	return 0, 0
+}
+
+// FlexBasis is the main size of a flex item before free space is
+// distributed. The zero value, FlexBasisAuto, uses the item's PrefSize.
+// FlexBasisZero starts the item from nothing, so that its size comes from
+// Grow alone. Positive values are sizes in pixels.
+type FlexBasis int32
+
+const (
+	FlexBasisAuto FlexBasis = 0
+	FlexBasisZero FlexBasis = -1
+)
+
+type FlexItem struct {
+	Grow      float32
+	Shrink    float32
+	Basis     FlexBasis
+	AlignSelf Alignment
+}
+
+// DefaultFlexItem matches the CSS initial values: flex: 0 1 auto.
+func DefaultFlexItem() FlexItem {
+	return FlexItem{
+		Grow:      0,
+		Shrink:    1,
+		Basis:     FlexBasisAuto,
+		AlignSelf: AlignAuto,
+	}
+}
+
+// Flex lays its children out like a CSS flex container. MinSize and MaxSize
+// of the children act as min-width/max-width, PrefSize as their content
+// size.
+type Flex struct {
+	gkit.ViewBase
+
+	direction    FlexDirection
+	wrap         FlexWrap
+	justify      Justify
+	alignItems   Alignment
+	alignContent Alignment
+	rowGap       uint32
+	columnGap    uint32
+
+	items map[gkit.View]FlexItem
+}
+
+var _ gkit.View = &Flex{}
+
+func NewFlex() *Flex {
+	flex := &Flex{
+		alignItems:   AlignFill,
+		alignContent: AlignFill,
+		items:        make(map[gkit.View]FlexItem),
+	}
+	flex.ViewBase.View = flex
+	return flex
+}
+
+func (f *Flex) SetDirection(direction FlexDirection) {
+	if f.direction != direction {
+		f.direction = direction
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *Flex) Direction() FlexDirection {
+	return f.direction
+}
+
+func (f *Flex) SetWrap(wrap FlexWrap) {
+	if f.wrap != wrap {
+		f.wrap = wrap
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *Flex) Wrap() FlexWrap {
+	return f.wrap
+}
+
+func (f *Flex) SetJustifyContent(justify Justify) {
+	if f.justify != justify {
+		f.justify = justify
+		f.SetNeedsLayout()
+	}
+}
+
+func (f *Flex) JustifyContent() Justify {
+	return f.justify
+}
+
+func (f *Flex) SetAlignItems(alignment Alignment) {
+	if f.alignItems != alignment {
+		f.alignItems = alignment
+		f.SetNeedsLayout()
+	}
+}
+
+func (f *Flex) AlignItems() Alignment {
+	return f.alignItems
+}
+
+// SetAlignContent sets how lines are placed across the main axis when the
+// container wraps. AlignFill stretches lines to fill the container.
+func (f *Flex) SetAlignContent(alignment Alignment) {
+	if f.alignContent != alignment {
+		f.alignContent = alignment
+		f.SetNeedsLayout()
+	}
+}
+
+func (f *Flex) AlignContent() Alignment {
+	return f.alignContent
+}
+
+func (f *Flex) SetGaps(row, column uint32) {
+	if f.rowGap != row || f.columnGap != column {
+		f.rowGap, f.columnGap = row, column
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *Flex) Gaps() (uint32, uint32) {
+	return f.rowGap, f.columnGap
+}
+
+func (f *Flex) SetItem(child gkit.View, item FlexItem) {
+	if old, ok := f.items[child]; !ok || old != item {
+		f.items[child] = item
+		f.SetPrefSizeChanged()
+	}
+}
+
+func (f *Flex) Item(child gkit.View) FlexItem {
+	if item, ok := f.items[child]; ok {
+		return item
+	}
+	return DefaultFlexItem()
+}
+
+func (f *Flex) DeleteChild(child gkit.View) {
+	f.ViewBase.DeleteChild(child)
+	delete(f.items, child)
+}
+
+func (f *Flex) Update() {}
+
+func (f *Flex) Draw(p gkit.Painter) {}
+
+// gaps returns the gap between items on a line and the gap between lines.
+func (f *Flex) gaps() (uint32, uint32) {
+	if f.direction.axis() == horizontal {
+		return f.columnGap, f.rowGap
+	}
+	return f.rowGap, f.columnGap
+}
+
+func clamp(size, min, max uint32) uint32 {
+	if max != 0 && size > max {
+		size = max
+	}
+	if size < min {
+		size = min
+	}
+	return size
+}
+
+func (f *Flex) basis(child gkit.View) uint32 {
+	a := f.direction.axis()
+	item := f.Item(child)
+	basis := a.main(child.PrefSize())
+	if item.Basis < 0 {
+		basis = 0
+	} else if item.Basis != FlexBasisAuto {
+		basis = uint32(item.Basis)
+	}
+	return clamp(basis, a.main(child.MinSize()), a.main(child.MaxSize()))
+}
+
+func (f *Flex) UpdateSizes() {
+	a := f.direction.axis()
+	children := f.Children()
+	mainGap, _ := f.gaps()
+	var minMain, minCross, prefMain, prefCross uint32
+	for _, child := range children {
+		item := f.Item(child)
+		basis := f.basis(child)
+		prefMain += basis
+		prefCross = max(prefCross, a.cross(child.PrefSize()))
+		minCross = max(minCross, a.cross(child.MinSize()))
+		childMin := a.main(child.MinSize())
+		if item.Shrink == 0 {
+			childMin = basis
+		}
+		if f.wrap == FlexNoWrap {
+			minMain += childMin
+		} else {
+			minMain = max(minMain, childMin)
+		}
+	}
+	prefMain += gaps(len(children), mainGap)
+	if f.wrap == FlexNoWrap {
+		minMain += gaps(len(children), mainGap)
+	}
+	f.SetMinSize(a.size(minMain, minCross).Outset(f.Borders()))
+	f.SetPrefSize(a.size(prefMain, prefCross))
+	f.SetNeedsLayout()
+}
+
+type flexLine struct {
+	children []gkit.View
+	items    []item
+	cross    uint32
+}
+
+func (f *Flex) lines(space uint32) []*flexLine {
+	mainGap, _ := f.gaps()
+	var lines []*flexLine
+	var line *flexLine
+	var used uint32
+	for _, child := range f.Children() {
+		basis := f.basis(child)
+		if line == nil || (f.wrap != FlexNoWrap && len(line.children) > 0 && used+mainGap+basis > space) {
+			line = &flexLine{}
+			lines = append(lines, line)
+			used = 0
+		} else if len(line.children) > 0 {
+			used += mainGap
+		}
+		used += basis
+		line.children = append(line.children, child)
+	}
+	return lines
+}
+
+func (f *Flex) Layout() {
+	a := f.direction.axis()
+	bounds := f.Bounds()
+	mainOrigin, crossOrigin := a.origin(bounds)
+	mainSpace, crossSpace := a.main(bounds.Size), a.cross(bounds.Size)
+	mainGap, crossGap := f.gaps()
+
+	lines := f.lines(mainSpace)
+	var linesCross uint32
+	for _, line := range lines {
+		line.items = make([]item, len(line.children))
+		for i, child := range line.children {
+			flexItem := f.Item(child)
+			line.items[i] = item{
+				min:     float64(a.main(child.MinSize())),
+				size:    float64(f.basis(child)),
+				max:     float64(a.main(child.MaxSize())),
+				stretch: float64(flexItem.Grow),
+				shrink:  float64(flexItem.Shrink),
+			}
+			line.cross = max(line.cross, clamp(
+				a.cross(child.PrefSize()), a.cross(child.MinSize()), a.cross(child.MaxSize())))
+		}
+		distribute(line.items, float64(sub(mainSpace, gaps(len(line.children), mainGap))))
+		linesCross += line.cross
+	}
+
+	if f.wrap == FlexNoWrap && len(lines) == 1 {
+		lines[0].cross = crossSpace
+	}
+	freeCross := sub(crossSpace, linesCross+gaps(len(lines), crossGap))
+	crossPosition := float64(crossOrigin)
+	switch f.alignContent {
+	case AlignFill:
+		if len(lines) > 0 && f.wrap != FlexNoWrap {
+			extra := freeCross / uint32(len(lines))
+			for _, line := range lines {
+				line.cross += extra
+			}
+		}
+	case AlignCenter:
+		crossPosition += float64(freeCross) / 2
+	case AlignEnd:
+		crossPosition += float64(freeCross)
+	}
+
+	for _, line := range lines {
+		used := 0.0
+		for _, it := range line.items {
+			used += it.size
+		}
+		free := float64(mainSpace) - used - float64(gaps(len(line.children), mainGap))
+		start, between := f.justify.offsets(free, len(line.children))
+		position := start
+		lineCross := uint32(math.Round(crossPosition))
+		if f.wrap == FlexWrapReverse {
+			lineCross = crossOrigin + sub(crossSpace, lineCross-crossOrigin+line.cross)
+		}
+		for i, child := range line.children {
+			size := line.items[i].size
+			begin := math.Round(position)
+			end := math.Round(position + size)
+			position += size + float64(mainGap) + between
+			if f.direction.reversed() {
+				begin, end = math.Max(float64(mainSpace)-end, 0), math.Max(float64(mainSpace)-begin, 0)
+			}
+
+			alignment := f.Item(child).AlignSelf
+			if alignment == AlignAuto {
+				alignment = f.alignItems
+			}
+			offset, cross := alignment.align(
+				a.cross(child.PrefSize()), a.cross(child.MinSize()), a.cross(child.MaxSize()), line.cross)
+			child.SetFrame(gkit.Rect{
+				Point: a.point(mainOrigin+uint32(begin), lineCross+offset),
+				Size:  a.size(uint32(end-begin), cross),
+			})
+		}
+		crossPosition += float64(line.cross) + float64(crossGap)
+	}
+}
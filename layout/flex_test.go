@@ -0,0 +1,202 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+type flexChild struct {
+	pref gkit.Size
+	item *FlexItem
+}
+
+func TestFlexLayout(t *testing.T) {
+	fifty := flexChild{pref: size(50, 20)}
+	forty := flexChild{pref: size(40, 20)}
+	wrapped := func(wrap FlexWrap) func(*Flex) {
+		return func(f *Flex) {
+			f.SetWrap(wrap)
+			f.SetAlignContent(AlignStart)
+			f.SetAlignItems(AlignStart)
+		}
+	}
+	for _, test := range []struct {
+		name     string
+		setup    func(*Flex)
+		size     gkit.Size
+		children []flexChild
+		want     []gkit.Rect
+	}{
+		{
+			name: "grow from literal item",
+			size: size(300, 100),
+			children: []flexChild{
+				{pref: size(50, 20), item: &FlexItem{Grow: 1}},
+				{pref: size(50, 20), item: &FlexItem{Grow: 1}},
+			},
+			want: []gkit.Rect{rect(0, 0, 150, 100), rect(150, 0, 150, 100)},
+		},
+		{
+			name: "grow from basis",
+			size: size(300, 100),
+			children: []flexChild{
+				{pref: size(100, 20), item: &FlexItem{Grow: 1}},
+				{pref: size(20, 20), item: &FlexItem{Grow: 1, Basis: FlexBasisZero}},
+			},
+			want: []gkit.Rect{rect(0, 0, 200, 100), rect(200, 0, 100, 100)},
+		},
+		{
+			name: "fixed basis",
+			size: size(300, 100),
+			children: []flexChild{
+				{pref: size(100, 20), item: &FlexItem{Basis: 30}},
+			},
+			want: []gkit.Rect{rect(0, 0, 30, 100)},
+		},
+		{
+			name:     "shrink",
+			size:     size(100, 100),
+			children: []flexChild{{pref: size(100, 20)}, {pref: size(50, 20)}},
+			want:     []gkit.Rect{rect(0, 0, 67, 100), rect(67, 0, 33, 100)},
+		},
+		{
+			name:     "justify center",
+			setup:    func(f *Flex) { f.SetJustifyContent(JustifyCenter) },
+			size:     size(300, 100),
+			children: []flexChild{fifty, fifty},
+			want:     []gkit.Rect{rect(100, 0, 50, 100), rect(150, 0, 50, 100)},
+		},
+		{
+			name:     "justify space between",
+			setup:    func(f *Flex) { f.SetJustifyContent(JustifySpaceBetween) },
+			size:     size(300, 100),
+			children: []flexChild{fifty, fifty, fifty},
+			want:     []gkit.Rect{rect(0, 0, 50, 100), rect(125, 0, 50, 100), rect(250, 0, 50, 100)},
+		},
+		{
+			name:     "justify space evenly",
+			setup:    func(f *Flex) { f.SetJustifyContent(JustifySpaceEvenly) },
+			size:     size(250, 100),
+			children: []flexChild{fifty, fifty},
+			want:     []gkit.Rect{rect(50, 0, 50, 100), rect(150, 0, 50, 100)},
+		},
+		{
+			name:     "row reverse",
+			setup:    func(f *Flex) { f.SetDirection(FlexRowReverse) },
+			size:     size(300, 100),
+			children: []flexChild{fifty, {pref: size(100, 20)}},
+			want:     []gkit.Rect{rect(250, 0, 50, 100), rect(150, 0, 100, 100)},
+		},
+		{
+			name: "column",
+			setup: func(f *Flex) {
+				f.SetDirection(FlexColumn)
+				f.SetJustifyContent(JustifyEnd)
+				f.SetGaps(10, 0)
+			},
+			size:     size(100, 300),
+			children: []flexChild{fifty, fifty},
+			want:     []gkit.Rect{rect(0, 250, 100, 20), rect(0, 280, 100, 20)},
+		},
+		{
+			name: "align self",
+			setup: func(f *Flex) {
+				f.SetAlignItems(AlignCenter)
+			},
+			size: size(300, 100),
+			children: []flexChild{
+				fifty,
+				{pref: size(50, 20), item: &FlexItem{AlignSelf: AlignEnd}},
+				{pref: size(50, 20), item: &FlexItem{AlignSelf: AlignFill}},
+			},
+			want: []gkit.Rect{rect(0, 40, 50, 20), rect(50, 80, 50, 20), rect(100, 0, 50, 100)},
+		},
+		{
+			name:     "wrap",
+			setup:    wrapped(FlexWrapForward),
+			size:     size(100, 100),
+			children: []flexChild{forty, forty, forty},
+			want:     []gkit.Rect{rect(0, 0, 40, 20), rect(40, 0, 40, 20), rect(0, 20, 40, 20)},
+		},
+		{
+			name: "wrap with gaps",
+			setup: func(f *Flex) {
+				wrapped(FlexWrapForward)(f)
+				f.SetGaps(5, 30)
+			},
+			size:     size(100, 100),
+			children: []flexChild{forty, forty, forty},
+			want:     []gkit.Rect{rect(0, 0, 40, 20), rect(0, 25, 40, 20), rect(0, 50, 40, 20)},
+		},
+		{
+			name: "wrap with stretched lines",
+			setup: func(f *Flex) {
+				f.SetWrap(FlexWrapForward)
+			},
+			size:     size(100, 100),
+			children: []flexChild{forty, forty, forty},
+			want:     []gkit.Rect{rect(0, 0, 40, 50), rect(40, 0, 40, 50), rect(0, 50, 40, 50)},
+		},
+		{
+			name:     "wrap reverse",
+			setup:    wrapped(FlexWrapReverse),
+			size:     size(100, 100),
+			children: []flexChild{forty, forty, forty},
+			want:     []gkit.Rect{rect(0, 80, 40, 20), rect(40, 80, 40, 20), rect(0, 60, 40, 20)},
+		},
+		{
+			name:     "wrap reverse overflow",
+			setup:    wrapped(FlexWrapReverse),
+			size:     size(100, 30),
+			children: []flexChild{forty, forty, forty},
+			want:     []gkit.Rect{rect(0, 10, 40, 20), rect(40, 10, 40, 20), rect(0, 0, 40, 20)},
+		},
+	} {
+		flex := NewFlex()
+		if test.setup != nil {
+			test.setup(flex)
+		}
+		for _, c := range test.children {
+			child := newTestView(c.pref)
+			flex.AddChild(child)
+			if c.item != nil {
+				flex.SetItem(child, *c.item)
+			}
+		}
+		flex.SetSize(test.size)
+		flex.Layout()
+		checkFrames(t, test.name, flex.Children(), test.want)
+	}
+}
+
+func TestFlexUpdateSizes(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		wrap          FlexWrap
+		item          FlexItem
+		pref, minSize gkit.Size
+	}{
+		{"no wrap", FlexNoWrap, DefaultFlexItem(), size(110, 30), size(40, 20)},
+		{"wrap", FlexWrapForward, DefaultFlexItem(), size(110, 30), size(20, 20)},
+		{"no shrink", FlexNoWrap, FlexItem{}, size(110, 30), size(110, 20)},
+	} {
+		flex := NewFlex()
+		flex.SetWrap(test.wrap)
+		flex.SetGaps(0, 10)
+		first, second := newTestView(size(40, 20)), newTestView(size(60, 30))
+		first.SetMinSize(size(10, 20))
+		second.SetMinSize(size(20, 10))
+		flex.AddChild(first)
+		flex.AddChild(second)
+		flex.SetItem(first, test.item)
+		flex.SetItem(second, test.item)
+		flex.UpdateSizes()
+		if got := flex.PrefSize(); got != test.pref {
+			t.Errorf("%s: PrefSize() = %v, want %v", test.name, got, test.pref)
+		}
+		if got := flex.MinSize(); got != test.minSize {
+			t.Errorf("%s: MinSize() = %v, want %v", test.name, got, test.minSize)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/alex-ac/gkit"
+)
+
+type TrackKind uint8
+
+const (
+	TrackFixed TrackKind = iota
+	TrackAuto
+	TrackFraction
+)
+
+// Track is a grid row or column. Fixed tracks have a size in pixels, auto
+// tracks fit the PrefSize of their children and fraction tracks share the
+// space left over by the others.
+type Track struct {
+	Kind     TrackKind
+	Size     uint32
+	Fraction float32
+}
+
+func Fixed(size uint32) Track {
+	return Track{Kind: TrackFixed, Size: size}
+}
+
+func Auto() Track {
+	return Track{Kind: TrackAuto}
+}
+
+func Fraction(fraction float32) Track {
+	return Track{Kind: TrackFraction, Fraction: fraction}
+}
+
+// GridCell places a child into a grid. Spans below one are treated as one
+// and the zero alignments, AlignAuto, use the grid's alignment.
+type GridCell struct {
+	Row        int
+	Column     int
+	RowSpan    int
+	ColumnSpan int
+	HAlign     Alignment
+	VAlign     Alignment
+}
+
+// Cell returns a single-track cell that uses the grid's default alignment.
+func Cell(row, column int) GridCell {
+	return GridCell{
+		Row:        row,
+		Column:     column,
+		RowSpan:    1,
+		ColumnSpan: 1,
+	}
+}
+
+func (c GridCell) spans() (int, int) {
+	rowSpan, columnSpan := c.RowSpan, c.ColumnSpan
+	if rowSpan < 1 {
+		rowSpan = 1
+	}
+	if columnSpan < 1 {
+		columnSpan = 1
+	}
+	return rowSpan, columnSpan
+}
+
+// Grid places its children into rows and columns. Children without a cell
+// are placed into the first free cells in row-major order. Rows needed by
+// children beyond the configured ones are added as auto tracks.
+type Grid struct {
+	gkit.ViewBase
+
+	rows      []Track
+	columns   []Track
+	rowGap    uint32
+	columnGap uint32
+	hAlign    Alignment
+	vAlign    Alignment
+
+	cells map[gkit.View]GridCell
+}
+
+var _ gkit.View = &Grid{}
+
+func NewGrid() *Grid {
+	grid := &Grid{
+		hAlign: AlignFill,
+		vAlign: AlignFill,
+		cells:  make(map[gkit.View]GridCell),
+	}
+	grid.ViewBase.View = grid
+	return grid
+}
+
+func (g *Grid) SetRows(rows ...Track) {
+	g.rows = rows
+	g.SetPrefSizeChanged()
+}
+
+func (g *Grid) Rows() []Track {
+	return g.rows
+}
+
+func (g *Grid) SetColumns(columns ...Track) {
+	g.columns = columns
+	g.SetPrefSizeChanged()
+}
+
+func (g *Grid) Columns() []Track {
+	return g.columns
+}
+
+func (g *Grid) SetGaps(row, column uint32) {
+	if g.rowGap != row || g.columnGap != column {
+		g.rowGap, g.columnGap = row, column
+		g.SetPrefSizeChanged()
+	}
+}
+
+func (g *Grid) Gaps() (uint32, uint32) {
+	return g.rowGap, g.columnGap
+}
+
+// SetAlignment sets the alignment of cells that use AlignAuto.
+func (g *Grid) SetAlignment(h, v Alignment) {
+	if g.hAlign != h || g.vAlign != v {
+		g.hAlign, g.vAlign = h, v
+		g.SetNeedsLayout()
+	}
+}
+
+func (g *Grid) Alignment() (Alignment, Alignment) {
+	return g.hAlign, g.vAlign
+}
+
+// SetCell places child into cell. Negative rows and columns are clamped to
+// zero and spans below one are treated as one. Cells that run past the
+// configured tracks add auto tracks.
+func (g *Grid) SetCell(child gkit.View, cell GridCell) {
+	if cell.Row < 0 {
+		cell.Row = 0
+	}
+	if cell.Column < 0 {
+		cell.Column = 0
+	}
+	cell.RowSpan, cell.ColumnSpan = cell.spans()
+	if old, ok := g.cells[child]; !ok || old != cell {
+		g.cells[child] = cell
+		g.SetPrefSizeChanged()
+	}
+}
+
+func (g *Grid) Cell(child gkit.View) (GridCell, bool) {
+	cell, ok := g.cells[child]
+	return cell, ok
+}
+
+func (g *Grid) DeleteChild(child gkit.View) {
+	g.ViewBase.DeleteChild(child)
+	delete(g.cells, child)
+}
+
+func (g *Grid) Update() {}
+
+func (g *Grid) Draw(p gkit.Painter) {}
+
+// placement resolves the cells of all children, auto-placing those without
+// one, and returns the number of rows and columns in use.
+func (g *Grid) placement() ([]GridCell, int, int) {
+	children := g.Children()
+	cells := make([]GridCell, len(children))
+	rows, columns := len(g.rows), len(g.columns)
+	occupied := make(map[[2]int]bool)
+	occupy := func(cell GridCell) {
+		rowSpan, columnSpan := cell.spans()
+		for r := cell.Row; r < cell.Row+rowSpan; r++ {
+			for c := cell.Column; c < cell.Column+columnSpan; c++ {
+				occupied[[2]int{r, c}] = true
+			}
+		}
+		if cell.Row+rowSpan > rows {
+			rows = cell.Row + rowSpan
+		}
+		if cell.Column+columnSpan > columns {
+			columns = cell.Column + columnSpan
+		}
+	}
+	for i, child := range children {
+		if cell, ok := g.cells[child]; ok {
+			cells[i] = cell
+			occupy(cell)
+		}
+	}
+	if columns == 0 {
+		columns = 1
+	}
+	next := 0
+	for i, child := range children {
+		if _, ok := g.cells[child]; ok {
+			continue
+		}
+		for occupied[[2]int{next / columns, next % columns}] {
+			next++
+		}
+		cells[i] = Cell(next/columns, next%columns)
+		occupy(cells[i])
+	}
+	return cells, rows, columns
+}
+
+func implicitTracks(tracks []Track, count int) []Track {
+	for len(tracks) < count {
+		tracks = append(tracks, Auto())
+	}
+	return tracks
+}
+
+type span struct {
+	start int
+	count int
+	size  uint32
+}
+
+// sizeTracks computes track sizes. If space is negative, fraction tracks get
+// the size their children ask for, otherwise they share what is left of
+// space.
+func sizeTracks(tracks []Track, spans []span, gap uint32, space int64) []float64 {
+	sizes := make([]float64, len(tracks))
+	fractions := 0.0
+	for i, track := range tracks {
+		switch track.Kind {
+		case TrackFixed:
+			sizes[i] = float64(track.Size)
+		case TrackFraction:
+			fractions += float64(track.Fraction)
+		}
+	}
+	for _, s := range spans {
+		if s.count == 1 && tracks[s.start].Kind == TrackAuto {
+			sizes[s.start] = math.Max(sizes[s.start], float64(s.size))
+		}
+	}
+nextSpan:
+	for _, s := range spans {
+		if s.count == 1 {
+			continue
+		}
+		covered := float64(gaps(s.count, gap))
+		var autos []int
+		for i := s.start; i < s.start+s.count; i++ {
+			covered += sizes[i]
+			switch tracks[i].Kind {
+			case TrackAuto:
+				autos = append(autos, i)
+			case TrackFraction:
+				// Spans over a fraction track are left to the fraction sizing below.
+				continue nextSpan
+			}
+		}
+		if len(autos) > 0 && float64(s.size) > covered {
+			extra := (float64(s.size) - covered) / float64(len(autos))
+			for _, i := range autos {
+				sizes[i] += extra
+			}
+		}
+	}
+	if fractions == 0 {
+		return sizes
+	}
+
+	unit := 0.0
+	if space >= 0 {
+		used := float64(gaps(len(tracks), gap))
+		for i, track := range tracks {
+			if track.Kind != TrackFraction {
+				used += sizes[i]
+			}
+		}
+		unit = math.Max(float64(space)-used, 0) / fractions
+	} else {
+		for _, s := range spans {
+			covered := float64(gaps(s.count, gap))
+			spanFractions := 0.0
+			for i := s.start; i < s.start+s.count; i++ {
+				if tracks[i].Kind == TrackFraction {
+					spanFractions += float64(tracks[i].Fraction)
+				} else {
+					covered += sizes[i]
+				}
+			}
+			if spanFractions > 0 {
+				unit = math.Max(unit, (float64(s.size)-covered)/spanFractions)
+			}
+		}
+	}
+	for i, track := range tracks {
+		if track.Kind == TrackFraction {
+			sizes[i] = unit * float64(track.Fraction)
+		}
+	}
+	return sizes
+}
+
+func (g *Grid) spans(cells []GridCell, a axis, size func(gkit.View) gkit.Size) []span {
+	children := g.Children()
+	spans := make([]span, len(children))
+	for i, child := range children {
+		rowSpan, columnSpan := cells[i].spans()
+		if a == horizontal {
+			spans[i] = span{cells[i].Column, columnSpan, size(child).Width}
+		} else {
+			spans[i] = span{cells[i].Row, rowSpan, size(child).Height}
+		}
+	}
+	return spans
+}
+
+func total(sizes []float64, gap uint32) uint32 {
+	sum := float64(gaps(len(sizes), gap))
+	for _, size := range sizes {
+		sum += size
+	}
+	return uint32(math.Ceil(sum))
+}
+
+func (g *Grid) UpdateSizes() {
+	cells, rowCount, columnCount := g.placement()
+	rows, columns := implicitTracks(g.rows, rowCount), implicitTracks(g.columns, columnCount)
+	pref := func(v gkit.View) gkit.Size { return v.PrefSize() }
+	minSize := func(v gkit.View) gkit.Size { return v.MinSize() }
+
+	g.SetPrefSize(gkit.Size{
+		Width:  total(sizeTracks(columns, g.spans(cells, horizontal, pref), g.columnGap, -1), g.columnGap),
+		Height: total(sizeTracks(rows, g.spans(cells, vertical, pref), g.rowGap, -1), g.rowGap),
+	})
+	g.SetMinSize(gkit.Size{
+		Width:  total(sizeTracks(columns, g.spans(cells, horizontal, minSize), g.columnGap, -1), g.columnGap),
+		Height: total(sizeTracks(rows, g.spans(cells, vertical, minSize), g.rowGap, -1), g.rowGap),
+	}.Outset(g.Borders()))
+	g.SetNeedsLayout()
+}
+
+// positions turns track sizes into rounded track start and end offsets.
+func positions(sizes []float64, gap uint32, origin uint32) ([]uint32, []uint32) {
+	starts, ends := make([]uint32, len(sizes)), make([]uint32, len(sizes))
+	position := float64(origin)
+	for i, size := range sizes {
+		starts[i] = uint32(math.Round(position))
+		position += size
+		ends[i] = uint32(math.Round(position))
+		position += float64(gap)
+	}
+	return starts, ends
+}
+
+func (g *Grid) Layout() {
+	cells, rowCount, columnCount := g.placement()
+	rows, columns := implicitTracks(g.rows, rowCount), implicitTracks(g.columns, columnCount)
+	pref := func(v gkit.View) gkit.Size { return v.PrefSize() }
+	bounds := g.Bounds()
+
+	columnSizes := sizeTracks(columns, g.spans(cells, horizontal, pref), g.columnGap, int64(bounds.Width))
+	rowSizes := sizeTracks(rows, g.spans(cells, vertical, pref), g.rowGap, int64(bounds.Height))
+	lefts, rights := positions(columnSizes, g.columnGap, bounds.X)
+	tops, bottoms := positions(rowSizes, g.rowGap, bounds.Y)
+
+	for i, child := range g.Children() {
+		cell := cells[i]
+		rowSpan, columnSpan := cell.spans()
+		left, right := lefts[cell.Column], rights[cell.Column+columnSpan-1]
+		top, bottom := tops[cell.Row], bottoms[cell.Row+rowSpan-1]
+
+		hAlign, vAlign := cell.HAlign, cell.VAlign
+		if hAlign == AlignAuto {
+			hAlign = g.hAlign
+		}
+		if vAlign == AlignAuto {
+			vAlign = g.vAlign
+		}
+		x, width := hAlign.align(child.PrefSize().Width, child.MinSize().Width, child.MaxSize().Width, right-left)
+		y, height := vAlign.align(child.PrefSize().Height, child.MinSize().Height, child.MaxSize().Height, bottom-top)
+		child.SetFrame(gkit.Rect{
+			Point: gkit.Point{X: left + x, Y: top + y},
+			Size:  gkit.Size{Width: width, Height: height},
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+type gridChild struct {
+	pref gkit.Size
+	cell *GridCell
+}
+
+func TestGridLayout(t *testing.T) {
+	small := gridChild{pref: size(20, 10)}
+	for _, test := range []struct {
+		name     string
+		setup    func(*Grid)
+		size     gkit.Size
+		children []gridChild
+		want     []gkit.Rect
+	}{
+		{
+			name: "fixed and fraction columns",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50), Fraction(1), Fraction(3))
+				g.SetRows(Fraction(1))
+			},
+			size:     size(250, 100),
+			children: []gridChild{small, small, small},
+			want:     []gkit.Rect{rect(0, 0, 50, 100), rect(50, 0, 50, 100), rect(100, 0, 150, 100)},
+		},
+		{
+			name: "auto column fits content",
+			setup: func(g *Grid) {
+				g.SetColumns(Auto(), Fraction(1))
+				g.SetRows(Fraction(1))
+			},
+			size:     size(200, 50),
+			children: []gridChild{{pref: size(70, 10)}, small},
+			want:     []gkit.Rect{rect(0, 0, 70, 50), rect(70, 0, 130, 50)},
+		},
+		{
+			name: "gaps",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50), Fixed(50))
+				g.SetRows(Fixed(20), Fixed(20))
+				g.SetGaps(5, 10)
+			},
+			size:     size(200, 100),
+			children: []gridChild{small, small, small, small},
+			want: []gkit.Rect{
+				rect(0, 0, 50, 20), rect(60, 0, 50, 20),
+				rect(0, 25, 50, 20), rect(60, 25, 50, 20),
+			},
+		},
+		{
+			name: "implicit rows",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50), Fixed(50))
+			},
+			size:     size(200, 100),
+			children: []gridChild{small, small, {pref: size(20, 30)}},
+			want:     []gkit.Rect{rect(0, 0, 50, 10), rect(50, 0, 50, 10), rect(0, 10, 50, 30)},
+		},
+		{
+			name: "column span",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50), Fixed(50), Fixed(50))
+				g.SetRows(Fixed(20))
+				g.SetGaps(0, 10)
+			},
+			size: size(200, 100),
+			children: []gridChild{
+				{pref: size(20, 10), cell: &GridCell{ColumnSpan: 2}},
+				small,
+			},
+			want: []gkit.Rect{rect(0, 0, 110, 20), rect(120, 0, 50, 20)},
+		},
+		{
+			name: "span grows auto tracks",
+			setup: func(g *Grid) {
+				g.SetColumns(Auto(), Auto())
+			},
+			size: size(200, 100),
+			children: []gridChild{
+				{pref: size(100, 10), cell: &GridCell{ColumnSpan: 2}},
+				{pref: size(20, 10), cell: &GridCell{Row: 1}},
+				{pref: size(20, 10), cell: &GridCell{Row: 1, Column: 1}},
+			},
+			want: []gkit.Rect{rect(0, 0, 100, 10), rect(0, 10, 50, 10), rect(50, 10, 50, 10)},
+		},
+		{
+			name: "row span",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50), Fixed(50))
+				g.SetRows(Fixed(20), Fixed(20))
+			},
+			size: size(200, 100),
+			children: []gridChild{
+				{pref: size(20, 10), cell: &GridCell{Column: 1, RowSpan: 2}},
+				small, small,
+			},
+			want: []gkit.Rect{rect(50, 0, 50, 40), rect(0, 0, 50, 20), rect(0, 20, 50, 20)},
+		},
+		{
+			name: "alignment",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(100))
+				g.SetRows(Fixed(100))
+				g.SetAlignment(AlignStart, AlignStart)
+			},
+			size: size(200, 200),
+			children: []gridChild{
+				{pref: size(20, 10), cell: &GridCell{}},
+				{pref: size(20, 10), cell: &GridCell{HAlign: AlignEnd, VAlign: AlignCenter}},
+				{pref: size(20, 10), cell: &GridCell{HAlign: AlignFill}},
+			},
+			want: []gkit.Rect{rect(0, 0, 20, 10), rect(80, 45, 20, 10), rect(0, 0, 100, 10)},
+		},
+		{
+			name: "invalid cells",
+			setup: func(g *Grid) {
+				g.SetColumns(Fixed(50))
+				g.SetRows(Fixed(20))
+			},
+			size: size(200, 100),
+			children: []gridChild{
+				{pref: size(10, 10), cell: &GridCell{Row: -2, Column: -1, ColumnSpan: 3}},
+				{pref: size(10, 10), cell: &GridCell{Row: 1, Column: 2, RowSpan: -4}},
+			},
+			want: []gkit.Rect{rect(0, 0, 60, 20), rect(50, 20, 10, 10)},
+		},
+	} {
+		grid := NewGrid()
+		if test.setup != nil {
+			test.setup(grid)
+		}
+		for _, c := range test.children {
+			child := newTestView(c.pref)
+			grid.AddChild(child)
+			if c.cell != nil {
+				grid.SetCell(child, *c.cell)
+			}
+		}
+		grid.SetSize(test.size)
+		grid.Layout()
+		checkFrames(t, test.name, grid.Children(), test.want)
+	}
+}
+
+func TestGridSetCellNormalizes(t *testing.T) {
+	grid := NewGrid()
+	child := newTestView(size(10, 10))
+	grid.AddChild(child)
+	grid.SetCell(child, GridCell{Row: -1, Column: 2, ColumnSpan: -3})
+	want := GridCell{Row: 0, Column: 2, RowSpan: 1, ColumnSpan: 1}
+	if cell, ok := grid.Cell(child); !ok || cell != want {
+		t.Errorf("Cell() = %v, %v, want %v, true", cell, ok, want)
+	}
+	if cell := Cell(1, 2); cell.HAlign != AlignAuto || cell.VAlign != AlignAuto {
+		t.Errorf("Cell(1, 2) alignment = %v, %v, want AlignAuto", cell.HAlign, cell.VAlign)
+	}
+}
+
+func TestGridUpdateSizes(t *testing.T) {
+	grid := NewGrid()
+	grid.SetColumns(Auto(), Fixed(30), Fraction(1))
+	grid.SetGaps(4, 5)
+	grid.SetBorders(gkit.SideValues{Left: 1, Right: 1, Top: 1, Bottom: 1})
+	first := newTestView(size(40, 10))
+	first.SetMinSize(size(15, 5))
+	second := newTestView(size(10, 10))
+	third := newTestView(size(25, 20))
+	third.SetMinSize(size(12, 8))
+	fourth := newTestView(size(20, 10))
+	for _, child := range []gkit.View{first, second, third, fourth} {
+		grid.AddChild(child)
+	}
+	grid.UpdateSizes()
+
+	// Columns: auto 40, fixed 30, fraction 25 with two gaps of 5. The
+	// fourth child wraps into a second row.
+	if got, want := grid.PrefSize(), size(107, 36); got != want {
+		t.Errorf("PrefSize() = %v, want %v", got, want)
+	}
+	if got, want := grid.MinSize(), size(69, 14); got != want {
+		t.Errorf("MinSize() = %v, want %v", got, want)
+	}
+}
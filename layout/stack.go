@@ -0,0 +1,157 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/alex-ac/gkit"
+)
+
+// Stack lays its children out in a single row or column. Every child gets
+// its preferred size along the stack axis. Extra space goes to children with
+// a positive stretch factor, in proportion to it and up to their MaxSize;
+// missing space is taken from children in proportion to their size, down to
+// their MinSize. A zero component of MaxSize means the child can grow
+// without limit.
+type Stack struct {
+	gkit.ViewBase
+
+	axis      axis
+	spacing   uint32
+	alignment Alignment
+	stretch   map[gkit.View]float32
+}
+
+var _ gkit.View = &Stack{}
+
+func newStack(a axis) *Stack {
+	stack := &Stack{
+		axis:      a,
+		alignment: AlignFill,
+		stretch:   make(map[gkit.View]float32),
+	}
+	stack.ViewBase.View = stack
+	return stack
+}
+
+func NewVStack() *Stack {
+	return newStack(vertical)
+}
+
+func NewHStack() *Stack {
+	return newStack(horizontal)
+}
+
+func (s *Stack) SetSpacing(spacing uint32) {
+	if s.spacing != spacing {
+		s.spacing = spacing
+		s.SetPrefSizeChanged()
+	}
+}
+
+func (s *Stack) Spacing() uint32 {
+	return s.spacing
+}
+
+// SetAlignment sets how children are placed across the stack axis.
+func (s *Stack) SetAlignment(alignment Alignment) {
+	if s.alignment != alignment {
+		s.alignment = alignment
+		s.SetNeedsLayout()
+	}
+}
+
+func (s *Stack) Alignment() Alignment {
+	return s.alignment
+}
+
+func (s *Stack) SetStretch(child gkit.View, factor float32) {
+	if s.stretch[child] != factor {
+		s.stretch[child] = factor
+		s.SetNeedsLayout()
+	}
+}
+
+func (s *Stack) Stretch(child gkit.View) float32 {
+	return s.stretch[child]
+}
+
+func (s *Stack) DeleteChild(child gkit.View) {
+	s.ViewBase.DeleteChild(child)
+	delete(s.stretch, child)
+}
+
+func (s *Stack) Update() {}
+
+func (s *Stack) Draw(p gkit.Painter) {}
+
+func (s *Stack) UpdateSizes() {
+	children := s.Children()
+	var minMain, minCross, prefMain, prefCross, maxMain, maxCross uint32
+	unboundedMain, unboundedCross := false, false
+	for _, child := range children {
+		minSize, prefSize, maxSize := child.MinSize(), child.PrefSize(), child.MaxSize()
+		minMain += s.axis.main(minSize)
+		prefMain += s.axis.main(prefSize)
+		maxMain += s.axis.main(maxSize)
+		unboundedMain = unboundedMain || s.axis.main(maxSize) == 0
+		minCross = max(minCross, s.axis.cross(minSize))
+		prefCross = max(prefCross, s.axis.cross(prefSize))
+		maxCross = max(maxCross, s.axis.cross(maxSize))
+		unboundedCross = unboundedCross || s.axis.cross(maxSize) == 0
+	}
+	spacing := gaps(len(children), s.spacing)
+	if unboundedMain {
+		maxMain = 0
+	} else {
+		maxMain += spacing
+	}
+	if unboundedCross {
+		maxCross = 0
+	}
+	borders := s.Borders()
+	s.SetMinSize(s.axis.size(minMain+spacing, minCross).Outset(borders))
+	s.SetMaxSize(outsetMax(s.axis.size(maxMain, maxCross), borders))
+	s.SetPrefSize(s.axis.size(prefMain+spacing, prefCross))
+	s.SetNeedsLayout()
+}
+
+func (s *Stack) Layout() {
+	children := s.Children()
+	if len(children) == 0 {
+		return
+	}
+	bounds := s.Bounds()
+	mainOrigin, crossOrigin := s.axis.origin(bounds)
+	space := sub(s.axis.main(bounds.Size), gaps(len(children), s.spacing))
+	crossSpace := s.axis.cross(bounds.Size)
+
+	items := make([]item, len(children))
+	for i, child := range children {
+		items[i] = item{
+			min:     float64(s.axis.main(child.MinSize())),
+			size:    float64(s.axis.main(child.PrefSize())),
+			max:     float64(s.axis.main(child.MaxSize())),
+			stretch: float64(s.stretch[child]),
+			shrink:  1,
+		}
+	}
+	distribute(items, float64(space))
+
+	position := float64(mainOrigin)
+	for i, child := range children {
+		start := uint32(math.Round(position))
+		position += items[i].size
+		end := uint32(math.Round(position))
+		position += float64(s.spacing)
+
+		offset, cross := s.alignment.align(
+			s.axis.cross(child.PrefSize()),
+			s.axis.cross(child.MinSize()),
+			s.axis.cross(child.MaxSize()),
+			crossSpace)
+		child.SetFrame(gkit.Rect{
+			Point: s.axis.point(start, crossOrigin+offset),
+			Size:  s.axis.size(end-start, cross),
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+type testView struct {
+	gkit.ViewBase
+}
+
+func newTestView(pref gkit.Size) *testView {
+	view := &testView{}
+	view.ViewBase.View = view
+	view.SetPrefSize(pref)
+	return view
+}
+
+func (v *testView) Layout()             {}
+func (v *testView) Update()             {}
+func (v *testView) UpdateSizes()        {}
+func (v *testView) Draw(p gkit.Painter) {}
+
+func size(w, h uint32) gkit.Size {
+	return gkit.Size{Width: w, Height: h}
+}
+
+func rect(x, y, w, h uint32) gkit.Rect {
+	return gkit.Rect{Point: gkit.Point{X: x, Y: y}, Size: size(w, h)}
+}
+
+func checkFrames(t *testing.T, name string, children []gkit.View, want []gkit.Rect) {
+	t.Helper()
+	for i, child := range children {
+		if frame := child.Frame(); frame != want[i] {
+			t.Errorf("%s: child %d frame = %v, want %v", name, i, frame, want[i])
+		}
+	}
+}
+
+func TestHStackLayout(t *testing.T) {
+	for _, test := range []struct {
+		alignment Alignment
+		maxHeight uint32
+		want      []gkit.Rect
+	}{
+		{AlignStart, 0, []gkit.Rect{rect(0, 0, 40, 20), rect(50, 0, 150, 30)}},
+		{AlignCenter, 0, []gkit.Rect{rect(0, 15, 40, 20), rect(50, 10, 150, 30)}},
+		{AlignEnd, 0, []gkit.Rect{rect(0, 30, 40, 20), rect(50, 20, 150, 30)}},
+		{AlignFill, 0, []gkit.Rect{rect(0, 0, 40, 50), rect(50, 0, 150, 50)}},
+		{AlignFill, 40, []gkit.Rect{rect(0, 0, 40, 40), rect(50, 0, 150, 40)}},
+	} {
+		stack := NewHStack()
+		stack.SetSpacing(10)
+		stack.SetAlignment(test.alignment)
+		first, second := newTestView(size(40, 20)), newTestView(size(60, 30))
+		first.SetMaxSize(size(0, test.maxHeight))
+		second.SetMaxSize(size(0, test.maxHeight))
+		stack.AddChild(first)
+		stack.AddChild(second)
+		stack.SetStretch(second, 1)
+		stack.SetSize(size(200, 50))
+		stack.Layout()
+		checkFrames(t, "HStack", stack.Children(), test.want)
+	}
+}
+
+func TestVStackShrinks(t *testing.T) {
+	stack := NewVStack()
+	first, second := newTestView(size(50, 80)), newTestView(size(50, 40))
+	first.SetMinSize(size(0, 60))
+	stack.AddChild(first)
+	stack.AddChild(second)
+	stack.SetSize(size(100, 100))
+	stack.Layout()
+	checkFrames(t, "VStack", stack.Children(), []gkit.Rect{
+		rect(0, 0, 100, 67),
+		rect(0, 67, 100, 33),
+	})
+
+	// Once the first child is at its minimum, the rest comes from the
+	// second one.
+	stack.SetSize(size(100, 70))
+	stack.Layout()
+	checkFrames(t, "VStack", stack.Children(), []gkit.Rect{
+		rect(0, 0, 100, 60),
+		rect(0, 60, 100, 10),
+	})
+}
+
+func TestStackUpdateSizes(t *testing.T) {
+	stack := NewHStack()
+	stack.SetSpacing(10)
+	stack.SetBorders(gkit.SideValues{Left: 1, Right: 2, Top: 3, Bottom: 4})
+	first, second := newTestView(size(40, 20)), newTestView(size(60, 30))
+	first.SetMinSize(size(10, 5))
+	second.SetMinSize(size(20, 10))
+	first.SetMaxSize(size(0, 30))
+	second.SetMaxSize(size(100, 40))
+	stack.AddChild(first)
+	stack.AddChild(second)
+	stack.UpdateSizes()
+
+	if got, want := stack.PrefSize(), size(113, 37); got != want {
+		t.Errorf("PrefSize() = %v, want %v", got, want)
+	}
+	if got, want := stack.MinSize(), size(43, 17); got != want {
+		t.Errorf("MinSize() = %v, want %v", got, want)
+	}
+	// The first child can grow without limit horizontally.
+	if got, want := stack.MaxSize(), size(0, 47); got != want {
+		t.Errorf("MaxSize() = %v, want %v", got, want)
+	}
+}
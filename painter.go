@@ -17,10 +17,53 @@ type Layer interface {
 
 type Painter interface {
 	DrawLayer(r Rect, l Layer)
+	// DrawScrolledLayer draws l in the current coordinate space shifted by
+	// -offset. Everything l draws is clipped to clip, which is given in the
+	// current, unshifted coordinates.
+	DrawScrolledLayer(clip Rect, offset Point, l Layer)
+	// DrawCompositedLayer draws l like DrawLayer, but into an offscreen
+	// target first. The target is then blended with mode over what is
+	// drawn, its alpha multiplied by opacity.
+	DrawCompositedLayer(r Rect, l Layer, opacity float32, mode BlendMode)
+	// PushClip limits everything drawn until the matching PopClip to r,
+	// intersected with the current clip.
+	PushClip(r Rect)
+	PopClip()
+	// Transform returns the transform mapping what is drawn, layers
+	// included, into the coordinates of the painter's layer. The
+	// operations below apply before it, Save and Restore push and pop it.
+	// Clips of rotated or skewed layers are their bounding boxes.
+	Transform() Transform
+	Save()
+	Restore()
+	ApplyTransform(t Transform)
+	Translate(x, y float32)
+	Scale(sx, sy float32)
+	Rotate(angle float32)
+	Skew(ax, ay float32)
+	// SetColor sets a solid brush of color c. Images and shadows are drawn
+	// in their own colors, everything else with the brush.
 	SetColor(c Color)
+	SetBrush(b Brush)
+	// SetBlendMode sets how everything drawn after it, including
+	// images, is combined with the target.
+	SetBlendMode(mode BlendMode)
 	DrawRect(r Rect)
 	SetFont(f *Font)
 	SetFontSize(size uint32)
 	DrawText(p Point, text string)
 	DrawImage(r Rect, image image.Image)
+	// FillPath fills the inside of path, as decided by rule, with the
+	// current color. StrokePath draws its outline with the current color.
+	FillPath(path *Path, rule FillRule)
+	StrokePath(path *Path, style StrokeStyle)
+	// FillRoundedRect fills r with its corners rounded by radii, which are
+	// fitted to r. StrokeRoundedRect strokes the outline width wide,
+	// centered on it, DrawBorder strokes it inside of r.
+	FillRoundedRect(r Rect, radii CornerRadii)
+	StrokeRoundedRect(r Rect, radii CornerRadii, width float32)
+	DrawBorder(r Rect, radii CornerRadii, width float32)
+	// DrawBoxShadow draws the shadow of the rounded box r in the color of
+	// the shadow.
+	DrawBoxShadow(r Rect, radii CornerRadii, shadow BoxShadow)
 }
@@ -0,0 +1,282 @@
+package gkit
+
+import (
+	"math"
+)
+
+type FillRule uint8
+
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// PathTolerance is the distance in pixels painters let flattened curves
+// deviate from the exact ones.
+const PathTolerance = 0.25
+
+type pathVerb uint8
+
+const (
+	verbMove pathVerb = iota
+	verbLine
+	verbQuad
+	verbCubic
+	verbClose
+)
+
+type pathCommand struct {
+	verb   pathVerb
+	points [3]PointF
+}
+
+// Path is a sequence of subpaths made of lines and Bézier curves, drawn
+// with Painter.FillPath and Painter.StrokePath. Drawing commands without a
+// current subpath start one at their first point. The zero Path is empty.
+type Path struct {
+	commands []pathCommand
+	start    PointF
+	current  PointF
+	// started is true while there is a subpath to add to.
+	started bool
+}
+
+func (p *Path) add(verb pathVerb, points ...PointF) {
+	c := pathCommand{verb: verb}
+	copy(c.points[:], points)
+	p.commands = append(p.commands, c)
+	if len(points) > 0 {
+		p.current = points[len(points)-1]
+	}
+}
+
+func (p *Path) ensureSubpath(x, y float32) {
+	if !p.started {
+		p.MoveTo(x, y)
+	}
+}
+
+// Empty reports whether the path has no drawing commands.
+func (p *Path) Empty() bool {
+	return len(p.commands) == 0
+}
+
+// CurrentPoint returns the point the next command starts at.
+func (p *Path) CurrentPoint() PointF {
+	return p.current
+}
+
+func (p *Path) MoveTo(x, y float32) {
+	p.add(verbMove, PointF{x, y})
+	p.start = p.current
+	p.started = true
+}
+
+func (p *Path) LineTo(x, y float32) {
+	p.ensureSubpath(x, y)
+	p.add(verbLine, PointF{x, y})
+}
+
+// QuadTo adds a quadratic Bézier curve with the control point (cx, cy).
+func (p *Path) QuadTo(cx, cy, x, y float32) {
+	p.ensureSubpath(cx, cy)
+	p.add(verbQuad, PointF{cx, cy}, PointF{x, y})
+}
+
+// CubicTo adds a cubic Bézier curve with the control points (c1x, c1y) and
+// (c2x, c2y).
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float32) {
+	p.ensureSubpath(c1x, c1y)
+	p.add(verbCubic, PointF{c1x, c1y}, PointF{c2x, c2y}, PointF{x, y})
+}
+
+// ArcTo adds an elliptical arc to (x, y) like the A command of SVG paths:
+// the ellipse has the radii rx and ry and is rotated by rotation radians,
+// largeArc and sweep choose one of the four arcs that fit. The arc is
+// approximated by cubic curves.
+func (p *Path) ArcTo(rx, ry, rotation float32, largeArc, sweep bool, x, y float32) {
+	p.ensureSubpath(x, y)
+	from, to := p.current, PointF{x, y}
+	if from == to {
+		return
+	}
+	rx, ry = float32(math.Abs(float64(rx))), float32(math.Abs(float64(ry)))
+	if rx == 0 || ry == 0 {
+		p.LineTo(x, y)
+		return
+	}
+
+	// Conversion from endpoint to center parameterization, SVG 1.1
+	// appendix F.6.5.
+	sin, cos := math.Sincos(float64(rotation))
+	dx, dy := float64(from.X-to.X)/2, float64(from.Y-to.Y)/2
+	x1, y1 := cos*dx+sin*dy, -sin*dx+cos*dy
+	rx64, ry64 := float64(rx), float64(ry)
+	if scale := x1*x1/(rx64*rx64) + y1*y1/(ry64*ry64); scale > 1 {
+		rx64, ry64 = rx64*math.Sqrt(scale), ry64*math.Sqrt(scale)
+	}
+	num := rx64*rx64*ry64*ry64 - rx64*rx64*y1*y1 - ry64*ry64*x1*x1
+	den := rx64*rx64*y1*y1 + ry64*ry64*x1*x1
+	k := math.Sqrt(math.Max(num, 0) / den)
+	if largeArc == sweep {
+		k = -k
+	}
+	cx1, cy1 := k*rx64*y1/ry64, -k*ry64*x1/rx64
+	cx := cos*cx1 - sin*cy1 + float64(from.X+to.X)/2
+	cy := sin*cx1 + cos*cy1 + float64(from.Y+to.Y)/2
+
+	angle := func(ux, uy float64) float64 {
+		return math.Atan2(uy, ux)
+	}
+	start := angle((x1-cx1)/rx64, (y1-cy1)/ry64)
+	delta := angle((-x1-cx1)/rx64, (-y1-cy1)/ry64) - start
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	// Every piece of at most a quarter of the ellipse is a cubic curve of
+	// the unit circle mapped onto the ellipse.
+	ellipse := Translation(float32(cx), float32(cy)).
+		Mul(Rotation(rotation)).
+		Mul(Scaling(float32(rx64), float32(ry64)))
+	pieces := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	if pieces == 0 {
+		p.LineTo(x, y)
+		return
+	}
+	step := delta / float64(pieces)
+	kappa := 4.0 / 3.0 * math.Tan(step/4)
+	for i := 0; i < pieces; i++ {
+		a1 := start + float64(i)*step
+		a2 := a1 + step
+		sin1, cos1 := math.Sincos(a1)
+		sin2, cos2 := math.Sincos(a2)
+		c1 := ellipse.Apply(PointF{float32(cos1 - kappa*sin1), float32(sin1 + kappa*cos1)})
+		c2 := ellipse.Apply(PointF{float32(cos2 + kappa*sin2), float32(sin2 - kappa*cos2)})
+		end := ellipse.Apply(PointF{float32(cos2), float32(sin2)})
+		if i == pieces-1 {
+			end = to
+		}
+		p.add(verbCubic, c1, c2, end)
+	}
+}
+
+// Close closes the current subpath with a line to its start. Commands
+// after it start a new subpath at the same point.
+func (p *Path) Close() {
+	if !p.started {
+		return
+	}
+	p.add(verbClose)
+	p.current = p.start
+}
+
+// polyline is a flattened subpath.
+type polyline struct {
+	points []PointF
+	closed bool
+}
+
+// Polygons flattens the path into polygons mapped by t, one per subpath, to
+// be filled. Curves are approximated by lines no further than tolerance
+// from them after mapping.
+func (p *Path) Polygons(t Transform, tolerance float32) [][]PointF {
+	var polygons [][]PointF
+	for _, line := range p.flatten(t, tolerance) {
+		if len(line.points) > 2 {
+			polygons = append(polygons, line.points)
+		}
+	}
+	return polygons
+}
+
+func (p *Path) flatten(t Transform, tolerance float32) []polyline {
+	var lines []polyline
+	var current polyline
+	var start PointF
+	finish := func(closed bool) {
+		if len(current.points) > 0 {
+			current.closed = closed
+			lines = append(lines, current)
+		}
+		current = polyline{}
+	}
+	last := func() PointF {
+		if len(current.points) == 0 {
+			// A command after Close continues from the start of the
+			// closed subpath.
+			current.points = append(current.points, start)
+		}
+		return current.points[len(current.points)-1]
+	}
+	for _, c := range p.commands {
+		switch c.verb {
+		case verbMove:
+			finish(false)
+			start = t.Apply(c.points[0])
+			current.points = append(current.points, start)
+		case verbLine:
+			last()
+			current.points = append(current.points, t.Apply(c.points[0]))
+		case verbQuad:
+			// Affine transforms map Bézier curves to the curves of their
+			// mapped control points.
+			current.points = flattenQuad(current.points, last(), t.Apply(c.points[0]), t.Apply(c.points[1]), tolerance)
+		case verbCubic:
+			current.points = flattenCubic(current.points, last(), t.Apply(c.points[0]), t.Apply(c.points[1]), t.Apply(c.points[2]), tolerance)
+		case verbClose:
+			finish(true)
+		}
+	}
+	finish(false)
+	return lines
+}
+
+func (p PointF) distance(p2 PointF) float32 {
+	dx, dy := p2.X-p.X, p2.Y-p.Y
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
+
+func lerp(a, b PointF, t float32) PointF {
+	return PointF{a.X + (b.X-a.X)*t, a.Y + (b.Y-a.Y)*t}
+}
+
+// segments returns the number of lines approximating a curve whose control
+// polygon deviates by deviation from a line, within tolerance.
+func segments(deviation, tolerance float32) int {
+	if tolerance <= 0 {
+		tolerance = 0.25
+	}
+	n := int(math.Ceil(math.Sqrt(float64(deviation / tolerance))))
+	if n < 1 {
+		return 1
+	}
+	if n > 1000 {
+		return 1000
+	}
+	return n
+}
+
+func flattenQuad(points []PointF, p0, p1, p2 PointF, tolerance float32) []PointF {
+	dd := PointF{p0.X - 2*p1.X + p2.X, p0.Y - 2*p1.Y + p2.Y}.distance(PointF{})
+	n := segments(dd/4, tolerance)
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		points = append(points, lerp(lerp(p0, p1, t), lerp(p1, p2, t), t))
+	}
+	return points
+}
+
+func flattenCubic(points []PointF, p0, p1, p2, p3 PointF, tolerance float32) []PointF {
+	dd1 := PointF{p0.X - 2*p1.X + p2.X, p0.Y - 2*p1.Y + p2.Y}.distance(PointF{})
+	dd2 := PointF{p1.X - 2*p2.X + p3.X, p1.Y - 2*p2.Y + p3.Y}.distance(PointF{})
+	n := segments(max32(dd1, dd2)*3/4, tolerance)
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		a, b, c := lerp(p0, p1, t), lerp(p1, p2, t), lerp(p2, p3, t)
+		points = append(points, lerp(lerp(a, b, t), lerp(b, c, t), t))
+	}
+	return points
+}
@@ -0,0 +1,175 @@
+package gkit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func distance(a, b gkit.PointF) float64 {
+	return math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y))
+}
+
+// nearest returns the distance from p to the nearest vertex of polygon.
+func nearest(polygon []gkit.PointF, p gkit.PointF) float64 {
+	d := math.Inf(1)
+	for _, v := range polygon {
+		d = math.Min(d, distance(v, p))
+	}
+	return d
+}
+
+func TestArcTo(t *testing.T) {
+	const r = 10
+	d := float32(r * math.Sqrt2 / 2)
+	for _, test := range []struct {
+		name            string
+		rx, ry          float32
+		largeArc, sweep bool
+		to              gkit.PointF
+		center          gkit.PointF
+		radius          float64
+		through         gkit.PointF
+	}{
+		// The four arcs from (0, 0) to (10, 10) on circles of radius 10.
+		{"small ccw", r, r, false, false, gkit.PointF{X: 10, Y: 10}, gkit.PointF{X: 10, Y: 0}, r, gkit.PointF{X: 10 - d, Y: d}},
+		{"small cw", r, r, false, true, gkit.PointF{X: 10, Y: 10}, gkit.PointF{X: 0, Y: 10}, r, gkit.PointF{X: d, Y: 10 - d}},
+		{"large ccw", r, r, true, false, gkit.PointF{X: 10, Y: 10}, gkit.PointF{X: 0, Y: 10}, r, gkit.PointF{X: -d, Y: 10 + d}},
+		{"large cw", r, r, true, true, gkit.PointF{X: 10, Y: 10}, gkit.PointF{X: 10, Y: 0}, r, gkit.PointF{X: 10 + d, Y: -d}},
+		// Radii too small to reach the end are scaled up until they do.
+		{"scaled up", 1, 1, false, true, gkit.PointF{X: 20, Y: 0}, gkit.PointF{X: 10, Y: 0}, 10, gkit.PointF{X: 10, Y: -10}},
+		{"scaled up ellipse", 2, 1, false, false, gkit.PointF{X: 40, Y: 0}, gkit.PointF{X: 20, Y: 0}, 10, gkit.PointF{X: 20, Y: 10}},
+	} {
+		var path gkit.Path
+		path.MoveTo(0, 0)
+		path.ArcTo(test.rx, test.ry, 0, test.largeArc, test.sweep, test.to.X, test.to.Y)
+		if got := path.CurrentPoint(); got != test.to {
+			t.Errorf("%s: CurrentPoint() = %v, want %v", test.name, got, test.to)
+		}
+		const tolerance = 0.1
+		polygons := path.Polygons(gkit.Identity(), tolerance)
+		if len(polygons) != 1 {
+			t.Fatalf("%s: %d polygons, want 1", test.name, len(polygons))
+		}
+		polygon := polygons[0]
+		if last := polygon[len(polygon)-1]; last != test.to {
+			t.Errorf("%s: arc ends at %v, want %v", test.name, last, test.to)
+		}
+		// Squeezing the ellipse horizontally by rx/ry makes it a circle
+		// of radius ry.
+		scale := test.rx / test.ry
+		for _, v := range polygon {
+			v := gkit.PointF{X: test.center.X + (v.X-test.center.X)/scale, Y: v.Y}
+			if d := distance(v, test.center); math.Abs(d-test.radius) > tolerance {
+				t.Errorf("%s: vertex %v is %g from the center, want %g", test.name, v, d, test.radius)
+				break
+			}
+		}
+		if d := nearest(polygon, test.through); d > 1 {
+			t.Errorf("%s: arc passes %g from %v", test.name, d, test.through)
+		}
+	}
+}
+
+func TestArcToDegenerate(t *testing.T) {
+	var path gkit.Path
+	path.MoveTo(5, 5)
+	// Zero radii make a line, the same point nothing.
+	path.ArcTo(0, 10, 0, false, false, 15, 5)
+	path.ArcTo(10, 10, 0, false, false, 15, 5)
+	path.LineTo(15, 15)
+	polygons := path.Polygons(gkit.Identity(), gkit.PathTolerance)
+	want := []gkit.PointF{{X: 5, Y: 5}, {X: 15, Y: 5}, {X: 15, Y: 15}}
+	if len(polygons) != 1 || len(polygons[0]) != len(want) {
+		t.Fatalf("Polygons() = %v, want [%v]", polygons, want)
+	}
+	for i, p := range want {
+		if polygons[0][i] != p {
+			t.Errorf("vertex %d = %v, want %v", i, polygons[0][i], p)
+		}
+	}
+}
+
+// maxDeviation returns how far the lines of polygon get from the circle.
+func maxDeviation(polygon []gkit.PointF, center gkit.PointF, radius float64) float64 {
+	var deviation float64
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		mid := gkit.PointF{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+		deviation = math.Max(deviation, math.Abs(distance(mid, center)-radius))
+		deviation = math.Max(deviation, math.Abs(distance(a, center)-radius))
+	}
+	return deviation
+}
+
+func TestPolygonsTolerance(t *testing.T) {
+	var path gkit.Path
+	path.MoveTo(0, 100)
+	path.ArcTo(100, 100, 0, false, true, 200, 100)
+	path.ArcTo(100, 100, 0, false, true, 0, 100)
+	path.Close()
+
+	var previous int
+	for _, test := range []struct {
+		transform gkit.Transform
+		tolerance float32
+		center    gkit.PointF
+		radius    float64
+	}{
+		{gkit.Identity(), 1, gkit.PointF{X: 100, Y: 100}, 100},
+		{gkit.Identity(), 0.25, gkit.PointF{X: 100, Y: 100}, 100},
+		{gkit.Identity(), 0.01, gkit.PointF{X: 100, Y: 100}, 100},
+		// The tolerance holds after mapping.
+		{gkit.Scaling(4, 4), 0.25, gkit.PointF{X: 400, Y: 400}, 400},
+	} {
+		polygons := path.Polygons(test.transform, test.tolerance)
+		if len(polygons) != 1 {
+			t.Fatalf("%d polygons, want 1", len(polygons))
+		}
+		polygon := polygons[0]
+		// The cubic curves themselves are off the circle by 0.03%.
+		if d := maxDeviation(polygon, test.center, test.radius); d > float64(test.tolerance)+test.radius*0.0003 {
+			t.Errorf("tolerance %g, %v: polygon deviates %g from the circle", test.tolerance, test.transform, d)
+		}
+		if len(polygon) <= previous && test.transform.IsIdentity() {
+			t.Errorf("tolerance %g: %d vertices, want more than the %d of a larger one", test.tolerance, len(polygon), previous)
+		}
+		previous = len(polygon)
+	}
+}
+
+func TestPolygonsSubpaths(t *testing.T) {
+	var path gkit.Path
+	path.MoveTo(0, 0)
+	path.LineTo(10, 0)
+	path.LineTo(10, 10)
+	path.Close()
+	// After Close the path continues from the start of the closed subpath.
+	path.LineTo(0, 10)
+	path.LineTo(-10, 10)
+	// Subpaths of fewer than three points fill nothing.
+	path.MoveTo(20, 20)
+	path.LineTo(30, 20)
+
+	polygons := path.Polygons(gkit.Translation(1, 2), gkit.PathTolerance)
+	want := [][]gkit.PointF{
+		{{X: 1, Y: 2}, {X: 11, Y: 2}, {X: 11, Y: 12}},
+		{{X: 1, Y: 2}, {X: 1, Y: 12}, {X: -9, Y: 12}},
+	}
+	if len(polygons) != len(want) {
+		t.Fatalf("Polygons() = %v, want %v", polygons, want)
+	}
+	for i := range want {
+		if len(polygons[i]) != len(want[i]) {
+			t.Errorf("polygon %d = %v, want %v", i, polygons[i], want[i])
+			continue
+		}
+		for j := range want[i] {
+			if polygons[i][j] != want[i][j] {
+				t.Errorf("polygon %d = %v, want %v", i, polygons[i], want[i])
+				break
+			}
+		}
+	}
+}
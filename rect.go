@@ -78,3 +78,7 @@ func (r Rect) Offset(p Point) Rect {
 func (r Rect) RightBottom() Point {
 	return Point{r.X + r.Width, r.Y + r.Height}
 }
+
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X && p.Y >= r.Y && p.X < r.X+r.Width && p.Y < r.Y+r.Height
+}
@@ -0,0 +1,190 @@
+package gkit
+
+import (
+	"math"
+)
+
+// PointF, SizeF and RectF are signed fractional counterparts of Point, Size
+// and Rect. They can express positions left of or above the origin and don't
+// wrap around on subtraction. A RectF with a negative size is empty.
+type PointF struct {
+	X float32
+	Y float32
+}
+
+func (p PointF) Add(p2 PointF) PointF {
+	return PointF{p.X + p2.X, p.Y + p2.Y}
+}
+
+func (p PointF) Sub(p2 PointF) PointF {
+	return PointF{p.X - p2.X, p.Y - p2.Y}
+}
+
+func (p PointF) Scale(factor float32) PointF {
+	return PointF{p.X * factor, p.Y * factor}
+}
+
+// Point rounds p to the nearest Point, negative coordinates become 0.
+func (p PointF) Point() Point {
+	return Point{toUint32(p.X), toUint32(p.Y)}
+}
+
+func (p Point) PointF() PointF {
+	return PointF{float32(p.X), float32(p.Y)}
+}
+
+type SizeF struct {
+	Width  float32
+	Height float32
+}
+
+func (s SizeF) Empty() bool {
+	return s.Width <= 0 || s.Height <= 0
+}
+
+func (s SizeF) Scale(factor float32) SizeF {
+	return SizeF{s.Width * factor, s.Height * factor}
+}
+
+// Size rounds s to the nearest Size, negative sizes become 0.
+func (s SizeF) Size() Size {
+	return Size{toUint32(s.Width), toUint32(s.Height)}
+}
+
+func (s Size) SizeF() SizeF {
+	return SizeF{float32(s.Width), float32(s.Height)}
+}
+
+type RectF struct {
+	PointF
+	SizeF
+}
+
+func RectFromCorners(leftTop, rightBottom PointF) RectF {
+	return RectF{leftTop, SizeF{rightBottom.X - leftTop.X, rightBottom.Y - leftTop.Y}}
+}
+
+func (r RectF) RightBottom() PointF {
+	return PointF{r.X + r.Width, r.Y + r.Height}
+}
+
+func (r RectF) Contains(p PointF) bool {
+	return p.X >= r.X && p.Y >= r.Y && p.X < r.X+r.Width && p.Y < r.Y+r.Height
+}
+
+// Intersect returns the largest rect contained by both r and r2, the zero
+// RectF if they don't overlap.
+func (r RectF) Intersect(r2 RectF) RectF {
+	rb, rb2 := r.RightBottom(), r2.RightBottom()
+	result := RectFromCorners(
+		PointF{max32(r.X, r2.X), max32(r.Y, r2.Y)},
+		PointF{min32(rb.X, rb2.X), min32(rb.Y, rb2.Y)},
+	)
+	if result.Empty() {
+		return RectF{}
+	}
+	return result
+}
+
+// Union returns the smallest rect containing both r and r2. Empty rects are
+// ignored.
+func (r RectF) Union(r2 RectF) RectF {
+	if r.Empty() {
+		return r2
+	}
+	if r2.Empty() {
+		return r
+	}
+	rb, rb2 := r.RightBottom(), r2.RightBottom()
+	return RectFromCorners(
+		PointF{min32(r.X, r2.X), min32(r.Y, r2.Y)},
+		PointF{max32(rb.X, rb2.X), max32(rb.Y, rb2.Y)},
+	)
+}
+
+// Inset moves the sides of r inwards. Unlike Rect.Inset it doesn't limit
+// the insets, the result is empty if they exceed the size.
+func (r RectF) Inset(insets SideValues) RectF {
+	r.X += float32(insets.Left)
+	r.Y += float32(insets.Top)
+	r.Width -= float32(insets.Left + insets.Right)
+	r.Height -= float32(insets.Top + insets.Bottom)
+	return r
+}
+
+func (r RectF) Outset(insets SideValues) RectF {
+	r.X -= float32(insets.Left)
+	r.Y -= float32(insets.Top)
+	r.Width += float32(insets.Left + insets.Right)
+	r.Height += float32(insets.Top + insets.Bottom)
+	return r
+}
+
+// Expand moves the sides of r outwards by d, inwards if d is negative.
+func (r RectF) Expand(d float32) RectF {
+	return RectF{PointF{r.X - d, r.Y - d}, SizeF{r.Width + 2*d, r.Height + 2*d}}
+}
+
+func (r RectF) Translate(p PointF) RectF {
+	r.PointF = r.PointF.Add(p)
+	return r
+}
+
+// Scale scales both the position and the size of r.
+func (r RectF) Scale(factor float32) RectF {
+	return RectF{r.PointF.Scale(factor), r.SizeF.Scale(factor)}
+}
+
+// Rect returns the smallest Rect covering the part of r that has
+// non-negative coordinates.
+func (r RectF) Rect() Rect {
+	rb := r.RightBottom()
+	leftTop := Point{floorUint32(r.X), floorUint32(r.Y)}
+	rightBottom := Point{ceilUint32(rb.X), ceilUint32(rb.Y)}
+	if rightBottom.X < leftTop.X || rightBottom.Y < leftTop.Y {
+		return Rect{Point: leftTop}
+	}
+	return Rect{leftTop, Size{rightBottom.X - leftTop.X, rightBottom.Y - leftTop.Y}}
+}
+
+func (r Rect) RectF() RectF {
+	return RectF{r.Point.PointF(), r.Size.SizeF()}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func toUint32(v float32) uint32 {
+	return floorUint32(v + 0.5)
+}
+
+func floorUint32(v float32) uint32 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(math.Floor(float64(v)))
+}
+
+func ceilUint32(v float32) uint32 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(math.Ceil(float64(v)))
+}
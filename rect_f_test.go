@@ -0,0 +1,175 @@
+package gkit_test
+
+import (
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+func TestRectFIntersect(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a, b gkit.RectF
+		want gkit.RectF
+	}{
+		{"overlapping", rectF(0, 0, 10, 10), rectF(5, 2, 10, 4), rectF(5, 2, 5, 4)},
+		{"contained", rectF(0, 0, 10, 10), rectF(2, 3, 4, 5), rectF(2, 3, 4, 5)},
+		{"negative coordinates", rectF(-10, -10, 15, 15), rectF(-5, 0, 20, 20), rectF(-5, 0, 10, 5)},
+		{"disjoint", rectF(0, 0, 10, 10), rectF(20, 0, 10, 10), gkit.RectF{}},
+		// Rects touching at an edge don't overlap.
+		{"touching", rectF(0, 0, 10, 10), rectF(10, 0, 10, 10), gkit.RectF{}},
+		{"empty", rectF(0, 0, 10, 10), rectF(5, 5, 0, 0), gkit.RectF{}},
+	} {
+		if got := test.a.Intersect(test.b); got != test.want {
+			t.Errorf("%s: %v.Intersect(%v) = %v, want %v", test.name, test.a, test.b, got, test.want)
+		}
+		if got := test.b.Intersect(test.a); got != test.want {
+			t.Errorf("%s: %v.Intersect(%v) = %v, want %v", test.name, test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestRectFUnion(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a, b gkit.RectF
+		want gkit.RectF
+	}{
+		{"overlapping", rectF(0, 0, 10, 10), rectF(5, 2, 10, 4), rectF(0, 0, 15, 10)},
+		{"disjoint", rectF(-10, 0, 5, 5), rectF(10, 10, 5, 5), rectF(-10, 0, 25, 15)},
+		// Empty rects are the identity, wherever they are.
+		{"zero", rectF(2, 3, 4, 5), gkit.RectF{}, rectF(2, 3, 4, 5)},
+		{"empty far away", rectF(2, 3, 4, 5), rectF(100, 100, 0, 10), rectF(2, 3, 4, 5)},
+		{"negative size", rectF(2, 3, 4, 5), rectF(-100, -100, -1, 10), rectF(2, 3, 4, 5)},
+	} {
+		if got := test.a.Union(test.b); got != test.want {
+			t.Errorf("%s: %v.Union(%v) = %v, want %v", test.name, test.a, test.b, got, test.want)
+		}
+		if got := test.b.Union(test.a); got != test.want {
+			t.Errorf("%s: %v.Union(%v) = %v, want %v", test.name, test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestRectFContains(t *testing.T) {
+	r := rectF(-2, 1, 4, 3)
+	for _, test := range []struct {
+		point gkit.PointF
+		want  bool
+	}{
+		{gkit.PointF{X: 0, Y: 2}, true},
+		// The left and top edges are inside, the right and bottom ones
+		// aren't.
+		{gkit.PointF{X: -2, Y: 1}, true},
+		{gkit.PointF{X: 1.99, Y: 3.99}, true},
+		{gkit.PointF{X: 2, Y: 2}, false},
+		{gkit.PointF{X: 0, Y: 4}, false},
+		{gkit.PointF{X: -2.01, Y: 2}, false},
+		{gkit.PointF{X: 0, Y: 0.99}, false},
+	} {
+		if got := r.Contains(test.point); got != test.want {
+			t.Errorf("%v.Contains(%v) = %v, want %v", r, test.point, got, test.want)
+		}
+	}
+	if empty := rectF(0, 0, 0, 10); empty.Contains(gkit.PointF{}) {
+		t.Errorf("%v.Contains(%v) = true, want false", empty, gkit.PointF{})
+	}
+}
+
+func TestRectFInset(t *testing.T) {
+	r := rectF(10, 20, 30, 40)
+	for _, test := range []struct {
+		name   string
+		insets gkit.SideValues
+		want   gkit.RectF
+		empty  bool
+	}{
+		{"none", gkit.SideValues{}, r, false},
+		{"each side", gkit.SideValues{Left: 1, Right: 2, Top: 3, Bottom: 4}, rectF(11, 23, 27, 33), false},
+		{"all of it", gkit.SideValues{Left: 10, Right: 20, Top: 20, Bottom: 20}, rectF(20, 40, 0, 0), true},
+		// Insets larger than the size aren't limited, the size becomes
+		// negative.
+		{"more than the size", gkit.SideValues{Left: 20, Right: 20, Top: 1, Bottom: 1}, rectF(30, 21, -10, 38), true},
+	} {
+		got := r.Inset(test.insets)
+		if got != test.want {
+			t.Errorf("%s: Inset(%v) = %v, want %v", test.name, test.insets, got, test.want)
+		}
+		if got.Empty() != test.empty {
+			t.Errorf("%s: Inset(%v).Empty() = %v, want %v", test.name, test.insets, got.Empty(), test.empty)
+		}
+		if back := got.Outset(test.insets); back != r {
+			t.Errorf("%s: Inset(%v).Outset(%[2]v) = %v, want %v", test.name, test.insets, back, r)
+		}
+	}
+}
+
+func TestRectFTransform(t *testing.T) {
+	r := rectF(-2, 4, 6, 8)
+	if got, want := r.Scale(0.5), rectF(-1, 2, 3, 4); got != want {
+		t.Errorf("Scale(0.5) = %v, want %v", got, want)
+	}
+	if got, want := r.Scale(2), rectF(-4, 8, 12, 16); got != want {
+		t.Errorf("Scale(2) = %v, want %v", got, want)
+	}
+	if got, want := r.Translate(gkit.PointF{X: 3, Y: -5}), rectF(1, -1, 6, 8); got != want {
+		t.Errorf("Translate = %v, want %v", got, want)
+	}
+	if got, want := r.Expand(1), rectF(-3, 3, 8, 10); got != want {
+		t.Errorf("Expand(1) = %v, want %v", got, want)
+	}
+	if got, want := r.Expand(-4), rectF(2, 8, -2, 0); got != want || !got.Empty() {
+		t.Errorf("Expand(-4) = %v, want empty %v", got, want)
+	}
+}
+
+func TestRectFRect(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		r    gkit.RectF
+		want gkit.Rect
+	}{
+		{"whole", rectF(1, 2, 3, 4), rect(1, 2, 3, 4)},
+		// The result covers every pixel r touches.
+		{"fractional", rectF(1.5, 2.25, 3, 0.5), rect(1, 2, 4, 1)},
+		{"left of the origin", rectF(-2.5, 1, 4, 4), rect(0, 1, 2, 4)},
+		{"above the origin", rectF(1, -10, 4, 4), rect(1, 0, 4, 0)},
+		{"empty", rectF(3, 4, 0, 0), rect(3, 4, 0, 0)},
+		{"negative size", rectF(3, 4, -2, -2), rect(3, 4, 0, 0)},
+	} {
+		if got := test.r.Rect(); got != test.want {
+			t.Errorf("%s: %v.Rect() = %v, want %v", test.name, test.r, got, test.want)
+		}
+	}
+
+	// Rects convert to RectF and back unchanged.
+	for _, r := range []gkit.Rect{rect(0, 0, 0, 0), rect(1, 2, 3, 4), rect(100, 0, 1, 1000)} {
+		if got := r.RectF().Rect(); got != r {
+			t.Errorf("%v.RectF().Rect() = %v", r, got)
+		}
+	}
+	if got, want := rect(1, 2, 3, 4).RectF(), rectF(1, 2, 3, 4); got != want {
+		t.Errorf("Rect.RectF() = %v, want %v", got, want)
+	}
+}
+
+func TestPointFPoint(t *testing.T) {
+	for _, test := range []struct {
+		point gkit.PointF
+		want  gkit.Point
+	}{
+		{gkit.PointF{X: 1, Y: 2}, gkit.Point{X: 1, Y: 2}},
+		{gkit.PointF{X: 1.4, Y: 1.6}, gkit.Point{X: 1, Y: 2}},
+		{gkit.PointF{X: 0.5, Y: 2.5}, gkit.Point{X: 1, Y: 3}},
+		{gkit.PointF{X: -3, Y: -0.2}, gkit.Point{}},
+	} {
+		if got := test.point.Point(); got != test.want {
+			t.Errorf("%v.Point() = %v, want %v", test.point, got, test.want)
+		}
+		size := gkit.SizeF{Width: test.point.X, Height: test.point.Y}
+		want := gkit.Size{Width: test.want.X, Height: test.want.Y}
+		if got := size.Size(); got != want {
+			t.Errorf("%v.Size() = %v, want %v", size, got, want)
+		}
+	}
+}
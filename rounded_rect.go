@@ -0,0 +1,74 @@
+package gkit
+
+// CornerRadii are the radii of the corners of a rounded rect.
+type CornerRadii struct {
+	TopLeft     float32
+	TopRight    float32
+	BottomRight float32
+	BottomLeft  float32
+}
+
+func UniformRadii(radius float32) CornerRadii {
+	return CornerRadii{radius, radius, radius, radius}
+}
+
+func (c CornerRadii) IsZero() bool {
+	return c == CornerRadii{}
+}
+
+// Fit scales the radii down so that the corners of a rect of the given
+// size don't overlap, like CSS does. Negative radii become 0.
+func (c CornerRadii) Fit(size SizeF) CornerRadii {
+	c = CornerRadii{
+		max32(c.TopLeft, 0),
+		max32(c.TopRight, 0),
+		max32(c.BottomRight, 0),
+		max32(c.BottomLeft, 0),
+	}
+	factor := float32(1)
+	fit := func(side, a, b float32) {
+		if a+b > 0 {
+			factor = min32(factor, max32(side, 0)/(a+b))
+		}
+	}
+	fit(size.Width, c.TopLeft, c.TopRight)
+	fit(size.Width, c.BottomLeft, c.BottomRight)
+	fit(size.Height, c.TopLeft, c.BottomLeft)
+	fit(size.Height, c.TopRight, c.BottomRight)
+	if factor < 1 {
+		c = CornerRadii{c.TopLeft * factor, c.TopRight * factor, c.BottomRight * factor, c.BottomLeft * factor}
+	}
+	return c
+}
+
+// Expand grows the rounded corners by d, the radii of a rounded rect
+// expanded by d. Square corners stay square.
+func (c CornerRadii) Expand(d float32) CornerRadii {
+	expand := func(radius float32) float32 {
+		if radius <= 0 {
+			return 0
+		}
+		return max32(radius+d, 0)
+	}
+	return CornerRadii{expand(c.TopLeft), expand(c.TopRight), expand(c.BottomRight), expand(c.BottomLeft)}
+}
+
+// BoxShadow is a shadow cast by a rounded box, like the box-shadow of CSS.
+// The shadow is the box moved by Offset, expanded by Spread and blurred
+// with a Gaussian of standard deviation Blur/2. Drop shadows are only
+// drawn outside of the box, inset shadows only inside of it.
+type BoxShadow struct {
+	Color  Color
+	Offset PointF
+	Blur   float32
+	Spread float32
+	Inset  bool
+}
+
+// Bounds returns the rect the shadow of the box r is drawn in.
+func (s BoxShadow) Bounds(r RectF) RectF {
+	if s.Inset {
+		return r
+	}
+	return r.Translate(s.Offset).Expand(s.Spread + 1.5*max32(s.Blur, 0))
+}
@@ -0,0 +1,84 @@
+package soft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/alex-ac/gkit"
+)
+
+// blendTarget returns the image to draw r into with op for mode, and done
+// to call after drawing. Normal drawing inside a clip rect goes to the
+// target directly. The other modes, and clips that are polygons, draw into
+// a transparent layer that done cuts to clip and blends into the target.
+// Drawing is limited to r.
+func (p *painter) blendTarget(r image.Rectangle, clip gkit.Clip, mode gkit.BlendMode) (dst draw.Image, op draw.Op, done func()) {
+	r = r.Intersect(pixels(clip.Bounds)).Intersect(p.target.Bounds())
+	if mode == gkit.BlendNormal && clip.Polygon == nil {
+		return p.target.SubImage(r).(*image.RGBA), draw.Over, func() {}
+	}
+	layer := image.NewRGBA(r)
+	return layer, draw.Over, func() {
+		if clip.Polygon != nil {
+			cut(layer, clip)
+		}
+		if mode == gkit.BlendNormal {
+			draw.Draw(p.target, r, layer, r.Min, draw.Over)
+			return
+		}
+		blend(p.target, layer, mode)
+	}
+}
+
+// cut clears the pixels of img whose centers are outside clip, like the
+// GL backend, which cuts the geometry it draws, does.
+func cut(img *image.RGBA, clip gkit.Clip) {
+	r := img.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if !clip.Contains(gkit.PointF{X: float32(x) + 0.5, Y: float32(y) + 0.5}) {
+				i := img.PixOffset(x, y)
+				copy(img.Pix[i:i+4], []uint8{0, 0, 0, 0})
+			}
+		}
+	}
+}
+
+// blend combines the premultiplied colors of src with the ones of dst
+// under it, like the GL blend functions of mode do. Transparent pixels of
+// src, which the GL backend discards, leave dst as it is.
+func blend(dst, src *image.RGBA, mode gkit.BlendMode) {
+	r := src.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		s := src.Pix[src.PixOffset(r.Min.X, y):src.PixOffset(r.Max.X, y)]
+		d := dst.Pix[dst.PixOffset(r.Min.X, y):dst.PixOffset(r.Max.X, y)]
+		for i := 0; i < len(s); i += 4 {
+			sa := uint32(s[i+3])
+			if sa == 0 {
+				continue
+			}
+			for c := 0; c < 4; c++ {
+				sc, dc := uint32(s[i+c]), uint32(d[i+c])
+				var v uint32
+				switch {
+				case mode == gkit.BlendMultiply && c < 3:
+					v = (sc*dc + dc*(255-sa) + 127) / 255
+				case mode == gkit.BlendScreen && c < 3:
+					v = sc + dc - (sc*dc+127)/255
+				case mode == gkit.BlendMultiply, mode == gkit.BlendScreen:
+					v = sc + (dc*(255-sa)+127)/255
+				case mode == gkit.BlendAdditive:
+					v = sc + dc
+					if v > 255 {
+						v = 255
+					}
+				case mode == gkit.BlendCopy:
+					v = sc
+				case mode == gkit.BlendClear:
+					v = (dc*(255-sa) + 127) / 255
+				}
+				d[i+c] = uint8(v)
+			}
+		}
+	}
+}
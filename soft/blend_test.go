@@ -0,0 +1,92 @@
+package soft
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+// glBlend returns what the blend functions setBlendFunc of the GL backend
+// sets for mode make of the premultiplied colors src and dst, with
+// components in [0, 1]. The result is clamped like it is in an 8 bit
+// framebuffer.
+func glBlend(mode gkit.BlendMode, src, dst [4]float64) [4]float64 {
+	var out [4]float64
+	sa := src[3]
+	for c := range out {
+		// The source and destination factors.
+		var sf, df float64
+		switch mode {
+		case gkit.BlendMultiply:
+			// DST_COLOR, ONE_MINUS_SRC_ALPHA; alpha ONE,
+			// ONE_MINUS_SRC_ALPHA.
+			sf, df = dst[c], 1-sa
+			if c == 3 {
+				sf = 1
+			}
+		case gkit.BlendScreen:
+			// ONE, ONE_MINUS_SRC_COLOR; alpha ONE, ONE_MINUS_SRC_ALPHA.
+			sf, df = 1, 1-src[c]
+			if c == 3 {
+				df = 1 - sa
+			}
+		case gkit.BlendAdditive:
+			sf, df = 1, 1
+		case gkit.BlendCopy:
+			sf, df = 1, 0
+		case gkit.BlendClear:
+			sf, df = 0, 1-sa
+		default:
+			sf, df = 1, 1-sa
+		}
+		out[c] = math.Min(src[c]*sf+dst[c]*df, 1)
+	}
+	return out
+}
+
+func unit(c color.RGBA) [4]float64 {
+	return [4]float64{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255}
+}
+
+func fromUnit(v [4]float64) color.RGBA {
+	c := func(x float64) uint8 { return uint8(math.Round(x * 255)) }
+	return color.RGBA{c(v[0]), c(v[1]), c(v[2]), c(v[3])}
+}
+
+func TestBlend(t *testing.T) {
+	// Premultiplied colors of every kind of opacity.
+	colors := []color.RGBA{
+		{0, 0, 0, 0},
+		{0, 0, 0, 255},
+		{255, 255, 255, 255},
+		{200, 100, 50, 255},
+		{100, 50, 25, 128},
+		{30, 120, 60, 160},
+		{1, 2, 3, 4},
+		{255, 0, 128, 255},
+	}
+	for _, mode := range []gkit.BlendMode{
+		gkit.BlendMultiply, gkit.BlendScreen, gkit.BlendAdditive, gkit.BlendCopy, gkit.BlendClear,
+	} {
+		for _, s := range colors {
+			for _, d := range colors {
+				src := image.NewRGBA(image.Rect(0, 0, 1, 1))
+				dst := image.NewRGBA(image.Rect(0, 0, 1, 1))
+				src.SetRGBA(0, 0, s)
+				dst.SetRGBA(0, 0, d)
+				blend(dst, src, mode)
+				want := fromUnit(glBlend(mode, unit(s), unit(d)))
+				// Transparent pixels are discarded.
+				if s.A == 0 {
+					want = d
+				}
+				if got := dst.RGBAAt(0, 0); !closeColors(got, want) {
+					t.Errorf("blend(%v, %v) in mode %d = %v, want %v", d, s, mode, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package soft
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/alex-ac/gkit"
+)
+
+func (p *painter) DrawCompositedLayer(r gkit.Rect, l gkit.Layer, opacity float32, mode gkit.BlendMode) {
+	if l.NeedsRedraw() {
+		p.enableRedraw()
+	}
+	t := p.Transform()
+	p.drawComposited(l, t.Mul(gkit.Translation(float32(r.X), float32(r.Y))), p.clip.ClipTo(r.RectF(), t), opacity, mode)
+}
+
+// drawComposited draws l into an offscreen image covering clip and blends
+// it into the target. The layer shares the brush, font and blend mode
+// with the painter, like in the GL backend.
+func (p *painter) drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, opacity float32, mode gkit.BlendMode) {
+	bounds := pixels(clip.Bounds).Intersect(p.target.Bounds())
+	if bounds.Empty() || opacity <= 0 {
+		return
+	}
+	// The offscreen image keeps the coordinates of the target.
+	offscreen := &painter{
+		target:           image.NewRGBA(bounds),
+		size:             p.size,
+		currentBrush:     p.currentBrush,
+		currentBlendMode: p.currentBlendMode,
+		currentFont:      p.currentFont,
+		currentFontSize:  p.currentFontSize,
+		clip:             noClip,
+	}
+	painter := &painterProxy{
+		impl: offscreen,
+		base: base,
+		clip: clip,
+	}
+	l.Draw(painter)
+	l.PropagateDraw(painter)
+	if offscreen.doRedraw {
+		p.enableRedraw()
+	}
+	p.currentBrush = offscreen.currentBrush
+	p.currentBlendMode = offscreen.currentBlendMode
+	p.currentFont = offscreen.currentFont
+	p.currentFontSize = offscreen.currentFontSize
+
+	alpha := uint8(min32(opacity, 1)*255 + 0.5)
+	dst, op, done := p.blendTarget(bounds, clip, mode)
+	draw.DrawMask(dst, bounds, offscreen.target, bounds.Min, image.NewUniform(color.Alpha{alpha}), image.Point{}, op)
+	done()
+}
+
+func (p *painterProxy) DrawCompositedLayer(r gkit.Rect, l gkit.Layer, opacity float32, mode gkit.BlendMode) {
+	t := p.toRoot()
+	p.drawComposited(l, t.Mul(gkit.Translation(float32(r.X), float32(r.Y))), p.clip.ClipTo(r.RectF(), t), opacity, mode)
+}
+
+func (p *painterProxy) drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, opacity float32, mode gkit.BlendMode) {
+	p.impl.drawComposited(l, base, clip, opacity, mode)
+}
@@ -0,0 +1,56 @@
+package soft
+
+import (
+	"image"
+
+	"github.com/alex-ac/gkit"
+)
+
+type DrawingContext struct {
+	size  gkit.Size
+	frame *image.RGBA
+}
+
+var _ gkit.DrawingContext = &DrawingContext{}
+
+func NewDrawingContext(size gkit.Size) *DrawingContext {
+	return &DrawingContext{
+		size:  size,
+		frame: newFrame(size),
+	}
+}
+
+func newFrame(size gkit.Size) *image.RGBA {
+	return image.NewRGBA(image.Rectangle{
+		Max: image.Point{int(size.Width), int(size.Height)},
+	})
+}
+
+func (c *DrawingContext) Size() gkit.Size {
+	return c.size
+}
+
+func (c *DrawingContext) SetSize(size gkit.Size) {
+	c.size = size
+}
+
+func (c *DrawingContext) BeginPaint() gkit.Painter {
+	return &painter{
+		target: newFrame(c.size),
+		size:   c.size,
+		clip:   noClip,
+	}
+}
+
+func (c *DrawingContext) EndPaint(gkitPainter gkit.Painter) {
+	p := gkitPainter.(*painter)
+	if !p.doRedraw {
+		return
+	}
+	c.frame = p.target
+}
+
+// Image returns the last frame that was painted with redraw enabled.
+func (c *DrawingContext) Image() *image.RGBA {
+	return c.frame
+}
@@ -0,0 +1,660 @@
+package soft
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/alex-ac/gkit"
+)
+
+// paint draws a frame of the given size with draw and returns it.
+func paint(size gkit.Size, draw func(p gkit.Painter)) *image.RGBA {
+	c := NewDrawingContext(size)
+	p := c.BeginPaint()
+	p.(*painter).enableRedraw()
+	draw(p)
+	c.EndPaint(p)
+	return c.Image()
+}
+
+// over returns the premultiplied pixel of src covering coverage of a pixel
+// of color dst, blended in sRGB like the soft backend does.
+func over(dst, src gkit.Color, coverage float64) color.RGBA {
+	sa := float64(src.A()) / 255 * coverage
+	da := float64(dst.A()) / 255
+	mix := func(s, d uint8) uint8 {
+		return uint8(math.Round(float64(s)*sa + float64(d)*da*(1-sa)))
+	}
+	return color.RGBA{
+		R: mix(src.R(), dst.R()),
+		G: mix(src.G(), dst.G()),
+		B: mix(src.B(), dst.B()),
+		A: uint8(math.Round(255 * (sa + da*(1-sa)))),
+	}
+}
+
+type goldenPixel struct {
+	x, y int
+	want color.RGBA
+}
+
+func checkPixels(t *testing.T, img *image.RGBA, pixels []goldenPixel) {
+	t.Helper()
+	for _, p := range pixels {
+		if got := img.RGBAAt(p.x, p.y); !closeColors(got, p.want) {
+			t.Errorf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+		}
+	}
+}
+
+// closeColors allows for rounding differences of one step.
+func closeColors(a, b color.RGBA) bool {
+	close := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return d >= -1 && d <= 1
+	}
+	return close(a.R, b.R) && close(a.G, b.G) && close(a.B, b.B) && close(a.A, b.A)
+}
+
+func fillBackground(p gkit.Painter, size gkit.Size, c gkit.Color) {
+	p.SetColor(c)
+	p.DrawRect(gkit.Rect{Size: size})
+}
+
+func TestGoldenRectEdges(t *testing.T) {
+	size := gkit.Size{Width: 10, Height: 4}
+	blue := gkit.RGBA(0, 0, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, blue)
+		// Covers half of column 2 and a quarter of column 6.
+		var path gkit.Path
+		path.MoveTo(2.5, 0)
+		path.LineTo(6.25, 0)
+		path.LineTo(6.25, 2)
+		path.LineTo(2.5, 2)
+		path.Close()
+		p.SetColor(red)
+		p.FillPath(&path, gkit.NonZero)
+		// The same edges with the distance based coverage of shapes.
+		p.Translate(0.5, 0)
+		p.FillRoundedRect(gkit.Rect{Point: gkit.Point{X: 2, Y: 2}, Size: gkit.Size{Width: 4, Height: 2}}, gkit.CornerRadii{})
+	})
+	for _, y := range []int{1, 3} {
+		checkPixels(t, img, []goldenPixel{
+			{1, y, over(blue, red, 0)},
+			// Edges blend in sRGB: half red over blue is (128, 0, 128),
+			// not the (188, 0, 188) of linear light.
+			{2, y, over(blue, red, 0.5)},
+			{3, y, over(blue, red, 1)},
+			{5, y, over(blue, red, 1)},
+			{7, y, over(blue, red, 0)},
+		})
+	}
+	checkPixels(t, img, []goldenPixel{
+		{6, 1, over(blue, red, 0.25)},
+		{6, 3, over(blue, red, 0.5)},
+	})
+	if got := img.RGBAAt(2, 1); got.R < 126 || got.R > 129 || got.B < 126 || got.B > 129 {
+		t.Errorf("half covered edge pixel = %v, want about (128, 0, 128)", got)
+	}
+}
+
+func TestGoldenTranslucentRectIsPremultiplied(t *testing.T) {
+	size := gkit.Size{Width: 6, Height: 2}
+	red := gkit.RGBA(255, 0, 0, 128)
+	img := paint(size, func(p gkit.Painter) {
+		var path gkit.Path
+		path.MoveTo(1.5, 0)
+		path.LineTo(5, 0)
+		path.LineTo(5, 2)
+		path.LineTo(1.5, 2)
+		path.Close()
+		p.SetColor(red)
+		p.FillPath(&path, gkit.NonZero)
+	})
+	checkPixels(t, img, []goldenPixel{
+		{0, 0, color.RGBA{}},
+		{1, 0, color.RGBA{R: 64, A: 64}},
+		{2, 0, color.RGBA{R: 128, A: 128}},
+		{4, 1, color.RGBA{R: 128, A: 128}},
+		{5, 1, color.RGBA{}},
+	})
+}
+
+func TestGoldenImage(t *testing.T) {
+	size := gkit.Size{Width: 6, Height: 4}
+	gray := gkit.RGBA(100, 100, 100, 255)
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	colors := []gkit.Color{
+		gkit.RGBA(255, 0, 0, 255),
+		gkit.RGBA(0, 255, 0, 128),
+		gkit.RGBA(0, 0, 255, 0),
+		gkit.RGBA(255, 255, 255, 64),
+	}
+	for i, c := range colors {
+		src.Set(i%2, i/2, c)
+	}
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, gray)
+		p.DrawImage(gkit.Rect{Point: gkit.Point{X: 3, Y: 1}, Size: gkit.Size{Width: 2, Height: 2}}, src)
+	})
+	pixels := []goldenPixel{
+		{2, 1, over(gray, 0, 0)},
+		{5, 2, over(gray, 0, 0)},
+		{3, 0, over(gray, 0, 0)},
+		{4, 3, over(gray, 0, 0)},
+	}
+	for i, c := range colors {
+		pixels = append(pixels, goldenPixel{3 + i%2, 1 + i/2, over(gray, c, 1)})
+	}
+	checkPixels(t, img, pixels)
+}
+
+func TestGoldenText(t *testing.T) {
+	font, err := gkit.LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const fontSize = 16
+	const text = "gkit/W"
+	textSize := font.StringSize(fontSize, text)
+	size := gkit.Size{Width: textSize.Width + 4, Height: textSize.Height + 4}
+	origin := gkit.Point{X: 2, Y: 2}
+	background := gkit.RGBA(0, 0, 128, 255)
+	foreground := gkit.RGBA(255, 255, 0, 192)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, background)
+		p.SetColor(foreground)
+		p.SetFont(font)
+		p.SetFontSize(fontSize)
+		p.DrawText(origin, text)
+	})
+
+	mask := image.NewAlpha(img.Bounds())
+	font.DrawString(fontSize, text, origin, mask)
+	var edges, solid int
+	for y := 0; y < int(size.Height); y++ {
+		for x := 0; x < int(size.Width); x++ {
+			coverage := mask.AlphaAt(x, y).A
+			switch coverage {
+			case 0:
+			case 255:
+				solid++
+			default:
+				edges++
+			}
+			want := over(background, foreground, float64(coverage)/255)
+			if got := img.RGBAAt(x, y); !closeColors(got, want) {
+				t.Fatalf("pixel (%d, %d) with coverage %d = %v, want %v", x, y, coverage, got, want)
+			}
+		}
+	}
+	if edges == 0 || solid == 0 {
+		t.Errorf("text has %d anti-aliased and %d solid pixels, want both", edges, solid)
+	}
+}
+
+// pushDiamondClip clips to the square of 20x20 centered at (20, 20) and
+// rotated by 45°, a diamond whose corners are 10√2 from the center.
+func pushDiamondClip(p gkit.Painter) {
+	p.Save()
+	p.Translate(20, 20)
+	p.Rotate(math.Pi / 4)
+	p.Translate(-10, -10)
+	p.PushClip(gkit.Rect{Size: gkit.Size{Width: 20, Height: 20}})
+	p.Restore()
+}
+
+func TestGoldenRotatedClip(t *testing.T) {
+	size := gkit.Size{Width: 40, Height: 40}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	blue := gkit.RGBA(0, 0, 255, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		pushDiamondClip(p)
+		fillBackground(p, size, red)
+		// A nested clip cuts the diamond to the right half.
+		p.PushClip(gkit.Rect{Point: gkit.Point{X: 20, Y: 0}, Size: gkit.Size{Width: 20, Height: 40}})
+		fillBackground(p, size, blue)
+		p.PopClip()
+		p.PopClip()
+	})
+	checkPixels(t, img, []goldenPixel{
+		{15, 20, over(white, red, 1)},
+		{20, 20, over(white, blue, 1)},
+		{20, 8, over(white, blue, 1)},
+		{19, 33, over(white, red, 1)},
+		{20, 34, over(white, white, 1)},
+		{30, 20, over(white, blue, 1)},
+		// The corners of the bounding box of the diamond stay white.
+		{8, 8, over(white, white, 1)},
+		{31, 8, over(white, white, 1)},
+		{31, 31, over(white, white, 1)},
+		{8, 31, over(white, white, 1)},
+	})
+}
+
+func TestGoldenFillRules(t *testing.T) {
+	size := gkit.Size{Width: 16, Height: 16}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	// Squares going the same way, the inner one winds twice.
+	var path gkit.Path
+	for _, r := range []gkit.RectF{
+		{PointF: gkit.PointF{X: 2, Y: 2}, SizeF: gkit.SizeF{Width: 12, Height: 12}},
+		{PointF: gkit.PointF{X: 6, Y: 6}, SizeF: gkit.SizeF{Width: 4, Height: 4}},
+	} {
+		rb := r.RightBottom()
+		path.MoveTo(r.X, r.Y)
+		path.LineTo(rb.X, r.Y)
+		path.LineTo(rb.X, rb.Y)
+		path.LineTo(r.X, rb.Y)
+		path.Close()
+	}
+	for _, test := range []struct {
+		rule  gkit.FillRule
+		inner color.RGBA
+	}{
+		{gkit.NonZero, over(white, red, 1)},
+		{gkit.EvenOdd, over(white, red, 0)},
+	} {
+		img := paint(size, func(p gkit.Painter) {
+			fillBackground(p, size, white)
+			p.SetColor(red)
+			p.FillPath(&path, test.rule)
+		})
+		checkPixels(t, img, []goldenPixel{
+			{1, 1, over(white, red, 0)},
+			{2, 2, over(white, red, 1)},
+			{5, 8, over(white, red, 1)},
+			{6, 6, test.inner},
+			{8, 8, test.inner},
+			{9, 9, test.inner},
+			{10, 8, over(white, red, 1)},
+			{14, 14, over(white, red, 0)},
+		})
+	}
+}
+
+func TestGoldenDashedStroke(t *testing.T) {
+	size := gkit.Size{Width: 20, Height: 8}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		p.SetColor(red)
+		// Dashes from 1 to 5, 7 to 11, 13 to 17 and 19 to 20 in rows 1
+		// and 2.
+		var path gkit.Path
+		path.MoveTo(0, 2)
+		path.LineTo(20, 2)
+		p.StrokePath(&path, gkit.StrokeStyle{Width: 2, Dashes: []float32{4, 2}, DashOffset: -1})
+		// The odd pattern is dashes and gaps of 3 from 0 in rows 5 and 6.
+		path = gkit.Path{}
+		path.MoveTo(0, 6)
+		path.LineTo(20, 6)
+		p.StrokePath(&path, gkit.StrokeStyle{Width: 2, Dashes: []float32{3}})
+	})
+	for _, y := range []int{1, 2} {
+		for x, dashed := range []bool{
+			false, true, true, true, true, false, false, true, true, true,
+			true, false, false, true, true, true, true, false, false, true,
+		} {
+			want := over(white, red, 0)
+			if dashed {
+				want = over(white, red, 1)
+			}
+			checkPixels(t, img, []goldenPixel{{x, y, want}})
+		}
+	}
+	for _, y := range []int{5, 6} {
+		for x := 0; x < 18; x++ {
+			want := over(white, red, 0)
+			if x/3%2 == 0 {
+				want = over(white, red, 1)
+			}
+			checkPixels(t, img, []goldenPixel{{x, y, want}})
+		}
+	}
+	checkPixels(t, img, []goldenPixel{
+		{5, 0, over(white, red, 0)},
+		{5, 3, over(white, red, 0)},
+		{2, 4, over(white, red, 0)},
+		{2, 7, over(white, red, 0)},
+	})
+}
+
+func TestGoldenRotatedRect(t *testing.T) {
+	size := gkit.Size{Width: 40, Height: 40}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		// The diamond of pushDiamondClip, its top right edge is the line
+		// x - y = 10√2.
+		p.Translate(20, 20)
+		p.Rotate(math.Pi / 4)
+		p.Translate(-10, -10)
+		p.SetColor(red)
+		p.DrawRect(gkit.Rect{Size: gkit.Size{Width: 20, Height: 20}})
+	})
+	checkPixels(t, img, []goldenPixel{
+		{20, 20, over(white, red, 1)},
+		{20, 7, over(white, red, 1)},
+		{8, 8, over(white, red, 0)},
+		{31, 31, over(white, red, 0)},
+		// The edge crosses the four scanlines of the pixel at x - y = 14
+		// at 0.267, 0.517, 0.767 and 1.017, the first three of the next
+		// one left of it.
+		{27, 13, over(white, red, (0.267+0.517+0.767+1)/4)},
+		{30, 16, over(white, red, (0.267+0.517+0.767+1)/4)},
+		{28, 13, over(white, red, 0.017/4)},
+	})
+}
+
+func TestGoldenRotatedText(t *testing.T) {
+	font, err := gkit.LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const fontSize = 16
+	const text = "gkit/W"
+	textSize := font.StringSize(fontSize, text)
+	size := gkit.Size{Width: textSize.Height + 4, Height: textSize.Width + 4}
+	background := gkit.RGBA(0, 0, 128, 255)
+	foreground := gkit.RGBA(255, 255, 0, 192)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, background)
+		p.SetColor(foreground)
+		p.SetFont(font)
+		p.SetFontSize(fontSize)
+		// Turned by 90°, the text goes down from the right top corner.
+		p.Translate(float32(size.Width)-2, 2)
+		p.Rotate(math.Pi / 2)
+		p.DrawText(gkit.Point{}, text)
+	})
+
+	// The pixels of a quarter turn are the ones of the mask transposed.
+	// Glyphs reach out of the line box, so the mask has a margin.
+	mask := image.NewAlpha(image.Rect(-4, -4, int(textSize.Width)+4, int(textSize.Height)+4))
+	font.DrawString(fontSize, text, gkit.Point{}, mask)
+	var covered int
+	for y := 0; y < int(size.Height); y++ {
+		for x := 0; x < int(size.Width); x++ {
+			// (x, y) is (y - 2, width - 3 - x) of the mask.
+			coverage := mask.AlphaAt(y-2, int(size.Width)-3-x).A
+			if coverage != 0 {
+				covered++
+			}
+			want := over(background, foreground, float64(coverage)/255)
+			if got := img.RGBAAt(x, y); !closeColors(got, want) {
+				t.Fatalf("pixel (%d, %d) with coverage %d = %v, want %v", x, y, coverage, got, want)
+			}
+		}
+	}
+	if covered == 0 {
+		t.Errorf("rotated text covers no pixels")
+	}
+}
+
+func TestGoldenRoundedRect(t *testing.T) {
+	size := gkit.Size{Width: 24, Height: 24}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		p.SetColor(red)
+		p.FillRoundedRect(gkit.Rect{Point: gkit.Point{X: 2, Y: 2}, Size: gkit.Size{Width: 20, Height: 20}},
+			gkit.CornerRadii{TopLeft: 8, BottomRight: 4})
+	})
+	// Pixels are covered by 0.5 minus the distance of their centers from
+	// the outline.
+	checkPixels(t, img, []goldenPixel{
+		{12, 1, over(white, red, 0)},
+		{12, 2, over(white, red, 1)},
+		{12, 12, over(white, red, 1)},
+		// The top left corner is a circle of 8 around (10, 10), (4.5, 4.5)
+		// is 7.778 from it.
+		{3, 3, over(white, red, 0)},
+		{4, 4, over(white, red, 0.5+8-5.5*math.Sqrt2)},
+		{5, 5, over(white, red, 1)},
+		// The bottom right one a circle of 4 around (18, 18).
+		{20, 20, over(white, red, 0.5+4-2.5*math.Sqrt2)},
+		{21, 21, over(white, red, 0)},
+		// The others are square.
+		{21, 2, over(white, red, 1)},
+		{2, 21, over(white, red, 1)},
+		{22, 2, over(white, red, 0)},
+	})
+}
+
+func TestGoldenBorder(t *testing.T) {
+	size := gkit.Size{Width: 24, Height: 24}
+	white := gkit.RGBA(255, 255, 255, 255)
+	blue := gkit.RGBA(0, 0, 255, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		p.SetColor(blue)
+		p.DrawBorder(gkit.Rect{Point: gkit.Point{X: 2, Y: 2}, Size: gkit.Size{Width: 20, Height: 20}},
+			gkit.CornerRadii{TopLeft: 6}, 2.5)
+	})
+	checkPixels(t, img, []goldenPixel{
+		{12, 1, over(white, blue, 0)},
+		{12, 2, over(white, blue, 1)},
+		{12, 3, over(white, blue, 1)},
+		// The inner edge is at 4.5.
+		{12, 4, over(white, blue, 0.5)},
+		{12, 5, over(white, blue, 0)},
+		{12, 12, over(white, blue, 0)},
+		{21, 12, over(white, blue, 1)},
+		{19, 12, over(white, blue, 0.5)},
+		// The rounded corner is between circles of 6 and 3.5 around
+		// (8, 8). (3.5, 3.5) is 6.364 from it, (5.5, 5.5) 3.536.
+		{3, 3, over(white, blue, 0.5+6-4.5*math.Sqrt2)},
+		{4, 4, over(white, blue, 1)},
+		{5, 5, over(white, blue, 1-(0.5+3.5-2.5*math.Sqrt2))},
+		{6, 6, over(white, blue, 0)},
+		// The inner corners of square corners are square.
+		{4, 19, over(white, blue, 0.5)},
+		{5, 18, over(white, blue, 0)},
+	})
+}
+
+// gaussianEdge returns how much of a long straight edge blurred by a
+// Gaussian of sigma covers a point distance outside of it.
+func gaussianEdge(distance, sigma float64) float64 {
+	return 0.5 * math.Erfc(distance/(sigma*math.Sqrt2))
+}
+
+func TestGoldenBoxShadow(t *testing.T) {
+	size := gkit.Size{Width: 60, Height: 60}
+	white := gkit.RGBA(255, 255, 255, 255)
+	black := gkit.RGBA(0, 0, 0, 255)
+	box := gkit.Rect{Point: gkit.Point{X: 10, Y: 10}, Size: gkit.Size{Width: 40, Height: 40}}
+	for _, test := range []struct {
+		name   string
+		shadow gkit.BoxShadow
+		pixels []goldenPixel
+	}{
+		// The shadow of the box moved by (3, 4) and grown by 2 is drawn
+		// around the box, but not under it.
+		{"drop", gkit.BoxShadow{Color: black, Offset: gkit.PointF{X: 3, Y: 4}, Spread: 2}, []goldenPixel{
+			{30, 49, over(white, black, 0)},
+			{30, 50, over(white, black, 1)},
+			{30, 55, over(white, black, 1)},
+			{30, 56, over(white, black, 0)},
+			{54, 30, over(white, black, 1)},
+			{55, 30, over(white, black, 0)},
+			{11, 50, over(white, black, 1)},
+			{10, 50, over(white, black, 0)},
+			{30, 9, over(white, black, 0)},
+		}},
+		// Blurring by 8 is a Gaussian of sigma 4. It is exact along x,
+		// rows are sampled.
+		{"blurred drop", gkit.BoxShadow{Color: black, Blur: 8}, []goldenPixel{
+			{49, 30, over(white, black, 0)},
+			{50, 30, over(white, black, gaussianEdge(0.5, 4))},
+			{53, 30, over(white, black, gaussianEdge(3.5, 4))},
+			{57, 30, over(white, black, gaussianEdge(7.5, 4))},
+		}},
+		// The inset shadow is drawn inside the box, outside of the box
+		// moved by (3, 0) and shrunk by 2.
+		{"inset", gkit.BoxShadow{Color: black, Offset: gkit.PointF{X: 3}, Spread: 2, Inset: true}, []goldenPixel{
+			{9, 30, over(white, black, 0)},
+			{10, 30, over(white, black, 1)},
+			{14, 30, over(white, black, 1)},
+			{15, 30, over(white, black, 0)},
+			{49, 30, over(white, black, 0)},
+			{30, 11, over(white, black, 1)},
+			{30, 12, over(white, black, 0)},
+			{30, 48, over(white, black, 1)},
+			{30, 47, over(white, black, 0)},
+		}},
+		{"blurred inset", gkit.BoxShadow{Color: black, Blur: 8, Inset: true}, []goldenPixel{
+			{9, 30, over(white, black, 0)},
+			{10, 30, over(white, black, 1-gaussianEdge(-0.5, 4))},
+			{13, 30, over(white, black, 1-gaussianEdge(-3.5, 4))},
+			{17, 30, over(white, black, 1-gaussianEdge(-7.5, 4))},
+		}},
+	} {
+		img := paint(size, func(p gkit.Painter) {
+			fillBackground(p, size, white)
+			p.DrawBoxShadow(box, gkit.CornerRadii{}, test.shadow)
+		})
+		t.Run(test.name, func(t *testing.T) {
+			checkPixels(t, img, test.pixels)
+		})
+	}
+}
+
+// mix returns a straight color f of the way from a to b, which are opaque.
+func mix(a, b gkit.Color, f float64) gkit.Color {
+	c := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*f))
+	}
+	return gkit.RGBA(c(a.R(), b.R()), c(a.G(), b.G()), c(a.B(), b.B()), 255)
+}
+
+func TestGoldenLinearGradient(t *testing.T) {
+	size := gkit.Size{Width: 20, Height: 6}
+	white := gkit.RGBA(255, 255, 255, 255)
+	red := gkit.RGBA(255, 0, 0, 255)
+	blue := gkit.RGBA(0, 0, 255, 255)
+	img := paint(size, func(p gkit.Painter) {
+		fillBackground(p, size, white)
+		p.SetBrush(gkit.LinearGradient(gkit.PointF{X: 2}, gkit.PointF{X: 18},
+			gkit.GradientStop{Offset: 0, Color: red},
+			gkit.GradientStop{Offset: 1, Color: blue}))
+		p.DrawRect(gkit.Rect{Size: gkit.Size{Width: 20, Height: 2}})
+		// Gradients are given in the coordinates things are drawn in, so
+		// this one is the same as the first.
+		p.Save()
+		p.Scale(2, 2)
+		p.SetBrush(gkit.LinearGradient(gkit.PointF{X: 1}, gkit.PointF{X: 9},
+			gkit.GradientStop{Offset: 0, Color: red},
+			gkit.GradientStop{Offset: 1, Color: blue}))
+		p.DrawRect(gkit.Rect{Point: gkit.Point{Y: 1}, Size: gkit.Size{Width: 10, Height: 1}})
+		p.Restore()
+		// Colors are interpolated premultiplied, fading into transparency
+		// keeps the color.
+		p.SetBrush(gkit.LinearGradient(gkit.PointF{X: 2}, gkit.PointF{X: 18},
+			gkit.GradientStop{Offset: 0, Color: red},
+			gkit.GradientStop{Offset: 1, Color: gkit.RGBA(0, 0, 255, 0)}))
+		p.DrawRect(gkit.Rect{Point: gkit.Point{Y: 4}, Size: gkit.Size{Width: 20, Height: 2}})
+	})
+	for x := 0; x < 20; x++ {
+		// The offset of the center of the pixel, padded at the ends.
+		f := math.Min(math.Max((float64(x)+0.5-2)/16, 0), 1)
+		for _, y := range []int{0, 1, 2, 3} {
+			checkPixels(t, img, []goldenPixel{{x, y, over(white, mix(red, blue, f), 1)}})
+		}
+		checkPixels(t, img, []goldenPixel{{x, 5, over(white, red, 1-f)}})
+	}
+}
+
+func TestGoldenRadialGradient(t *testing.T) {
+	size := gkit.Size{Width: 20, Height: 20}
+	black := gkit.RGBA(0, 0, 0, 255)
+	yellow := gkit.RGBA(255, 255, 0, 255)
+	green := gkit.RGBA(0, 128, 0, 255)
+	img := paint(size, func(p gkit.Painter) {
+		p.SetBrush(gkit.RadialGradient(gkit.PointF{X: 10, Y: 10}, 8,
+			gkit.GradientStop{Offset: 0.25, Color: yellow},
+			gkit.GradientStop{Offset: 1, Color: green}))
+		p.DrawRect(gkit.Rect{Size: size})
+	})
+	for _, test := range []struct {
+		x, y int
+		want gkit.Color
+	}{
+		// Up to 2 from the center the gradient is padded with the first
+		// stop, from 8 on with the last one.
+		{10, 10, yellow},
+		{11, 9, yellow},
+		{10, 14, mix(yellow, green, (math.Hypot(0.5, 4.5)/8-0.25)/0.75)},
+		{14, 10, mix(yellow, green, (math.Hypot(4.5, 0.5)/8-0.25)/0.75)},
+		{5, 5, mix(yellow, green, (math.Hypot(4.5, 4.5)/8-0.25)/0.75)},
+		{1, 10, green},
+		{0, 0, green},
+	} {
+		checkPixels(t, img, []goldenPixel{{test.x, test.y, over(black, test.want, 1)}})
+	}
+}
+
+func TestGoldenBlendModes(t *testing.T) {
+	size := gkit.Size{Width: 8, Height: 4}
+	opaque := gkit.RGBA(200, 100, 50, 255)
+	translucent := gkit.RGBA(0, 60, 255, 128)
+	src := gkit.RGBA(100, 200, 150, 128)
+	premultiplied := func(c gkit.Color, coverage float64) [4]float64 {
+		a := float64(c.A()) / 255 * coverage
+		return [4]float64{float64(c.R()) / 255 * a, float64(c.G()) / 255 * a, float64(c.B()) / 255 * a, a}
+	}
+	// Covers columns 2 to 5 and half of column 6.
+	var path gkit.Path
+	path.MoveTo(2, 0)
+	path.LineTo(6.5, 0)
+	path.LineTo(6.5, 4)
+	path.LineTo(2, 4)
+	path.Close()
+	for _, test := range []struct {
+		name string
+		mode gkit.BlendMode
+	}{
+		{"normal", gkit.BlendNormal},
+		{"multiply", gkit.BlendMultiply},
+		{"screen", gkit.BlendScreen},
+		{"additive", gkit.BlendAdditive},
+		{"copy", gkit.BlendCopy},
+		{"clear", gkit.BlendClear},
+	} {
+		img := paint(size, func(p gkit.Painter) {
+			p.SetColor(opaque)
+			p.DrawRect(gkit.Rect{Size: gkit.Size{Width: 8, Height: 2}})
+			p.SetColor(translucent)
+			p.DrawRect(gkit.Rect{Point: gkit.Point{Y: 2}, Size: gkit.Size{Width: 8, Height: 2}})
+			p.SetBlendMode(test.mode)
+			p.SetColor(src)
+			p.FillPath(&path, gkit.NonZero)
+		})
+		var pixels []goldenPixel
+		for y, dst := range []gkit.Color{opaque, opaque, translucent, translucent} {
+			for x, coverage := range map[int]float64{1: 0, 3: 1, 5: 1, 6: 0.5, 7: 0} {
+				want := fromUnit(premultiplied(dst, 1))
+				if coverage > 0 {
+					want = fromUnit(glBlend(test.mode, premultiplied(src, coverage), premultiplied(dst, 1)))
+				}
+				pixels = append(pixels, goldenPixel{x, y, want})
+			}
+		}
+		t.Run(test.name, func(t *testing.T) {
+			checkPixels(t, img, pixels)
+		})
+	}
+}
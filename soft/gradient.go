@@ -0,0 +1,47 @@
+package soft
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/alex-ac/gkit"
+)
+
+// gradient is an endless image of a gradient brush. toDrawing maps the
+// centers of its pixels into the coordinates the brush is given in.
+type gradient struct {
+	brush     gkit.Brush
+	toDrawing gkit.Transform
+}
+
+func (g *gradient) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+func (g *gradient) Bounds() image.Rectangle {
+	return image.Rect(-1<<30, -1<<30, 1<<30, 1<<30)
+}
+
+func (g *gradient) At(x, y int) color.Color {
+	p := g.toDrawing.Apply(gkit.PointF{X: float32(x) + 0.5, Y: float32(y) + 0.5})
+	return g.brush.ColorAt(g.brush.Offset(p))
+}
+
+// source returns the image drawing with the current brush, whose pixels
+// toDrawing maps into the drawing coordinates.
+func (p *painter) source(toDrawing gkit.Transform) image.Image {
+	if p.currentBrush.IsSolid() {
+		return image.NewUniform(p.currentBrush.Color())
+	}
+	return &gradient{brush: p.currentBrush, toDrawing: toDrawing}
+}
+
+// fromRoot returns the inverse of t, which maps the drawing coordinates
+// into root ones.
+func fromRoot(t gkit.Transform) gkit.Transform {
+	inverse, ok := t.Invert()
+	if !ok {
+		return gkit.Identity()
+	}
+	return inverse
+}
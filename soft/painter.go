@@ -0,0 +1,308 @@
+package soft
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+
+	"github.com/alex-ac/gkit"
+)
+
+// The internal drawing methods take r or o in the coordinates of the layer
+// that draws, t mapping them into the coordinates of the root painter, and
+// a clip in root coordinates.
+type softPainterInternal interface {
+	setBrush(b gkit.Brush)
+	setBlendMode(mode gkit.BlendMode)
+	drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip)
+	setFont(font *gkit.Font)
+	setFontSize(size uint32)
+	drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, text string)
+	drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, image image.Image)
+	// fillPolygons takes polygons in root coordinates, t only maps the
+	// brush.
+	fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip)
+	// drawShape draws shadows in their color rather than the current one.
+	drawShape(s shape, t gkit.Transform, clip gkit.Clip)
+	// drawComposited takes base, mapping the layer into root
+	// coordinates, and clip already limited by the caller.
+	drawComposited(l gkit.Layer, base gkit.Transform, clip gkit.Clip, opacity float32, mode gkit.BlendMode)
+	enableRedraw()
+}
+
+// noClip is used as the clip of draw calls that are only limited by the
+// layers they are made in.
+var noClip = gkit.ClipRect(gkit.RectF{
+	PointF: gkit.PointF{X: -1 << 30, Y: -1 << 30},
+	SizeF:  gkit.SizeF{Width: 1 << 31, Height: 1 << 31},
+})
+
+// pixels returns the pixels whose centers are inside r.
+func pixels(r gkit.RectF) image.Rectangle {
+	round := func(v float32) int {
+		return int(math.Floor(float64(v) + 0.5))
+	}
+	rb := r.RightBottom()
+	return image.Rect(round(r.X), round(r.Y), round(rb.X), round(rb.Y))
+}
+
+// translation returns the offset of t if it only moves by whole pixels.
+func translation(t gkit.Transform) (image.Point, bool) {
+	ok := t.A == 1 && t.B == 0 && t.C == 0 && t.D == 1 &&
+		t.E == float32(math.Floor(float64(t.E))) && t.F == float32(math.Floor(float64(t.F)))
+	return image.Point{int(t.E), int(t.F)}, ok
+}
+
+func aff3(t gkit.Transform) f64.Aff3 {
+	return f64.Aff3{
+		float64(t.A), float64(t.C), float64(t.E),
+		float64(t.B), float64(t.D), float64(t.F),
+	}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type painter struct {
+	gkit.TransformStack
+
+	target *image.RGBA
+	size   gkit.Size
+
+	currentBrush     gkit.Brush
+	currentBlendMode gkit.BlendMode
+
+	currentFont     *gkit.Font
+	currentFontSize uint32
+
+	// clip limits everything drawn, clips holds the clips saved by
+	// PushClip.
+	clip  gkit.Clip
+	clips []gkit.Clip
+
+	doRedraw bool
+}
+
+var _ gkit.Painter = &painter{}
+var _ softPainterInternal = &painter{}
+
+func (p *painter) DrawLayer(r gkit.Rect, l gkit.Layer) {
+	t := p.Transform()
+	p.drawLayer(&painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(float32(r.X), float32(r.Y))),
+		clip: p.clip.ClipTo(r.RectF(), t),
+	}, l)
+}
+
+func (p *painter) DrawScrolledLayer(clip gkit.Rect, offset gkit.Point, l gkit.Layer) {
+	t := p.Transform()
+	p.drawLayer(&painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(-float32(offset.X), -float32(offset.Y))),
+		clip: p.clip.ClipTo(clip.RectF(), t),
+	}, l)
+}
+
+func (p *painter) drawLayer(painter *painterProxy, l gkit.Layer) {
+	if l.NeedsRedraw() {
+		p.enableRedraw()
+	}
+	l.Draw(painter)
+	l.PropagateDraw(painter)
+}
+
+func (p *painter) enableRedraw() {
+	p.doRedraw = true
+}
+
+func (p *painter) PushClip(r gkit.Rect) {
+	p.clips = append(p.clips, p.clip)
+	p.clip = p.clip.ClipTo(r.RectF(), p.Transform())
+}
+
+func (p *painter) PopClip() {
+	if n := len(p.clips); n > 0 {
+		p.clip = p.clips[n-1]
+		p.clips = p.clips[:n-1]
+	}
+}
+
+// touched returns the pixels of the target inside the bounds of clip that r
+// touches.
+// Unlike pixels, it includes partially covered ones.
+func (p *painter) touched(r gkit.RectF, clip gkit.Clip) image.Rectangle {
+	rb := r.RightBottom()
+	return image.Rect(
+		int(math.Floor(float64(r.X))), int(math.Floor(float64(r.Y))),
+		int(math.Ceil(float64(rb.X))), int(math.Ceil(float64(rb.Y))),
+	).Intersect(pixels(clip.Bounds)).Intersect(p.target.Bounds())
+}
+
+func (p *painter) SetColor(c gkit.Color) {
+	p.setBrush(gkit.SolidBrush(c))
+}
+
+func (p *painter) SetBrush(b gkit.Brush) {
+	p.setBrush(b)
+}
+
+func (p *painter) setBrush(b gkit.Brush) {
+	p.currentBrush = b
+}
+
+func (p *painter) SetBlendMode(mode gkit.BlendMode) {
+	p.setBlendMode(mode)
+}
+
+func (p *painter) setBlendMode(mode gkit.BlendMode) {
+	p.currentBlendMode = mode
+}
+
+func (p *painter) DrawRect(r gkit.Rect) {
+	p.drawRect(r.RectF(), p.Transform(), p.clip)
+}
+
+func (p *painter) drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip) {
+	if t.IsAxisAligned() {
+		rect := pixels(t.BoundingBox(r).Intersect(clip.Bounds))
+		dst, op, done := p.blendTarget(rect, clip, p.currentBlendMode)
+		draw.Draw(dst, rect, p.source(fromRoot(t)), rect.Min, op)
+		done()
+		return
+	}
+	// Rotated and skewed rects are polygons, so their edges are
+	// anti-aliased like the ones of paths.
+	rb := r.RightBottom()
+	p.fillPolygons([][]gkit.PointF{{
+		t.Apply(r.PointF),
+		t.Apply(gkit.PointF{X: rb.X, Y: r.Y}),
+		t.Apply(rb),
+		t.Apply(gkit.PointF{X: r.X, Y: rb.Y}),
+	}}, gkit.NonZero, t, clip)
+}
+
+func (p *painter) SetFont(font *gkit.Font) {
+	p.setFont(font)
+}
+
+func (p *painter) setFont(font *gkit.Font) {
+	p.currentFont = font
+}
+
+func (p *painter) SetFontSize(size uint32) {
+	p.setFontSize(size)
+}
+
+func (p *painter) setFontSize(size uint32) {
+	p.currentFontSize = size
+}
+
+func (p *painter) DrawText(o gkit.Point, text string) {
+	p.drawText(o.PointF(), p.Transform(), p.clip, text)
+}
+
+func (p *painter) drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, text string) {
+	if p.currentFont == nil {
+		return
+	}
+	// Glyphs may reach out of the line box, like descenders do, so the
+	// mask has a margin of the font size around it.
+	size := p.currentFont.StringSize(p.currentFontSize, text)
+	margin := int(p.currentFontSize)
+	mask := image.NewAlpha(image.Rect(-margin, -margin, int(size.Width)+margin, int(size.Height)+margin))
+	p.currentFont.DrawString(p.currentFontSize, text, gkit.Point{}, mask)
+	toDrawing := fromRoot(t)
+	t = t.Mul(gkit.Translation(o.X, o.Y))
+	if offset, ok := translation(t); ok {
+		visible := mask.Bounds().Add(offset).Intersect(pixels(clip.Bounds))
+		dst, op, done := p.blendTarget(visible, clip, p.currentBlendMode)
+		draw.DrawMask(dst, visible, p.source(toDrawing), visible.Min, mask, visible.Min.Sub(offset), op)
+		done()
+		return
+	}
+	// The source is sampled in the coordinates of the mask.
+	src := p.source(gkit.Translation(o.X, o.Y))
+	dst, op, done := p.blendTarget(pixels(clip.Bounds), clip, p.currentBlendMode)
+	xdraw.BiLinear.Transform(dst, aff3(t), src, mask.Bounds(), op, &xdraw.Options{
+		SrcMask: mask,
+	})
+	done()
+}
+
+func (p *painter) FillPath(path *gkit.Path, rule gkit.FillRule) {
+	t := p.Transform()
+	p.fillPolygons(path.Polygons(t, gkit.PathTolerance), rule, t, p.clip)
+}
+
+func (p *painter) StrokePath(path *gkit.Path, style gkit.StrokeStyle) {
+	t := p.Transform()
+	p.fillPolygons(path.Stroke(style, t, gkit.PathTolerance), gkit.NonZero, t, p.clip)
+}
+
+func (p *painter) fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip) {
+	var bounds gkit.RectF
+	for _, polygon := range polygons {
+		for _, point := range polygon {
+			bounds = bounds.Union(gkit.RectF{PointF: point, SizeF: gkit.SizeF{Width: 1, Height: 1}})
+		}
+	}
+	r := p.touched(bounds, clip)
+	if r.Empty() {
+		return
+	}
+	mask := rasterize(polygons, rule, r)
+	dst, op, done := p.blendTarget(r, clip, p.currentBlendMode)
+	draw.DrawMask(dst, r, p.source(fromRoot(t)), r.Min, mask, r.Min, op)
+	done()
+}
+
+func (p *painter) FillRoundedRect(r gkit.Rect, radii gkit.CornerRadii) {
+	p.drawShape(roundedRect(r, radii, 0), p.Transform(), p.clip)
+}
+
+func (p *painter) StrokeRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(strokedRoundedRect(r, radii, width), p.Transform(), p.clip)
+}
+
+func (p *painter) DrawBorder(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(roundedRect(r, radii, width), p.Transform(), p.clip)
+}
+
+func (p *painter) DrawBoxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) {
+	p.drawShape(boxShadow(r, radii, shadow), p.Transform(), p.clip)
+}
+
+func (p *painter) DrawImage(r gkit.Rect, img image.Image) {
+	p.drawImage(r.RectF(), p.Transform(), p.clip, img)
+}
+
+func (p *painter) drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, img image.Image) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return
+	}
+	// The image is stretched over r.
+	t = t.Mul(gkit.Translation(r.X, r.Y)).
+		Mul(gkit.Scaling(r.Width/float32(bounds.Dx()), r.Height/float32(bounds.Dy()))).
+		Mul(gkit.Translation(-float32(bounds.Min.X), -float32(bounds.Min.Y)))
+	// Transform only draws inside the destination bounds, so clipping is a
+	// sub-image of the target.
+	dst, op, done := p.blendTarget(pixels(clip.Bounds), clip, p.currentBlendMode)
+	xdraw.BiLinear.Transform(dst, aff3(t), img, bounds, op, nil)
+	done()
+}
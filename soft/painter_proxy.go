@@ -0,0 +1,162 @@
+package soft
+
+import (
+	"image"
+
+	"github.com/alex-ac/gkit"
+)
+
+type painterProxy struct {
+	gkit.TransformStack
+
+	impl softPainterInternal
+
+	// base maps the layer's coordinates into the coordinates of the root
+	// painter, clip is the visible part of the layer in root coordinates.
+	base gkit.Transform
+	clip gkit.Clip
+	// clips holds the clips saved by PushClip.
+	clips []gkit.Clip
+}
+
+var _ gkit.Painter = &painterProxy{}
+var _ softPainterInternal = &painterProxy{}
+
+// toRoot maps what the layer draws now into root coordinates.
+func (p *painterProxy) toRoot() gkit.Transform {
+	return p.base.Mul(p.Transform())
+}
+
+func (p *painterProxy) DrawRect(r gkit.Rect) {
+	p.drawRect(r.RectF(), p.toRoot(), noClip)
+}
+
+func (p *painterProxy) drawRect(r gkit.RectF, t gkit.Transform, clip gkit.Clip) {
+	p.impl.drawRect(r, t, clip.Intersect(p.clip))
+}
+
+func (p *painterProxy) DrawLayer(r gkit.Rect, l gkit.Layer) {
+	t := p.toRoot()
+	painter := &painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(float32(r.X), float32(r.Y))),
+		clip: p.clip.ClipTo(r.RectF(), t),
+	}
+
+	l.Draw(painter)
+	l.PropagateDraw(painter)
+}
+
+func (p *painterProxy) DrawScrolledLayer(clip gkit.Rect, offset gkit.Point, l gkit.Layer) {
+	t := p.toRoot()
+	painter := &painterProxy{
+		impl: p,
+		base: t.Mul(gkit.Translation(-float32(offset.X), -float32(offset.Y))),
+		clip: p.clip.ClipTo(clip.RectF(), t),
+	}
+
+	l.Draw(painter)
+	l.PropagateDraw(painter)
+}
+
+func (p *painterProxy) PushClip(r gkit.Rect) {
+	p.clips = append(p.clips, p.clip)
+	p.clip = p.clip.ClipTo(r.RectF(), p.toRoot())
+}
+
+func (p *painterProxy) PopClip() {
+	if n := len(p.clips); n > 0 {
+		p.clip = p.clips[n-1]
+		p.clips = p.clips[:n-1]
+	}
+}
+
+func (p *painterProxy) SetColor(c gkit.Color) {
+	p.setBrush(gkit.SolidBrush(c))
+}
+
+func (p *painterProxy) SetBrush(b gkit.Brush) {
+	p.setBrush(b)
+}
+
+func (p *painterProxy) setBrush(b gkit.Brush) {
+	p.impl.setBrush(b)
+}
+
+func (p *painterProxy) SetBlendMode(mode gkit.BlendMode) {
+	p.setBlendMode(mode)
+}
+
+func (p *painterProxy) setBlendMode(mode gkit.BlendMode) {
+	p.impl.setBlendMode(mode)
+}
+
+func (p *painterProxy) SetFont(f *gkit.Font) {
+	p.setFont(f)
+}
+
+func (p *painterProxy) setFont(f *gkit.Font) {
+	p.impl.setFont(f)
+}
+
+func (p *painterProxy) SetFontSize(size uint32) {
+	p.setFontSize(size)
+}
+
+func (p *painterProxy) setFontSize(size uint32) {
+	p.impl.setFontSize(size)
+}
+
+func (p *painterProxy) DrawText(o gkit.Point, text string) {
+	p.drawText(o.PointF(), p.toRoot(), noClip, text)
+}
+
+func (p *painterProxy) drawText(o gkit.PointF, t gkit.Transform, clip gkit.Clip, text string) {
+	p.impl.drawText(o, t, clip.Intersect(p.clip), text)
+}
+
+func (p *painterProxy) DrawImage(r gkit.Rect, image image.Image) {
+	p.drawImage(r.RectF(), p.toRoot(), noClip, image)
+}
+
+func (p *painterProxy) drawImage(r gkit.RectF, t gkit.Transform, clip gkit.Clip, image image.Image) {
+	p.impl.drawImage(r, t, clip.Intersect(p.clip), image)
+}
+
+func (p *painterProxy) FillPath(path *gkit.Path, rule gkit.FillRule) {
+	t := p.toRoot()
+	p.fillPolygons(path.Polygons(t, gkit.PathTolerance), rule, t, noClip)
+}
+
+func (p *painterProxy) StrokePath(path *gkit.Path, style gkit.StrokeStyle) {
+	t := p.toRoot()
+	p.fillPolygons(path.Stroke(style, t, gkit.PathTolerance), gkit.NonZero, t, noClip)
+}
+
+func (p *painterProxy) fillPolygons(polygons [][]gkit.PointF, rule gkit.FillRule, t gkit.Transform, clip gkit.Clip) {
+	p.impl.fillPolygons(polygons, rule, t, clip.Intersect(p.clip))
+}
+
+func (p *painterProxy) FillRoundedRect(r gkit.Rect, radii gkit.CornerRadii) {
+	p.drawShape(roundedRect(r, radii, 0), p.toRoot(), noClip)
+}
+
+func (p *painterProxy) StrokeRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(strokedRoundedRect(r, radii, width), p.toRoot(), noClip)
+}
+
+func (p *painterProxy) DrawBorder(r gkit.Rect, radii gkit.CornerRadii, width float32) {
+	p.drawShape(roundedRect(r, radii, width), p.toRoot(), noClip)
+}
+
+func (p *painterProxy) DrawBoxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) {
+	p.drawShape(boxShadow(r, radii, shadow), p.toRoot(), noClip)
+}
+
+func (p *painterProxy) drawShape(s shape, t gkit.Transform, clip gkit.Clip) {
+	p.impl.drawShape(s, t, clip.Intersect(p.clip))
+}
+
+func (p *painterProxy) enableRedraw() {
+	p.impl.enableRedraw()
+}
@@ -0,0 +1,108 @@
+package soft
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"github.com/alex-ac/gkit"
+)
+
+// subsamples is the number of scanlines sampled per pixel row.
+const subsamples = 4
+
+// edge is a non-horizontal polygon edge, top above bottom. winding is 1 if
+// the polygon runs downwards along it and -1 otherwise.
+type edge struct {
+	top, bottom gkit.PointF
+	winding     int
+}
+
+type crossing struct {
+	x       float32
+	winding int
+}
+
+// rasterize returns the coverage of the area rule fills inside polygons,
+// limited to bounds. Every pixel row is sampled by a few scanlines, each
+// of which covers the spans between its crossings with the edges
+// exactly.
+func rasterize(polygons [][]gkit.PointF, rule gkit.FillRule, bounds image.Rectangle) *image.Alpha {
+	var edges []edge
+	for _, polygon := range polygons {
+		for i, a := range polygon {
+			b := polygon[(i+1)%len(polygon)]
+			switch {
+			case a.Y < b.Y:
+				edges = append(edges, edge{a, b, 1})
+			case a.Y > b.Y:
+				edges = append(edges, edge{b, a, -1})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].top.Y < edges[j].top.Y })
+
+	mask := image.NewAlpha(bounds)
+	coverage := make([]float32, bounds.Dx())
+	var active []edge
+	var crossings []crossing
+	next := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for i := range coverage {
+			coverage[i] = 0
+		}
+		for s := 0; s < subsamples; s++ {
+			sy := float32(y) + (float32(s)+0.5)/subsamples
+			kept := active[:0]
+			for _, e := range active {
+				if e.bottom.Y > sy {
+					kept = append(kept, e)
+				}
+			}
+			active = kept
+			for ; next < len(edges) && edges[next].top.Y <= sy; next++ {
+				if edges[next].bottom.Y > sy {
+					active = append(active, edges[next])
+				}
+			}
+
+			crossings = crossings[:0]
+			for _, e := range active {
+				x := e.top.X + (sy-e.top.Y)*(e.bottom.X-e.top.X)/(e.bottom.Y-e.top.Y)
+				crossings = append(crossings, crossing{x, e.winding})
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			winding := 0
+			for i := 0; i+1 < len(crossings); i++ {
+				c := crossings[i]
+				winding += c.winding
+				inside := winding != 0
+				if rule == gkit.EvenOdd {
+					inside = winding%2 != 0
+				}
+				if inside {
+					cover(coverage, c.x-float32(bounds.Min.X), crossings[i+1].x-float32(bounds.Min.X))
+				}
+			}
+		}
+		row := mask.Pix[(y-bounds.Min.Y)*mask.Stride:]
+		for i, c := range coverage {
+			row[i] = uint8(math.Min(float64(c), 1)*255 + 0.5)
+		}
+	}
+	return mask
+}
+
+// cover adds the span from x0 to x1 of one scanline to the coverage of
+// the pixels it overlaps.
+func cover(coverage []float32, x0, x1 float32) {
+	x0 = float32(math.Max(float64(x0), 0))
+	x1 = float32(math.Min(float64(x1), float64(len(coverage))))
+	for x0 < x1 {
+		pixel := math.Floor(float64(x0))
+		end := float32(math.Min(pixel+1, float64(x1)))
+		coverage[int(pixel)] += (end - x0) / subsamples
+		x0 = end
+	}
+}
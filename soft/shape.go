@@ -0,0 +1,170 @@
+package soft
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/alex-ac/gkit"
+)
+
+type shapeKind uint8
+
+const (
+	shapeRoundedRect shapeKind = iota
+	shapeShadow
+)
+
+// shape is a rounded rect, filled or stroked border wide inside of rect,
+// or the shadow of it. Its coverage is computed from signed distances
+// like in the GL fragment shader.
+type shape struct {
+	kind   shapeKind
+	rect   gkit.RectF
+	radii  gkit.CornerRadii
+	border float32
+	shadow gkit.BoxShadow
+}
+
+func roundedRect(r gkit.Rect, radii gkit.CornerRadii, border float32) shape {
+	return shape{kind: shapeRoundedRect, rect: r.RectF(), radii: radii.Fit(r.SizeF()), border: border}
+}
+
+// strokedRoundedRect strokes the outline of r centered on it.
+func strokedRoundedRect(r gkit.Rect, radii gkit.CornerRadii, width float32) shape {
+	s := roundedRect(r, radii, width)
+	s.rect = s.rect.Expand(width / 2)
+	s.radii = s.radii.Expand(width / 2)
+	return s
+}
+
+func boxShadow(r gkit.Rect, radii gkit.CornerRadii, shadow gkit.BoxShadow) shape {
+	return shape{kind: shapeShadow, rect: r.RectF(), radii: radii.Fit(r.SizeF()), shadow: shadow}
+}
+
+func (s shape) bounds() gkit.RectF {
+	if s.kind == shapeShadow {
+		return s.shadow.Bounds(s.rect)
+	}
+	return s.rect
+}
+
+// coverage returns how much of the pixel of the given size around p, in
+// the coordinates of the shape, the shape covers.
+func (s shape) coverage(p gkit.PointF, pixel float32) float32 {
+	center := s.rect.PointF.Add(gkit.PointF{X: s.rect.Width / 2, Y: s.rect.Height / 2})
+	p = p.Sub(center)
+	half := gkit.PointF{X: s.rect.Width / 2, Y: s.rect.Height / 2}
+	box := edgeCoverage(roundedRectDistance(p, half, s.radii), pixel)
+	if s.kind == shapeRoundedRect {
+		if s.border > 0 {
+			inner := half.Sub(gkit.PointF{X: s.border, Y: s.border})
+			box *= 1 - edgeCoverage(roundedRectDistance(p, inner, s.radii.Expand(-s.border)), pixel)
+		}
+		return box
+	}
+
+	spread := s.shadow.Spread
+	if s.shadow.Inset {
+		spread = -spread
+	}
+	shadowHalf := gkit.PointF{X: max32(half.X+spread, 0), Y: max32(half.Y+spread, 0)}
+	shadowRadii := s.radii.Expand(spread)
+	q := p.Sub(s.shadow.Offset)
+	var shadow float32
+	if sigma := s.shadow.Blur / 2; sigma > 0 {
+		shadow = blurredRoundedRect(q, shadowHalf, shadowRadii, sigma)
+	} else {
+		shadow = edgeCoverage(roundedRectDistance(q, shadowHalf, shadowRadii), pixel)
+	}
+	if s.shadow.Inset {
+		return box * (1 - shadow)
+	}
+	return shadow * (1 - box)
+}
+
+func cornerRadius(p gkit.PointF, radii gkit.CornerRadii) float32 {
+	switch {
+	case p.X > 0 && p.Y > 0:
+		return radii.BottomRight
+	case p.X > 0:
+		return radii.TopRight
+	case p.Y > 0:
+		return radii.BottomLeft
+	default:
+		return radii.TopLeft
+	}
+}
+
+// roundedRectDistance returns the signed distance from p to the rounded
+// rect with the given half size centered at the origin, negative inside.
+func roundedRectDistance(p, half gkit.PointF, radii gkit.CornerRadii) float32 {
+	r := cornerRadius(p, radii)
+	qx := float32(math.Abs(float64(p.X))) - half.X + r
+	qy := float32(math.Abs(float64(p.Y))) - half.Y + r
+	outside := math.Hypot(float64(max32(qx, 0)), float64(max32(qy, 0)))
+	inside := math.Min(math.Max(float64(qx), float64(qy)), 0)
+	return float32(outside+inside) - r
+}
+
+// edgeCoverage returns the coverage of a pixel whose center is distance
+// away from an edge.
+func edgeCoverage(distance, pixel float32) float32 {
+	return float32(math.Max(0, math.Min(1, 0.5-float64(distance/pixel))))
+}
+
+// blurredRoundedRect returns the coverage of the rounded rect blurred by a
+// Gaussian at p. The Gaussian is integrated exactly along x and sampled
+// along y, with the radius of the corner p is nearest.
+func blurredRoundedRect(p, half gkit.PointF, radii gkit.CornerRadii, sigma float32) float32 {
+	corner := math.Min(float64(cornerRadius(p, radii)), math.Min(float64(half.X), float64(half.Y)))
+	s := float64(sigma)
+	// Only the rows within 3 sigma contribute.
+	low, high := float64(p.Y-half.Y), float64(p.Y+half.Y)
+	start := math.Min(math.Max(-3*s, low), high)
+	end := math.Min(math.Max(3*s, low), high)
+	const samples = 4
+	step := (end - start) / samples
+	var value float64
+	y := start + step/2
+	for i := 0; i < samples; i++ {
+		// The row of the rect at p.Y - y, blurred along x.
+		row := float64(p.Y) - y
+		delta := math.Min(float64(half.Y)-corner-math.Abs(row), 0)
+		curved := float64(half.X) - corner + math.Sqrt(math.Max(0, corner*corner-delta*delta))
+		x := float64(p.X)
+		integral := 0.5*math.Erf((x+curved)/(math.Sqrt2*s)) - 0.5*math.Erf((x-curved)/(math.Sqrt2*s))
+		gaussian := math.Exp(-y*y/(2*s*s)) / (math.Sqrt(2*math.Pi) * s)
+		value += integral * gaussian * step
+		y += step
+	}
+	return float32(value)
+}
+
+func (p *painter) drawShape(s shape, t gkit.Transform, clip gkit.Clip) {
+	inverse, ok := t.Invert()
+	if !ok {
+		return
+	}
+	// Coverage fades out over a pixel, which is 1/scale long in the
+	// coordinates of the shape.
+	pixel := 1 / t.Scale()
+	r := p.touched(t.BoundingBox(s.bounds().Expand(pixel)), clip)
+	if r.Empty() {
+		return
+	}
+	mask := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			local := inverse.Apply(gkit.PointF{X: float32(x) + 0.5, Y: float32(y) + 0.5})
+			mask.Pix[mask.PixOffset(x, y)] = uint8(s.coverage(local, pixel)*255 + 0.5)
+		}
+	}
+	src := p.source(inverse)
+	if s.kind == shapeShadow {
+		src = image.NewUniform(s.shadow.Color)
+	}
+	dst, op, done := p.blendTarget(r, clip, p.currentBlendMode)
+	draw.DrawMask(dst, r, src, r.Min, mask, r.Min, op)
+	done()
+}
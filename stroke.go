@@ -0,0 +1,278 @@
+package gkit
+
+import (
+	"math"
+)
+
+type LineJoin uint8
+
+const (
+	JoinMiter LineJoin = iota
+	JoinRound
+	JoinBevel
+)
+
+type LineCap uint8
+
+const (
+	CapButt LineCap = iota
+	CapRound
+	CapSquare
+)
+
+const defaultMiterLimit = 4
+
+// StrokeStyle describes the lines Painter.StrokePath draws. Zero Width
+// strokes lines one unit wide, zero MiterLimit means 4, the default of SVG.
+// Dashes alternate the lengths of dashes and gaps, starting DashOffset
+// into the pattern; no dashes draw a solid line.
+type StrokeStyle struct {
+	Width      float32
+	Join       LineJoin
+	Cap        LineCap
+	MiterLimit float32
+	Dashes     []float32
+	DashOffset float32
+}
+
+// Stroke returns the outline of the path stroked with style as polygons
+// mapped by t, to be filled with the NonZero rule. The stroke is computed
+// before mapping, so scaling t scales its width too.
+func (p *Path) Stroke(style StrokeStyle, t Transform, tolerance float32) [][]PointF {
+	scale := t.Scale()
+	if scale == 0 {
+		return nil
+	}
+	tolerance /= scale
+	lines := p.flatten(Identity(), tolerance)
+	if len(style.Dashes) > 0 {
+		lines = dash(lines, style.Dashes, style.DashOffset)
+	}
+	var polygons [][]PointF
+	for _, line := range lines {
+		polygons = strokePolyline(polygons, line, style, tolerance)
+	}
+	for _, polygon := range polygons {
+		for i, point := range polygon {
+			polygon[i] = t.Apply(point)
+		}
+	}
+	return polygons
+}
+
+// dash splits lines into the dashes of pattern. The pattern starts anew on
+// every subpath.
+func dash(lines []polyline, pattern []float32, offset float32) []polyline {
+	if len(pattern)%2 == 1 {
+		pattern = append(pattern[:len(pattern):len(pattern)], pattern...)
+	}
+	var total float32
+	for _, d := range pattern {
+		if d < 0 {
+			return lines
+		}
+		total += d
+	}
+	if total <= 0 {
+		return lines
+	}
+
+	var dashes []polyline
+	for _, line := range lines {
+		points := line.points
+		if line.closed && len(points) > 1 {
+			points = append(points[:len(points):len(points)], points[0])
+		}
+
+		i, left := 0, pattern[0]
+		skip := float32(math.Mod(float64(offset), float64(total)))
+		if skip < 0 {
+			skip += total
+		}
+		for skip > 0 {
+			if skip < left {
+				left -= skip
+				break
+			}
+			skip -= left
+			i = (i + 1) % len(pattern)
+			left = pattern[i]
+		}
+
+		var current []PointF
+		if i%2 == 0 {
+			current = []PointF{points[0]}
+		}
+		for j := 0; j+1 < len(points); j++ {
+			a, b := points[j], points[j+1]
+			length := a.distance(b)
+			var position float32
+			for length-position > left {
+				position += left
+				point := lerp(a, b, position/length)
+				if i%2 == 0 {
+					dashes = append(dashes, polyline{points: append(current, point)})
+					current = nil
+				} else {
+					current = []PointF{point}
+				}
+				i = (i + 1) % len(pattern)
+				left = pattern[i]
+			}
+			left -= length - position
+			if i%2 == 0 {
+				current = append(current, b)
+			}
+		}
+		if i%2 == 0 && len(current) > 1 {
+			dashes = append(dashes, polyline{points: current})
+		}
+	}
+	return dashes
+}
+
+// strokePolyline appends the polygons covering the stroke of line: a quad
+// per segment, joins and caps. All of them wind the same way, so that
+// filling them with the NonZero rule draws their union.
+func strokePolyline(polygons [][]PointF, line polyline, style StrokeStyle, tolerance float32) [][]PointF {
+	halfWidth := style.Width / 2
+	if halfWidth <= 0 {
+		halfWidth = 0.5
+	}
+	miterLimit := style.MiterLimit
+	if miterLimit <= 0 {
+		miterLimit = defaultMiterLimit
+	}
+
+	points := make([]PointF, 0, len(line.points))
+	for _, point := range line.points {
+		if len(points) == 0 || points[len(points)-1] != point {
+			points = append(points, point)
+		}
+	}
+	closed := line.closed && len(points) > 2
+	if closed && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+
+	add := func(polygon ...PointF) {
+		if area(polygon) < 0 {
+			for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+				polygon[i], polygon[j] = polygon[j], polygon[i]
+			}
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	if len(points) == 1 {
+		// Only caps make a single point visible.
+		switch style.Cap {
+		case CapRound:
+			add(circle(points[0], halfWidth, tolerance)...)
+		case CapSquare:
+			p := points[0]
+			add(PointF{p.X - halfWidth, p.Y - halfWidth}, PointF{p.X + halfWidth, p.Y - halfWidth},
+				PointF{p.X + halfWidth, p.Y + halfWidth}, PointF{p.X - halfWidth, p.Y + halfWidth})
+		}
+		return polygons
+	}
+
+	count := len(points) - 1
+	if closed {
+		count = len(points)
+	}
+	direction := func(i int) PointF {
+		a, b := points[i], points[(i+1)%len(points)]
+		length := a.distance(b)
+		return PointF{(b.X - a.X) / length, (b.Y - a.Y) / length}
+	}
+	normal := func(d PointF) PointF {
+		return PointF{-d.Y * halfWidth, d.X * halfWidth}
+	}
+
+	for i := 0; i < count; i++ {
+		a, b := points[i], points[(i+1)%len(points)]
+		d := direction(i)
+		if !closed && style.Cap == CapSquare {
+			extension := d.Scale(halfWidth)
+			if i == 0 {
+				a = a.Sub(extension)
+			}
+			if i == count-1 {
+				b = b.Add(extension)
+			}
+		}
+		n := normal(d)
+		add(a.Add(n), b.Add(n), b.Sub(n), a.Sub(n))
+	}
+
+	for i := 0; i < len(points); i++ {
+		if !closed && (i == 0 || i == len(points)-1) {
+			continue
+		}
+		in, out := direction((i-1+len(points))%len(points)), direction(i)
+		p := points[i]
+		cross := in.X*out.Y - in.Y*out.X
+		if cross == 0 && in.X*out.X+in.Y*out.Y > 0 {
+			continue
+		}
+		if style.Join == JoinRound {
+			add(circle(p, halfWidth, tolerance)...)
+			continue
+		}
+		// The join fills the gap on the outer side of the turn.
+		side := float32(1)
+		if cross > 0 {
+			side = -1
+		}
+		n0, n1 := normal(in).Scale(side), normal(out).Scale(side)
+		mid := n0.Add(n1)
+		midLength := mid.distance(PointF{})
+		if style.Join == JoinMiter && midLength > 0 {
+			// The miter is 1/cos(θ/2) half widths long, θ being the angle
+			// between the normals.
+			cosHalf := midLength / 2 / halfWidth
+			if 1/cosHalf <= miterLimit {
+				miter := mid.Scale(halfWidth / cosHalf / midLength)
+				add(p, p.Add(n0), p.Add(miter), p.Add(n1))
+				continue
+			}
+		}
+		add(p, p.Add(n0), p.Add(n1))
+	}
+
+	if !closed && style.Cap == CapRound {
+		add(circle(points[0], halfWidth, tolerance)...)
+		add(circle(points[len(points)-1], halfWidth, tolerance)...)
+	}
+	return polygons
+}
+
+// area returns the signed area of polygon, positive for clockwise polygons
+// in y-down coordinates.
+func area(polygon []PointF) float32 {
+	var sum float32
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum / 2
+}
+
+// circle returns a polygon approximating the circle within tolerance.
+func circle(center PointF, radius, tolerance float32) []PointF {
+	step := math.Pi / 2
+	if tolerance < radius {
+		step = 2 * math.Acos(float64(1-tolerance/radius))
+	}
+	n := int(math.Ceil(2 * math.Pi / step))
+	if n < 8 {
+		n = 8
+	}
+	polygon := make([]PointF, n)
+	for i := range polygon {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		polygon[i] = PointF{center.X + radius*float32(cos), center.Y + radius*float32(sin)}
+	}
+	return polygon
+}
@@ -0,0 +1,176 @@
+package gkit_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/alex-ac/gkit"
+)
+
+// covers reports whether filling polygons with the NonZero rule covers p.
+func covers(polygons [][]gkit.PointF, p gkit.PointF) bool {
+	winding := 0
+	for _, polygon := range polygons {
+		for i, a := range polygon {
+			b := polygon[(i+1)%len(polygon)]
+			side := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+			switch {
+			case a.Y <= p.Y && b.Y > p.Y && side > 0:
+				winding++
+			case a.Y > p.Y && b.Y <= p.Y && side < 0:
+				winding--
+			}
+		}
+	}
+	return winding != 0
+}
+
+func polyline(points ...gkit.PointF) *gkit.Path {
+	var path gkit.Path
+	path.MoveTo(points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		path.LineTo(p.X, p.Y)
+	}
+	return &path
+}
+
+func TestStroke(t *testing.T) {
+	line := polyline(gkit.PointF{X: 0, Y: 0}, gkit.PointF{X: 10, Y: 0})
+	// A right turn at (10, 0), the outer corner of the stroke is (11, -1).
+	corner := polyline(gkit.PointF{X: 0, Y: 0}, gkit.PointF{X: 10, Y: 0}, gkit.PointF{X: 10, Y: 10})
+	// A sharp turn at (10, 0), its miter is about 10 half widths long and
+	// points to +x.
+	sharp := polyline(gkit.PointF{X: 0, Y: 0}, gkit.PointF{X: 10, Y: 0}, gkit.PointF{X: 0, Y: 2})
+	var closed gkit.Path
+	closed.MoveTo(0, 0)
+	closed.LineTo(10, 0)
+	closed.LineTo(10, 10)
+	closed.LineTo(0, 10)
+	closed.Close()
+
+	for _, test := range []struct {
+		name    string
+		path    *gkit.Path
+		style   gkit.StrokeStyle
+		inside  []gkit.PointF
+		outside []gkit.PointF
+	}{
+		{"butt cap", line, gkit.StrokeStyle{Width: 2},
+			[]gkit.PointF{{X: 5, Y: 0.9}, {X: 5, Y: -0.9}, {X: 0.1, Y: 0}, {X: 9.9, Y: 0}},
+			[]gkit.PointF{{X: 5, Y: 1.1}, {X: -0.1, Y: 0}, {X: 10.1, Y: 0}}},
+		{"square cap", line, gkit.StrokeStyle{Width: 2, Cap: gkit.CapSquare},
+			[]gkit.PointF{{X: -0.9, Y: 0.9}, {X: 10.9, Y: -0.9}},
+			[]gkit.PointF{{X: -1.1, Y: 0}, {X: 11.1, Y: 0}}},
+		{"round cap", line, gkit.StrokeStyle{Width: 2, Cap: gkit.CapRound},
+			[]gkit.PointF{{X: -0.9, Y: 0}, {X: 10.6, Y: 0.6}},
+			[]gkit.PointF{{X: -0.8, Y: 0.8}, {X: 11.1, Y: 0}}},
+		{"default width", line, gkit.StrokeStyle{},
+			[]gkit.PointF{{X: 5, Y: 0.4}},
+			[]gkit.PointF{{X: 5, Y: 0.6}}},
+		{"miter join", corner, gkit.StrokeStyle{Width: 2, Join: gkit.JoinMiter},
+			[]gkit.PointF{{X: 10.9, Y: -0.9}, {X: 9.1, Y: 9}},
+			[]gkit.PointF{{X: 11.1, Y: -0.9}, {X: 8.9, Y: 5}}},
+		{"bevel join", corner, gkit.StrokeStyle{Width: 2, Join: gkit.JoinBevel},
+			[]gkit.PointF{{X: 10.4, Y: -0.4}},
+			[]gkit.PointF{{X: 10.6, Y: -0.6}}},
+		{"round join", corner, gkit.StrokeStyle{Width: 2, Join: gkit.JoinRound},
+			[]gkit.PointF{{X: 10.6, Y: -0.6}},
+			[]gkit.PointF{{X: 10.8, Y: -0.8}}},
+		// The miter of the sharp turn is longer than the default limit of
+		// 4, so it is beveled.
+		{"miter limit", sharp, gkit.StrokeStyle{Width: 2},
+			[]gkit.PointF{{X: 5, Y: 0.5}},
+			[]gkit.PointF{{X: 12, Y: -0.2}, {X: 15, Y: -0.5}}},
+		{"miter under limit", sharp, gkit.StrokeStyle{Width: 2, MiterLimit: 11},
+			[]gkit.PointF{{X: 12, Y: -0.2}, {X: 18, Y: -0.7}},
+			[]gkit.PointF{{X: 20.5, Y: -1}}},
+		// Closed subpaths join at the start and have no caps.
+		{"closed", &closed, gkit.StrokeStyle{Width: 2, Cap: gkit.CapSquare},
+			[]gkit.PointF{{X: -0.9, Y: -0.9}, {X: 10.9, Y: 10.9}},
+			[]gkit.PointF{{X: 5, Y: 5}, {X: -1.1, Y: 0}, {X: 0, Y: -1.1}}},
+	} {
+		polygons := test.path.Stroke(test.style, gkit.Identity(), gkit.PathTolerance)
+		for _, p := range test.inside {
+			if !covers(polygons, p) {
+				t.Errorf("%s: %v isn't stroked", test.name, p)
+			}
+		}
+		for _, p := range test.outside {
+			if covers(polygons, p) {
+				t.Errorf("%s: %v is stroked", test.name, p)
+			}
+		}
+	}
+}
+
+func TestStrokeTransform(t *testing.T) {
+	// The stroke is scaled with the path.
+	line := polyline(gkit.PointF{X: 0, Y: 0}, gkit.PointF{X: 10, Y: 0})
+	polygons := line.Stroke(gkit.StrokeStyle{Width: 2}, gkit.Translation(5, 5).Mul(gkit.Scaling(3, 3)), gkit.PathTolerance)
+	for _, test := range []struct {
+		point gkit.PointF
+		want  bool
+	}{
+		{gkit.PointF{X: 20, Y: 7.9}, true},
+		{gkit.PointF{X: 20, Y: 8.1}, false},
+		{gkit.PointF{X: 34.9, Y: 5}, true},
+		{gkit.PointF{X: 35.1, Y: 5}, false},
+	} {
+		if got := covers(polygons, test.point); got != test.want {
+			t.Errorf("covers(%v) = %v, want %v", test.point, got, test.want)
+		}
+	}
+}
+
+// dashes returns the spans of x the polygons of a horizontal stroke with
+// butt caps cover.
+func dashes(polygons [][]gkit.PointF) [][2]float32 {
+	var spans [][2]float32
+	for _, polygon := range polygons {
+		span := [2]float32{float32(math.Inf(1)), float32(math.Inf(-1))}
+		for _, p := range polygon {
+			span[0] = float32(math.Min(float64(span[0]), float64(p.X)))
+			span[1] = float32(math.Max(float64(span[1]), float64(p.X)))
+		}
+		spans = append(spans, span)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	return spans
+}
+
+func TestStrokeDashes(t *testing.T) {
+	line := polyline(gkit.PointF{X: 0, Y: 0}, gkit.PointF{X: 4, Y: 0}, gkit.PointF{X: 10, Y: 0})
+	for _, test := range []struct {
+		name    string
+		pattern []float32
+		offset  float32
+		want    [][2]float32
+	}{
+		{"even", []float32{3, 1}, 0, [][2]float32{{0, 3}, {4, 7}, {8, 10}}},
+		// Odd patterns repeat once more, swapping dashes and gaps.
+		{"odd", []float32{2}, 0, [][2]float32{{0, 2}, {4, 6}, {8, 10}}},
+		{"odd of three", []float32{3, 1, 2}, 0, [][2]float32{{0, 3}, {4, 6}, {9, 10}}},
+		// Dashes over a vertex are stroked a segment at a time.
+		{"offset", []float32{2, 2}, 1, [][2]float32{{0, 1}, {3, 4}, {4, 5}, {7, 9}}},
+		// Negative offsets start before the pattern, it repeats there.
+		{"negative offset", []float32{2, 2}, -1, [][2]float32{{1, 3}, {5, 7}, {9, 10}}},
+		{"negative offset of more than the pattern", []float32{2, 2}, -5, [][2]float32{{1, 3}, {5, 7}, {9, 10}}},
+		// Patterns that can't be drawn draw solid lines.
+		{"negative dash", []float32{2, -1}, 0, [][2]float32{{0, 4}, {4, 10}}},
+		{"zero length", []float32{0, 0}, 0, [][2]float32{{0, 4}, {4, 10}}},
+	} {
+		style := gkit.StrokeStyle{Width: 2, Dashes: test.pattern, DashOffset: test.offset}
+		got := dashes(line.Stroke(style, gkit.Identity(), gkit.PathTolerance))
+		if len(got) != len(test.want) {
+			t.Errorf("%s: dashes = %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(float64(got[i][0]-test.want[i][0])) > 1e-4 || math.Abs(float64(got[i][1]-test.want[i][1])) > 1e-4 {
+				t.Errorf("%s: dashes = %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package gkit
+
+import (
+	"math"
+)
+
+// Transform is a 2D affine transform. It maps (x, y) to
+// (A*x + C*y + E, B*x + D*y + F), the matrix(a, b, c, d, e, f) of CSS and SVG.
+type Transform struct {
+	A, B, C, D, E, F float32
+}
+
+func Identity() Transform {
+	return Transform{A: 1, D: 1}
+}
+
+func Translation(x, y float32) Transform {
+	return Transform{A: 1, D: 1, E: x, F: y}
+}
+
+func Scaling(sx, sy float32) Transform {
+	return Transform{A: sx, D: sy}
+}
+
+// Rotation rotates by angle radians, clockwise as y grows downwards.
+func Rotation(angle float32) Transform {
+	sin, cos := math.Sincos(float64(angle))
+	return Transform{A: float32(cos), B: float32(sin), C: float32(-sin), D: float32(cos)}
+}
+
+// Skewing skews along the x axis by ax and along the y axis by ay radians.
+func Skewing(ax, ay float32) Transform {
+	return Transform{
+		A: 1,
+		B: float32(math.Tan(float64(ay))),
+		C: float32(math.Tan(float64(ax))),
+		D: 1,
+	}
+}
+
+// Mul returns the transform applying t2 and then t.
+func (t Transform) Mul(t2 Transform) Transform {
+	return Transform{
+		A: t.A*t2.A + t.C*t2.B,
+		B: t.B*t2.A + t.D*t2.B,
+		C: t.A*t2.C + t.C*t2.D,
+		D: t.B*t2.C + t.D*t2.D,
+		E: t.A*t2.E + t.C*t2.F + t.E,
+		F: t.B*t2.E + t.D*t2.F + t.F,
+	}
+}
+
+func (t Transform) Apply(p PointF) PointF {
+	return PointF{
+		X: t.A*p.X + t.C*p.Y + t.E,
+		Y: t.B*p.X + t.D*p.Y + t.F,
+	}
+}
+
+// Invert returns the inverse of t, false if t collapses the plane and has
+// none.
+func (t Transform) Invert() (Transform, bool) {
+	det := t.A*t.D - t.B*t.C
+	if det == 0 {
+		return Transform{}, false
+	}
+	return Transform{
+		A: t.D / det,
+		B: -t.B / det,
+		C: -t.C / det,
+		D: t.A / det,
+		E: (t.C*t.F - t.D*t.E) / det,
+		F: (t.B*t.E - t.A*t.F) / det,
+	}, true
+}
+
+func (t Transform) IsIdentity() bool {
+	return t == Identity()
+}
+
+// IsAxisAligned reports whether t maps rects to rects: it only translates,
+// scales and mirrors.
+func (t Transform) IsAxisAligned() bool {
+	return t.B == 0 && t.C == 0
+}
+
+// Scale returns the factor t scales areas by, as a length.
+func (t Transform) Scale() float32 {
+	return float32(math.Sqrt(math.Abs(float64(t.A*t.D - t.B*t.C))))
+}
+
+// BoundingBox returns the smallest rect containing r mapped by t.
+func (t Transform) BoundingBox(r RectF) RectF {
+	rb := r.RightBottom()
+	corners := [4]PointF{
+		t.Apply(r.PointF),
+		t.Apply(PointF{rb.X, r.Y}),
+		t.Apply(rb),
+		t.Apply(PointF{r.X, rb.Y}),
+	}
+	leftTop, rightBottom := corners[0], corners[0]
+	for _, c := range corners[1:] {
+		leftTop = PointF{min32(leftTop.X, c.X), min32(leftTop.Y, c.Y)}
+		rightBottom = PointF{max32(rightBottom.X, c.X), max32(rightBottom.Y, c.Y)}
+	}
+	return RectFromCorners(leftTop, rightBottom)
+}
+
+// TransformStack implements the transform operations of Painter. Painters
+// embed it and map what they draw with Transform.
+type TransformStack struct {
+	current Transform
+	saved   []Transform
+	// set is false until the first operation, the zero TransformStack is
+	// the identity.
+	set bool
+}
+
+func (s *TransformStack) Transform() Transform {
+	if !s.set {
+		return Identity()
+	}
+	return s.current
+}
+
+func (s *TransformStack) Save() {
+	s.saved = append(s.saved, s.Transform())
+}
+
+func (s *TransformStack) Restore() {
+	if n := len(s.saved); n > 0 {
+		s.current, s.set = s.saved[n-1], true
+		s.saved = s.saved[:n-1]
+	}
+}
+
+// ApplyTransform makes t apply to everything drawn afterwards before the
+// current transform.
+func (s *TransformStack) ApplyTransform(t Transform) {
+	s.current, s.set = s.Transform().Mul(t), true
+}
+
+func (s *TransformStack) Translate(x, y float32) {
+	s.ApplyTransform(Translation(x, y))
+}
+
+func (s *TransformStack) Scale(sx, sy float32) {
+	s.ApplyTransform(Scaling(sx, sy))
+}
+
+func (s *TransformStack) Rotate(angle float32) {
+	s.ApplyTransform(Rotation(angle))
+}
+
+func (s *TransformStack) Skew(ax, ay float32) {
+	s.ApplyTransform(Skewing(ax, ay))
+}
@@ -18,6 +18,8 @@ type View interface {
 	SetBorders(v SideValues)
 	Borders() SideValues
 	Bounds() Rect
+	Transform() Transform
+	Opacity() float32
 
 	PropagateLayout()
 	Layout()
@@ -29,3 +31,10 @@ type View interface {
 
 	Layer
 }
+
+// Scroller is implemented by views that draw their children shifted by a
+// content offset and clipped to their Bounds, like controls.ScrollView.
+// Hit testing and event coordinates take the offset into account.
+type Scroller interface {
+	ContentOffset() Point
+}
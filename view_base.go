@@ -15,6 +15,16 @@ type ViewBase struct {
 	minSize  Size
 	prefSize Size
 	maxSize  Size
+
+	focusable bool
+	focused   bool
+	tabIndex  int
+
+	transform    Transform
+	hasTransform bool
+
+	// transparency is 1 - opacity, so that views are opaque by default.
+	transparency float32
 }
 
 func (v *ViewBase) AddChild(view View) {
@@ -65,7 +75,26 @@ func (v *ViewBase) Frame() Rect {
 func (v *ViewBase) PropagateDraw(p Painter) {
 	for _, child := range v.children {
 		frame := child.Frame()
-		p.DrawLayer(frame, child)
+		opacity := child.Opacity()
+		if opacity <= 0 {
+			continue
+		}
+		draw := func(r Rect) {
+			if opacity < 1 {
+				p.DrawCompositedLayer(r, child, opacity, BlendNormal)
+			} else {
+				p.DrawLayer(r, child)
+			}
+		}
+		if t := child.Transform(); !t.IsIdentity() {
+			p.Save()
+			p.Translate(float32(frame.X), float32(frame.Y))
+			p.ApplyTransform(t)
+			draw(Rect{Size: frame.Size})
+			p.Restore()
+			continue
+		}
+		draw(frame)
 	}
 	v.needsRedraw = false
 }
@@ -76,10 +105,28 @@ func (v *ViewBase) PropagateLayout() {
 		v.needsLayout = false
 		v.prefSizeChanged = true
 	}
+	if v.propagateLayoutToChildren() {
+		// The preferred size of a child depends on the size it was given,
+		// like the height of a wrapped label. Size the view again and lay
+		// it out a second time in the same pass.
+		v.View.UpdateSizes()
+		v.View.Layout()
+		v.needsLayout = false
+		v.propagateLayoutToChildren()
+	}
+}
+
+// propagateLayoutToChildren lays the children out and reports whether any
+// of them changed its preferred size while doing so.
+func (v *ViewBase) propagateLayoutToChildren() bool {
+	changed := false
 	for _, child := range v.children {
+		pref := child.PrefSize()
 		child.PropagateLayout()
+		changed = changed || child.PrefSize() != pref
 		v.needsRedraw = v.needsRedraw || child.NeedsRedraw()
 	}
+	return changed
 }
 
 func (v *ViewBase) SetOrigin(p Point) {
@@ -170,3 +217,62 @@ func (v *ViewBase) Borders() SideValues {
 func (v *ViewBase) Bounds() Rect {
 	return Rect{Size: v.Size()}.Inset(v.Borders())
 }
+
+func (v *ViewBase) SetFocusable(focusable bool) {
+	v.focusable = focusable
+}
+
+func (v *ViewBase) Focusable() bool {
+	return v.focusable
+}
+
+func (v *ViewBase) SetTabIndex(index int) {
+	v.tabIndex = index
+}
+
+func (v *ViewBase) TabIndex() int {
+	return v.tabIndex
+}
+
+func (v *ViewBase) SetFocused(focused bool) {
+	if v.focused != focused {
+		v.focused = focused
+		v.SetNeedsRedraw()
+	}
+}
+
+func (v *ViewBase) Focused() bool {
+	return v.focused
+}
+
+// SetTransform makes the view draw mapped by t, which applies in the
+// coordinates of the view: (0, 0) is the top-left corner of its frame.
+func (v *ViewBase) SetTransform(t Transform) {
+	if v.Transform() != t {
+		v.transform = t
+		v.hasTransform = true
+		v.SetNeedsRedraw()
+	}
+}
+
+func (v *ViewBase) Transform() Transform {
+	if !v.hasTransform {
+		return Identity()
+	}
+	return v.transform
+}
+
+// SetOpacity makes the view and its children draw as a group with its
+// alpha multiplied by opacity, clamped to [0, 1]. Views with zero opacity
+// aren't drawn.
+func (v *ViewBase) SetOpacity(opacity float32) {
+	transparency := 1 - min32(max32(opacity, 0), 1)
+	if v.transparency != transparency {
+		v.transparency = transparency
+		v.SetNeedsRedraw()
+	}
+}
+
+func (v *ViewBase) Opacity() float32 {
+	return 1 - v.transparency
+}
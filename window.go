@@ -16,4 +16,7 @@ type Window interface {
 	Maximize() error
 	ShouldClose() bool
 	Size() Size
+	DispatchEvent(e Event)
+	Focus() View
+	SetFocus(View)
 }